@@ -0,0 +1,24 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/AlejandroJorge/forum-rest-api/config"
+	"github.com/AlejandroJorge/forum-rest-api/repository"
+	"github.com/AlejandroJorge/forum-rest-api/service"
+)
+
+// Recomputes every denormalized counter and reports the rows that had drifted
+func main() {
+	config.InitializeAll()
+
+	serv := service.NewCounterService(repository.NewSQLiteCounterRepository(config.SQLiteDatabase()))
+	drift, err := serv.Reconcile()
+	if err != nil {
+		fmt.Println("Couldn't reconcile counters:", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Repaired counters of %d posts, %d comments and %d profiles\n", drift.Posts, drift.Comments, drift.Profiles)
+}
@@ -3,19 +3,41 @@ package main
 import (
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/AlejandroJorge/forum-rest-api/config"
 	"github.com/AlejandroJorge/forum-rest-api/delivery/router"
+	"github.com/AlejandroJorge/forum-rest-api/events"
+	"github.com/AlejandroJorge/forum-rest-api/jobs"
 	"github.com/AlejandroJorge/forum-rest-api/logging"
+	"github.com/AlejandroJorge/forum-rest-api/repository"
+	"github.com/AlejandroJorge/forum-rest-api/service"
 )
 
 func main() {
 	config.InitializeAll()
 	logging.LogSetup()
 
-	port := config.GetParams().Port
-	router := router.AppRouter(config.SQLiteDatabase())
+	params := config.GetParams()
+	db := config.SQLiteDatabase()
+	router := router.AppRouter(db)
 
-	http.ListenAndServe(fmt.Sprintf(":%d", port), router)
+	userRepo := repository.NewSQLiteUserRepository(db)
+	postRepo := repository.NewSQLitePostRepository(db)
+	profileRepo := repository.NewSQLiteProfileRepository(db)
+	notificationRepo := repository.NewSQLiteNotificationRepository(db)
+	postServ := service.NewPostService(postRepo, userRepo, repository.NewSQLiteCategoryRepository(db), profileRepo, repository.NewSQLiteMediaRepository(db), notificationRepo, events.DefaultBus())
+	commentServ := service.NewCommentService(repository.NewSQLiteCommentRepository(db), userRepo, postRepo, profileRepo, notificationRepo, events.DefaultBus())
+	jobs.StartPurge(postServ, commentServ,
+		time.Duration(params.PurgeIntervalMinutes)*time.Minute,
+		time.Duration(params.DeletedRetentionDays)*24*time.Hour)
+	jobs.StartRankingRefresh(postServ, time.Duration(params.RankingRefreshMinutes)*time.Minute)
+	if params.ArchiveAfterDays > 0 {
+		jobs.StartArchive(postServ,
+			time.Duration(params.ArchiveIntervalMinutes)*time.Minute,
+			time.Duration(params.ArchiveAfterDays)*24*time.Hour)
+	}
+
+	http.ListenAndServe(fmt.Sprintf(":%d", params.Port), router)
 
 }
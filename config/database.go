@@ -34,16 +34,285 @@ func initializeSQLiteDatabase() {
 
 	dbPath := path.Join(folderPath, fileName)
 
-	connectionStr := "file:" + dbPath + "?_journal=WAL&_foreign_keys=true"
+	connectionStr := "file:" + dbPath + "?_journal=WAL&_foreign_keys=true&_txlock=immediate"
 	newDB, err := sql.Open("sqlite3", connectionStr)
 	util.PanicIfError(err)
 
 	sqliteDB = newDB
 }
 
+var searchEnabled = false
+
+// Reports whether the full-text search tables exist, SQLite must be built with the sqlite_fts5 tag
+func SearchEnabled() bool {
+	return searchEnabled
+}
+
 // Runs the migration script(s), panics if it fails
+// Migrations run in the order their columns were introduced, backfills like counters.sql read the columns of the earlier ones
 func runSQLiteMigration() {
 	mustRunSQLiteScript("schema.sql")
+	mustRunSQLiteScript("default_category.sql")
+	runSQLiteSoftDeleteMigration()
+	runSQLiteThreadsMigration()
+	runSQLiteCommentDatesMigration()
+	runSQLiteSlugsMigration()
+	runSQLiteCategoriesMigration()
+	runSQLiteVotesMigration()
+	runSQLiteCountersMigration()
+	runSQLiteLastActivityMigration()
+	runSQLiteEntitiesMigration()
+	runSQLiteContentHTMLMigration()
+	runSQLitePrivateProfilesMigration()
+	runSQLiteModerationMigration()
+	runSQLitePostStatesMigration()
+	runSQLiteMessageBlocksMigration()
+	runSQLiteSearchMigration()
+}
+
+// Adds the soft deletion of posts and comments and the moderator flag of users to databases created before they existed
+func runSQLiteSoftDeleteMigration() {
+	var existing int
+	err := SQLiteDatabase().QueryRow(`SELECT COUNT(*) FROM pragma_table_info('Post') WHERE name = 'Deleted_At'`).Scan(&existing)
+	util.PanicIfError(err)
+
+	if existing == 0 {
+		mustRunSQLiteScript("soft_delete.sql")
+	}
+}
+
+// Adds the reply threads of comments to databases created before they existed, every existing comment stays top level
+func runSQLiteThreadsMigration() {
+	var existing int
+	err := SQLiteDatabase().QueryRow(`SELECT COUNT(*) FROM pragma_table_info('Comment') WHERE name = 'Parent_ID'`).Scan(&existing)
+	util.PanicIfError(err)
+
+	if existing == 0 {
+		mustRunSQLiteScript("threads.sql")
+	}
+}
+
+// Adds the timestamps of comments to databases created before they existed, existing comments are dated like their post
+func runSQLiteCommentDatesMigration() {
+	var existing int
+	err := SQLiteDatabase().QueryRow(`SELECT COUNT(*) FROM pragma_table_info('Comment') WHERE name = 'Creation_Date'`).Scan(&existing)
+	util.PanicIfError(err)
+
+	if existing == 0 {
+		mustRunSQLiteScript("comment_dates.sql")
+	}
+}
+
+// Gives posts of databases created before slugs existed a slug and drops the uniqueness of their titles
+func runSQLiteSlugsMigration() {
+	var existing int
+	err := SQLiteDatabase().QueryRow(`SELECT COUNT(*) FROM pragma_table_info('Post') WHERE name = 'Slug'`).Scan(&existing)
+	util.PanicIfError(err)
+
+	if existing == 0 {
+		mustRunSQLiteScript("slugs.sql")
+		fillStoredSlugs()
+	}
+}
+
+// Replaces the placeholder slugs left by slugs.sql with ones made from the titles, older posts keep the plain slug
+func fillStoredSlugs() {
+	db := SQLiteDatabase()
+
+	rows, err := db.Query(`SELECT Post_ID, Title FROM Post ORDER BY Post_ID`)
+	util.PanicIfError(err)
+
+	taken := make(map[string]bool)
+	slugs := make(map[uint]string)
+	for rows.Next() {
+		var id uint
+		var title string
+		err = rows.Scan(&id, &title)
+		util.PanicIfError(err)
+
+		base := util.Slugify(title)
+		slug := base
+		for suffix := 2; taken[slug]; suffix++ {
+			slug = fmt.Sprintf("%s-%d", base, suffix)
+		}
+		taken[slug] = true
+		slugs[id] = slug
+	}
+	util.PanicIfError(rows.Err())
+	rows.Close()
+
+	tx, err := db.Begin()
+	util.PanicIfError(err)
+	defer tx.Rollback()
+
+	for id, slug := range slugs {
+		_, err = tx.Exec(`UPDATE Post SET Slug = ? WHERE Post_ID = ?`, slug, id)
+		util.PanicIfError(err)
+	}
+
+	util.PanicIfError(tx.Commit())
+}
+
+// Adds the category of posts to databases created before categories existed, existing posts go to the first top level category
+func runSQLiteCategoriesMigration() {
+	var existing int
+	err := SQLiteDatabase().QueryRow(`SELECT COUNT(*) FROM pragma_table_info('Post') WHERE name = 'Category_ID'`).Scan(&existing)
+	util.PanicIfError(err)
+
+	if existing == 0 {
+		mustRunSQLiteScript("categories.sql")
+	}
+}
+
+// Turns the likes of databases created before votes existed into upvotes
+func runSQLiteVotesMigration() {
+	var existing int
+	err := SQLiteDatabase().QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE name = 'Post_Likings'`).Scan(&existing)
+	util.PanicIfError(err)
+
+	if existing != 0 {
+		mustRunSQLiteScript("likes_to_votes.sql")
+	}
+}
+
+// Adds the counter columns to databases created before they existed and fills them
+func runSQLiteCountersMigration() {
+	var existing int
+	err := SQLiteDatabase().QueryRow(`SELECT COUNT(*) FROM pragma_table_info('Post') WHERE name = 'Upvotes'`).Scan(&existing)
+	util.PanicIfError(err)
+
+	if existing == 0 {
+		mustRunSQLiteScript("counters.sql")
+	}
+}
+
+// Adds the last activity of posts to databases created before it existed and fills it
+func runSQLiteLastActivityMigration() {
+	var existing int
+	err := SQLiteDatabase().QueryRow(`SELECT COUNT(*) FROM pragma_table_info('Post') WHERE name = 'Last_Activity'`).Scan(&existing)
+	util.PanicIfError(err)
+
+	if existing == 0 {
+		mustRunSQLiteScript("last_activity.sql")
+	}
+}
+
+// Adds the mentions and hashtags of posts and comments to databases created before they existed, older content has none until it's edited
+func runSQLiteEntitiesMigration() {
+	var existing int
+	err := SQLiteDatabase().QueryRow(`SELECT COUNT(*) FROM pragma_table_info('Post') WHERE name = 'Entities'`).Scan(&existing)
+	util.PanicIfError(err)
+
+	if existing == 0 {
+		mustRunSQLiteScript("entities.sql")
+	}
+}
+
+// Adds the rendered HTML of posts and comments to databases created before it existed and renders the existing content once
+func runSQLiteContentHTMLMigration() {
+	var existing int
+	err := SQLiteDatabase().QueryRow(`SELECT COUNT(*) FROM pragma_table_info('Post') WHERE name = 'Content_HTML'`).Scan(&existing)
+	util.PanicIfError(err)
+
+	if existing == 0 {
+		mustRunSQLiteScript("content_html.sql")
+		renderStoredContent("Post", "Post_ID")
+		renderStoredContent("Comment", "Comment_ID")
+	}
+}
+
+// Fills Content_HTML for every row of the table from its Markdown content
+func renderStoredContent(table, idColumn string) {
+	db := SQLiteDatabase()
+
+	rows, err := db.Query(`SELECT ` + idColumn + `, Content FROM ` + table)
+	util.PanicIfError(err)
+
+	rendered := make(map[uint]string)
+	for rows.Next() {
+		var id uint
+		var content string
+		err = rows.Scan(&id, &content)
+		util.PanicIfError(err)
+
+		rendered[id] = util.RenderMarkdown(content)
+	}
+	util.PanicIfError(rows.Err())
+	rows.Close()
+
+	tx, err := db.Begin()
+	util.PanicIfError(err)
+	defer tx.Rollback()
+
+	for id, contentHTML := range rendered {
+		_, err = tx.Exec(`UPDATE `+table+` SET Content_HTML = ? WHERE `+idColumn+` = ?`, contentHTML, id)
+		util.PanicIfError(err)
+	}
+
+	util.PanicIfError(tx.Commit())
+}
+
+// Adds the privacy flag to profiles of databases created before it existed, every existing profile stays public
+func runSQLitePrivateProfilesMigration() {
+	var existing int
+	err := SQLiteDatabase().QueryRow(`SELECT COUNT(*) FROM pragma_table_info('Profile') WHERE name = 'Is_Private'`).Scan(&existing)
+	util.PanicIfError(err)
+
+	if existing == 0 {
+		mustRunSQLiteScript("private_profiles.sql")
+	}
+}
+
+// Adds the suspensions of users and the moderation marks of posts and comments to databases created before they existed
+func runSQLiteModerationMigration() {
+	var existing int
+	err := SQLiteDatabase().QueryRow(`SELECT COUNT(*) FROM pragma_table_info('User') WHERE name = 'Suspended_Until'`).Scan(&existing)
+	util.PanicIfError(err)
+
+	if existing == 0 {
+		mustRunSQLiteScript("moderation.sql")
+	}
+}
+
+// Adds the locked, pinned and archived states of posts to databases created before they existed, every existing post stays open
+func runSQLitePostStatesMigration() {
+	var existing int
+	err := SQLiteDatabase().QueryRow(`SELECT COUNT(*) FROM pragma_table_info('Post') WHERE name = 'Is_Locked'`).Scan(&existing)
+	util.PanicIfError(err)
+
+	if existing == 0 {
+		mustRunSQLiteScript("post_states.sql")
+	}
+}
+
+// Turns the blocks from messaging of databases that had them into profile blocks
+func runSQLiteMessageBlocksMigration() {
+	var existing int
+	err := SQLiteDatabase().QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'Message_Blocks'`).Scan(&existing)
+	util.PanicIfError(err)
+
+	if existing != 0 {
+		mustRunSQLiteScript("message_blocks.sql")
+	}
+}
+
+// Creates the full-text search tables and indexes existing rows the first time, search stays disabled without FTS5
+func runSQLiteSearchMigration() {
+	var existing int
+	err := SQLiteDatabase().QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE name = 'Post_Search'`).Scan(&existing)
+	util.PanicIfError(err)
+
+	err = runSQLiteScript("search.sql")
+	if err != nil {
+		fmt.Println("[WARNING] Full-text search disabled, SQLite was built without FTS5")
+		return
+	}
+
+	if existing == 0 {
+		mustRunSQLiteScript("search_rebuild.sql")
+	}
+
+	searchEnabled = true
 }
 
 // Runs a SQL script in sql/ folder
@@ -33,6 +33,20 @@ func getEnvUint(key string) (uint, bool) {
 	return uint(parsed), true
 }
 
+func getEnvFloat(key string) (float64, bool) {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return 0, false
+	}
+
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return parsed, true
+}
+
 func getEnvString(key string) (string, bool) {
 	return os.LookupEnv(key)
 }
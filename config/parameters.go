@@ -1,20 +1,66 @@
 package config
 
 type Parameters struct {
-	DbFileName   string
-	DbFolderName string
-	Port         uint
-	AuthSecret   []byte
+	DbFileName             string
+	DbFolderName           string
+	Port                   uint
+	AuthSecret             []byte
+	DeletedRetentionDays   uint
+	PurgeIntervalMinutes   uint
+	MaxCommentDepth        uint
+	MaxTagsPerPost         uint
+	PageSize               uint
+	RankingGravity         float64
+	RankingCommentWeight   float64
+	RankingRefreshMinutes  uint
+	RisingWindowHours      uint
+	StreamHeartbeatSeconds uint
+	StreamReplaySize       uint
+	WSMaxSubscriptions     uint
+	MediaStore             string
+	MediaFolderName        string
+	S3Endpoint             string
+	S3Bucket               string
+	S3Region               string
+	S3AccessKeyID          string
+	S3SecretAccessKey      string
+	MaxUploadBytes         uint
+	ThumbnailSize          uint
+	MaxAttachmentsPerPost  uint
+	MaxConversationMembers uint
+	ArchiveAfterDays       uint
+	ArchiveIntervalMinutes uint
 }
 
 var params Parameters
 var isParamsInitialized = false
 
 var defaultParams Parameters = Parameters{
-	DbFolderName: "data",
-	DbFileName:   "database.sqlite",
-	Port:         3000,
-	AuthSecret:   []byte("weaksecret"),
+	DbFolderName:           "data",
+	DbFileName:             "database.sqlite",
+	Port:                   3000,
+	AuthSecret:             []byte("weaksecret"),
+	DeletedRetentionDays:   30,
+	PurgeIntervalMinutes:   60,
+	MaxCommentDepth:        5,
+	MaxTagsPerPost:         5,
+	PageSize:               20,
+	RankingGravity:         1.8,
+	RankingCommentWeight:   0.5,
+	RankingRefreshMinutes:  5,
+	RisingWindowHours:      24,
+	StreamHeartbeatSeconds: 15,
+	StreamReplaySize:       256,
+	WSMaxSubscriptions:     20,
+	MediaStore:             "local",
+	MediaFolderName:        "media",
+	S3Region:               "us-east-1",
+	MaxUploadBytes:         5 << 20,
+	ThumbnailSize:          320,
+	MaxAttachmentsPerPost:  4,
+	MaxConversationMembers: 8,
+	ArchiveAfterDays:       180,
+	ArchiveIntervalMinutes: 60,
 }
 
 func GetParams() Parameters {
@@ -39,6 +85,73 @@ func initializeConfigParameters() {
 	if params.AuthSecret, ok = getEnvBytes("AUTH_SECRET"); !ok {
 		params.AuthSecret = defaultParams.AuthSecret
 	}
+	if params.DeletedRetentionDays, ok = getEnvUint("DELETED_RETENTION_DAYS"); !ok {
+		params.DeletedRetentionDays = defaultParams.DeletedRetentionDays
+	}
+	if params.PurgeIntervalMinutes, ok = getEnvUint("PURGE_INTERVAL_MINUTES"); !ok || params.PurgeIntervalMinutes == 0 {
+		params.PurgeIntervalMinutes = defaultParams.PurgeIntervalMinutes
+	}
+	if params.MaxCommentDepth, ok = getEnvUint("MAX_COMMENT_DEPTH"); !ok {
+		params.MaxCommentDepth = defaultParams.MaxCommentDepth
+	}
+	if params.MaxTagsPerPost, ok = getEnvUint("MAX_TAGS_PER_POST"); !ok {
+		params.MaxTagsPerPost = defaultParams.MaxTagsPerPost
+	}
+	if params.PageSize, ok = getEnvUint("PAGE_SIZE"); !ok || params.PageSize == 0 {
+		params.PageSize = defaultParams.PageSize
+	}
+	if params.RankingGravity, ok = getEnvFloat("RANKING_GRAVITY"); !ok || params.RankingGravity <= 0 {
+		params.RankingGravity = defaultParams.RankingGravity
+	}
+	if params.RankingCommentWeight, ok = getEnvFloat("RANKING_COMMENT_WEIGHT"); !ok || params.RankingCommentWeight < 0 {
+		params.RankingCommentWeight = defaultParams.RankingCommentWeight
+	}
+	if params.RankingRefreshMinutes, ok = getEnvUint("RANKING_REFRESH_MINUTES"); !ok || params.RankingRefreshMinutes == 0 {
+		params.RankingRefreshMinutes = defaultParams.RankingRefreshMinutes
+	}
+	if params.RisingWindowHours, ok = getEnvUint("RISING_WINDOW_HOURS"); !ok || params.RisingWindowHours == 0 {
+		params.RisingWindowHours = defaultParams.RisingWindowHours
+	}
+	if params.StreamHeartbeatSeconds, ok = getEnvUint("STREAM_HEARTBEAT_SECONDS"); !ok || params.StreamHeartbeatSeconds == 0 {
+		params.StreamHeartbeatSeconds = defaultParams.StreamHeartbeatSeconds
+	}
+	if params.StreamReplaySize, ok = getEnvUint("STREAM_REPLAY_SIZE"); !ok {
+		params.StreamReplaySize = defaultParams.StreamReplaySize
+	}
+	if params.WSMaxSubscriptions, ok = getEnvUint("WS_MAX_SUBSCRIPTIONS"); !ok || params.WSMaxSubscriptions == 0 {
+		params.WSMaxSubscriptions = defaultParams.WSMaxSubscriptions
+	}
+	if params.MediaStore, ok = getEnvString("MEDIA_STORE"); !ok || (params.MediaStore != "local" && params.MediaStore != "s3") {
+		params.MediaStore = defaultParams.MediaStore
+	}
+	if params.MediaFolderName, ok = getEnvString("MEDIA_FOLDER_NAME"); !ok || params.MediaFolderName == "" {
+		params.MediaFolderName = defaultParams.MediaFolderName
+	}
+	params.S3Endpoint, _ = getEnvString("S3_ENDPOINT")
+	params.S3Bucket, _ = getEnvString("S3_BUCKET")
+	if params.S3Region, ok = getEnvString("S3_REGION"); !ok || params.S3Region == "" {
+		params.S3Region = defaultParams.S3Region
+	}
+	params.S3AccessKeyID, _ = getEnvString("S3_ACCESS_KEY_ID")
+	params.S3SecretAccessKey, _ = getEnvString("S3_SECRET_ACCESS_KEY")
+	if params.MaxUploadBytes, ok = getEnvUint("MAX_UPLOAD_BYTES"); !ok || params.MaxUploadBytes == 0 {
+		params.MaxUploadBytes = defaultParams.MaxUploadBytes
+	}
+	if params.ThumbnailSize, ok = getEnvUint("THUMBNAIL_SIZE"); !ok || params.ThumbnailSize == 0 {
+		params.ThumbnailSize = defaultParams.ThumbnailSize
+	}
+	if params.MaxAttachmentsPerPost, ok = getEnvUint("MAX_ATTACHMENTS_PER_POST"); !ok {
+		params.MaxAttachmentsPerPost = defaultParams.MaxAttachmentsPerPost
+	}
+	if params.MaxConversationMembers, ok = getEnvUint("MAX_CONVERSATION_MEMBERS"); !ok || params.MaxConversationMembers < 2 {
+		params.MaxConversationMembers = defaultParams.MaxConversationMembers
+	}
+	if params.ArchiveAfterDays, ok = getEnvUint("ARCHIVE_AFTER_DAYS"); !ok {
+		params.ArchiveAfterDays = defaultParams.ArchiveAfterDays
+	}
+	if params.ArchiveIntervalMinutes, ok = getEnvUint("ARCHIVE_INTERVAL_MINUTES"); !ok || params.ArchiveIntervalMinutes == 0 {
+		params.ArchiveIntervalMinutes = defaultParams.ArchiveIntervalMinutes
+	}
 
 	isParamsInitialized = true
 }
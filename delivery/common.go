@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/AlejandroJorge/forum-rest-api/logging"
 	"github.com/gorilla/mux"
@@ -35,6 +36,11 @@ func WriteResponse(w http.ResponseWriter, statusCode int, message string) {
 	logging.LogRawResponse(statusCode, message)
 }
 
+func WriteRedirect(w http.ResponseWriter, r *http.Request, location string) {
+	http.Redirect(w, r, location, http.StatusMovedPermanently)
+	logging.LogRawResponse(http.StatusMovedPermanently, location)
+}
+
 func ParseUintParam(r *http.Request, key string) (uint, error) {
 	params := mux.Vars(r)
 	valueStr, ok := params[key]
@@ -59,3 +65,28 @@ func ParseStringParam(r *http.Request, key string) (string, error) {
 
 	return value, nil
 }
+
+// Returns defaultValue when the query parameter is missing
+func ParseUintQuery(r *http.Request, key string, defaultValue uint) (uint, error) {
+	valueStr := r.URL.Query().Get(key)
+	if valueStr == "" {
+		return defaultValue, nil
+	}
+
+	value, err := strconv.ParseUint(valueStr, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint(value), nil
+}
+
+// Expects a YYYY-MM-DD date and returns the zero time when the query parameter is missing
+func ParseDateQuery(r *http.Request, key string) (time.Time, error) {
+	valueStr := r.URL.Query().Get(key)
+	if valueStr == "" {
+		return time.Time{}, nil
+	}
+
+	return time.Parse(time.DateOnly, valueStr)
+}
@@ -0,0 +1,271 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/AlejandroJorge/forum-rest-api/delivery"
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/service"
+)
+
+type CategoryController interface {
+	Create(w http.ResponseWriter, r *http.Request)
+
+	GetByID(w http.ResponseWriter, r *http.Request)
+
+	GetTree(w http.ResponseWriter, r *http.Request)
+
+	UpdateName(w http.ResponseWriter, r *http.Request)
+
+	UpdatePosition(w http.ResponseWriter, r *http.Request)
+
+	Archive(w http.ResponseWriter, r *http.Request)
+
+	Unarchive(w http.ResponseWriter, r *http.Request)
+}
+
+type categoryControllerImpl struct {
+	serv domain.CategoryService
+}
+
+func (con categoryControllerImpl) Create(w http.ResponseWriter, r *http.Request) {
+	userID, err := delivery.ParseUintParam(r, "userid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid ID provided")
+		return
+	}
+	var createReq struct {
+		ParentID uint   `json:"ParentId"`
+		Name     string `json:"Name"`
+	}
+	err = delivery.ReadJSONRequest(r, &createReq)
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Incorrect request format")
+		return
+	}
+
+	id, err := con.serv.Create(userID, createReq.ParentID, createReq.Name)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid provided parameters")
+		return
+	}
+	if err == service.ErrNotAuthorized {
+		delivery.WriteResponse(w, http.StatusForbidden, "Only moderators can manage categories")
+		return
+	}
+	if err == service.ErrDependencyNotSatisfied {
+		delivery.WriteResponse(w, http.StatusNotFound, "Unexistent parent category")
+		return
+	}
+	if err == service.ErrAlreadyExisting {
+		delivery.WriteResponse(w, http.StatusConflict, "Repeated name")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	response := struct {
+		ID uint `json:"ID"`
+	}{
+		ID: id,
+	}
+	delivery.WriteJSONResponse(w, http.StatusCreated, response)
+}
+
+func (con categoryControllerImpl) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := delivery.ParseUintParam(r, "categoryid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid ID provided")
+		return
+	}
+
+	category, err := con.serv.GetByID(id)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
+		return
+	}
+	if err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusNotFound, "Category doesn't exist")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteJSONResponse(w, http.StatusOK, category)
+}
+
+func (con categoryControllerImpl) GetTree(w http.ResponseWriter, r *http.Request) {
+	trees, err := con.serv.GetTree()
+	if err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusNotFound, "No category found")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteJSONResponse(w, http.StatusOK, trees)
+}
+
+func (con categoryControllerImpl) UpdateName(w http.ResponseWriter, r *http.Request) {
+	userID, err := delivery.ParseUintParam(r, "userid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid userID provided")
+		return
+	}
+	categoryID, err := delivery.ParseUintParam(r, "categoryid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid categoryID provided")
+		return
+	}
+
+	var updateReq struct {
+		Name string `json:"Name"`
+	}
+	err = delivery.ReadJSONRequest(r, &updateReq)
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Incorrect request format")
+		return
+	}
+
+	err = con.serv.UpdateName(userID, categoryID, updateReq.Name)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
+		return
+	}
+	if err == service.ErrNotAuthorized {
+		delivery.WriteResponse(w, http.StatusForbidden, "Only moderators can manage categories")
+		return
+	}
+	if err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusNotFound, "Category doesn't exist")
+		return
+	}
+	if err == service.ErrAlreadyExisting {
+		delivery.WriteResponse(w, http.StatusConflict, "Repeated name")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteResponse(w, http.StatusOK, "Category updated successfully")
+}
+
+func (con categoryControllerImpl) UpdatePosition(w http.ResponseWriter, r *http.Request) {
+	userID, err := delivery.ParseUintParam(r, "userid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid userID provided")
+		return
+	}
+	categoryID, err := delivery.ParseUintParam(r, "categoryid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid categoryID provided")
+		return
+	}
+
+	var updateReq struct {
+		Position uint `json:"Position"`
+	}
+	err = delivery.ReadJSONRequest(r, &updateReq)
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Incorrect request format")
+		return
+	}
+
+	err = con.serv.UpdatePosition(userID, categoryID, updateReq.Position)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
+		return
+	}
+	if err == service.ErrNotAuthorized {
+		delivery.WriteResponse(w, http.StatusForbidden, "Only moderators can manage categories")
+		return
+	}
+	if err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusNotFound, "Category doesn't exist")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteResponse(w, http.StatusOK, "Category moved successfully")
+}
+
+func (con categoryControllerImpl) Archive(w http.ResponseWriter, r *http.Request) {
+	userID, err := delivery.ParseUintParam(r, "userid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid userID provided")
+		return
+	}
+	categoryID, err := delivery.ParseUintParam(r, "categoryid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid categoryID provided")
+		return
+	}
+
+	err = con.serv.Archive(userID, categoryID)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
+		return
+	}
+	if err == service.ErrNotAuthorized {
+		delivery.WriteResponse(w, http.StatusForbidden, "Only moderators can manage categories")
+		return
+	}
+	if err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusNotFound, "Category doesn't exist")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteResponse(w, http.StatusOK, "Category archived successfully")
+}
+
+func (con categoryControllerImpl) Unarchive(w http.ResponseWriter, r *http.Request) {
+	userID, err := delivery.ParseUintParam(r, "userid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid userID provided")
+		return
+	}
+	categoryID, err := delivery.ParseUintParam(r, "categoryid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid categoryID provided")
+		return
+	}
+
+	err = con.serv.Unarchive(userID, categoryID)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
+		return
+	}
+	if err == service.ErrNotAuthorized {
+		delivery.WriteResponse(w, http.StatusForbidden, "Only moderators can manage categories")
+		return
+	}
+	if err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusNotFound, "Category doesn't exist")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteResponse(w, http.StatusOK, "Category unarchived successfully")
+}
+
+func NewCategoryController(serv domain.CategoryService) CategoryController {
+	return categoryControllerImpl{serv: serv}
+}
@@ -21,63 +21,97 @@ type CommentController interface {
 
 	GetByUser(w http.ResponseWriter, r *http.Request)
 
-	AddLike(w http.ResponseWriter, r *http.Request)
+	GetReplies(w http.ResponseWriter, r *http.Request)
 
-	DeleteLike(w http.ResponseWriter, r *http.Request)
+	GetRevisions(w http.ResponseWriter, r *http.Request)
+
+	Vote(w http.ResponseWriter, r *http.Request)
+
+	Restore(w http.ResponseWriter, r *http.Request)
 }
 
 type commentControllerImpl struct {
 	serv domain.CommentService
 }
 
-func (con commentControllerImpl) AddLike(w http.ResponseWriter, r *http.Request) {
+func (con commentControllerImpl) Create(w http.ResponseWriter, r *http.Request) {
 	userID, err := delivery.ParseUintParam(r, "userid")
-	if err != nil {
-		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid userID provided")
-		return
+	var createReq struct {
+		PostID   uint   `json:"PostId"`
+		ParentID uint   `json:"ParentId"`
+		Content  string `json:"Content"`
 	}
-
-	commentID, err := delivery.ParseUintParam(r, "commentid")
+	err = delivery.ReadJSONRequest(r, &createReq)
 	if err != nil {
-		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid commentID provided")
+		delivery.WriteResponse(w, http.StatusBadRequest, "Incorrect request format")
 		return
 	}
 
-	err = con.serv.AddLike(userID, commentID)
+	id, err := con.serv.Create(userID, createReq.PostID, createReq.ParentID, createReq.Content)
 	if err == service.ErrIncorrectParameters {
 		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
 		return
 	}
 	if err == service.ErrDependencyNotSatisfied {
-		delivery.WriteResponse(w, http.StatusNotFound, "User or Post doesn't exist")
+		delivery.WriteResponse(w, http.StatusBadRequest, "User, Post or parent Comment doesn't exist")
+		return
+	}
+	if err == service.ErrMaxDepthExceeded {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Replies can't be nested deeper")
+		return
+	}
+	if err == service.ErrNotAuthorized {
+		delivery.WriteResponse(w, http.StatusForbidden, "You can't comment on this post")
+		return
+	}
+	if err == service.ErrSuspended {
+		delivery.WriteResponse(w, http.StatusForbidden, "User is suspended")
+		return
+	}
+	if err == service.ErrLocked {
+		delivery.WriteResponse(w, http.StatusForbidden, "Post is locked")
+		return
+	}
+	if err == service.ErrArchived {
+		delivery.WriteResponse(w, http.StatusForbidden, "Post is archived")
+		return
 	}
 	if err != nil {
 		delivery.WriteResponse(w, http.StatusInternalServerError, "")
 		return
 	}
 
-	delivery.WriteResponse(w, http.StatusOK, "Like created successfully")
+	response := struct {
+		ID uint `json:"ID"`
+	}{
+		ID: id,
+	}
+	delivery.WriteJSONResponse(w, http.StatusCreated, response)
 }
 
-func (con commentControllerImpl) Create(w http.ResponseWriter, r *http.Request) {
+func (con commentControllerImpl) Delete(w http.ResponseWriter, r *http.Request) {
 	userID, err := delivery.ParseUintParam(r, "userid")
-	var createReq struct {
-		PostID  uint   `json:"PostId"`
-		Content string `json:"Content"`
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid userID provided")
+		return
 	}
-	err = delivery.ReadJSONRequest(r, &createReq)
+	commentID, err := delivery.ParseUintParam(r, "commentid")
 	if err != nil {
-		delivery.WriteResponse(w, http.StatusBadRequest, "Incorrect request format")
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid ID provided")
 		return
 	}
 
-	id, err := con.serv.Create(userID, createReq.PostID, createReq.Content)
+	err = con.serv.Delete(userID, commentID)
 	if err == service.ErrIncorrectParameters {
 		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
 		return
 	}
-	if err == service.ErrDependencyNotSatisfied {
-		delivery.WriteResponse(w, http.StatusBadRequest, "User or Post doesn't exist")
+	if err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusNotFound, "Comment doesn't exist")
+		return
+	}
+	if err == service.ErrNotAuthorized {
+		delivery.WriteResponse(w, http.StatusForbidden, "Only the author or a moderator can delete this comment")
 		return
 	}
 	if err != nil {
@@ -85,22 +119,17 @@ func (con commentControllerImpl) Create(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	response := struct {
-		ID uint `json:"ID"`
-	}{
-		ID: id,
-	}
-	delivery.WriteJSONResponse(w, http.StatusCreated, response)
+	delivery.WriteResponse(w, http.StatusOK, "Comment deleted successfully")
 }
 
-func (con commentControllerImpl) Delete(w http.ResponseWriter, r *http.Request) {
-	userID, err := delivery.ParseUintParam(r, "userid")
+func (con commentControllerImpl) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := delivery.ParseUintParam(r, "commentid")
 	if err != nil {
 		delivery.WriteResponse(w, http.StatusOK, "Invalid ID provided")
 		return
 	}
 
-	err = con.serv.Delete(userID)
+	comment, err := con.serv.GetByID(id)
 	if err == service.ErrIncorrectParameters {
 		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
 		return
@@ -114,29 +143,54 @@ func (con commentControllerImpl) Delete(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	delivery.WriteResponse(w, http.StatusOK, "Post deleted successfully")
-}
+	if !con.checkPostVisible(w, r, comment.PostID) {
+		return
+	}
 
-func (con commentControllerImpl) DeleteLike(w http.ResponseWriter, r *http.Request) {
-	userID, err := delivery.ParseUintParam(r, "userid")
+	err = con.addCommentViewer(r, &comment)
 	if err != nil {
-		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid userID provided")
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
 		return
 	}
 
-	commentID, err := delivery.ParseUintParam(r, "commentid")
+	delivery.WriteJSONResponse(w, http.StatusOK, comment)
+}
+
+func (con commentControllerImpl) GetByPost(w http.ResponseWriter, r *http.Request) {
+	id, err := delivery.ParseUintParam(r, "postid")
 	if err != nil {
-		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid postID provided")
+		delivery.WriteResponse(w, http.StatusOK, "Invalid ID provided")
+		return
+	}
+
+	if !con.checkPostVisible(w, r, id) {
 		return
 	}
 
-	err = con.serv.DeleteLike(userID, commentID)
+	order := domain.CommentOrder(r.URL.Query().Get("sort"))
+
+	var comments interface{}
+	if r.URL.Query().Get("view") == "tree" {
+		var threads []domain.CommentThread
+		threads, err = con.serv.GetThreadsByPost(id, order)
+		if err == nil {
+			threads, err = con.addThreadsViewer(r, threads)
+		}
+		comments = threads
+	} else {
+		var list []domain.Comment
+		list, err = con.serv.GetByPost(id, order)
+		if err == nil {
+			list, err = con.addViewer(r, list)
+		}
+		comments = list
+	}
 	if err == service.ErrIncorrectParameters {
 		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
 		return
 	}
 	if err == service.ErrNotExistingEntity {
-		delivery.WriteResponse(w, http.StatusNotFound, "Like doesn't exist")
+		delivery.WriteResponse(w, http.StatusNotFound, "No comments found")
 		return
 	}
 	if err != nil {
@@ -144,23 +198,27 @@ func (con commentControllerImpl) DeleteLike(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	delivery.WriteResponse(w, http.StatusOK, "Like deleted successfully")
+	delivery.WriteJSONResponse(w, http.StatusOK, comments)
 }
 
-func (con commentControllerImpl) GetByID(w http.ResponseWriter, r *http.Request) {
+func (con commentControllerImpl) GetReplies(w http.ResponseWriter, r *http.Request) {
 	id, err := delivery.ParseUintParam(r, "commentid")
 	if err != nil {
-		delivery.WriteResponse(w, http.StatusOK, "Invalid ID provided")
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid ID provided")
 		return
 	}
 
-	comment, err := con.serv.GetByID(id)
+	if !con.checkCommentVisible(w, r, id) {
+		return
+	}
+
+	replies, err := con.serv.GetReplies(id)
 	if err == service.ErrIncorrectParameters {
 		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
 		return
 	}
 	if err == service.ErrNotExistingEntity {
-		delivery.WriteResponse(w, http.StatusNotFound, "Comment doesn't exist")
+		delivery.WriteResponse(w, http.StatusNotFound, "No replies found")
 		return
 	}
 	if err != nil {
@@ -168,17 +226,24 @@ func (con commentControllerImpl) GetByID(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	delivery.WriteJSONResponse(w, http.StatusOK, comment)
+	replies, err = con.addViewer(r, replies)
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteJSONResponse(w, http.StatusOK, replies)
 }
 
-func (con commentControllerImpl) GetByPost(w http.ResponseWriter, r *http.Request) {
-	id, err := delivery.ParseUintParam(r, "postid")
+func (con commentControllerImpl) GetByUser(w http.ResponseWriter, r *http.Request) {
+	id, err := delivery.ParseUintParam(r, "userid")
 	if err != nil {
 		delivery.WriteResponse(w, http.StatusOK, "Invalid ID provided")
 		return
 	}
 
-	posts, err := con.serv.GetByPost(id)
+	viewerID, _ := delivery.AuthenticatedUserID(r)
+	posts, err := con.serv.GetByUser(viewerID, id)
 	if err == service.ErrIncorrectParameters {
 		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
 		return
@@ -192,23 +257,33 @@ func (con commentControllerImpl) GetByPost(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	posts, err = con.addViewer(r, posts)
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
 	delivery.WriteJSONResponse(w, http.StatusOK, posts)
 }
 
-func (con commentControllerImpl) GetByUser(w http.ResponseWriter, r *http.Request) {
-	id, err := delivery.ParseUintParam(r, "userid")
+func (con commentControllerImpl) GetRevisions(w http.ResponseWriter, r *http.Request) {
+	id, err := delivery.ParseUintParam(r, "commentid")
 	if err != nil {
-		delivery.WriteResponse(w, http.StatusOK, "Invalid ID provided")
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid ID provided")
+		return
+	}
+
+	if !con.checkCommentVisible(w, r, id) {
 		return
 	}
 
-	posts, err := con.serv.GetByUser(id)
+	revisions, err := con.serv.GetRevisions(id)
 	if err == service.ErrIncorrectParameters {
 		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
 		return
 	}
 	if err == service.ErrNotExistingEntity {
-		delivery.WriteResponse(w, http.StatusNotFound, "No posts found")
+		delivery.WriteResponse(w, http.StatusNotFound, "No revisions found")
 		return
 	}
 	if err != nil {
@@ -216,10 +291,15 @@ func (con commentControllerImpl) GetByUser(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	delivery.WriteJSONResponse(w, http.StatusOK, posts)
+	delivery.WriteJSONResponse(w, http.StatusOK, revisions)
 }
 
 func (con commentControllerImpl) UpdateContent(w http.ResponseWriter, r *http.Request) {
+	userID, err := delivery.ParseUintParam(r, "userid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid userID provided")
+		return
+	}
 	id, err := delivery.ParseUintParam(r, "commentid")
 	if err != nil {
 		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid ID provided")
@@ -235,7 +315,7 @@ func (con commentControllerImpl) UpdateContent(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	err = con.serv.Update(id, updateReq.Content)
+	err = con.serv.Update(userID, id, updateReq.Content)
 	if err == service.ErrIncorrectParameters {
 		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
 		return
@@ -244,6 +324,18 @@ func (con commentControllerImpl) UpdateContent(w http.ResponseWriter, r *http.Re
 		delivery.WriteResponse(w, http.StatusNotFound, "Comment doesn't exist")
 		return
 	}
+	if err == service.ErrNotAuthorized {
+		delivery.WriteResponse(w, http.StatusForbidden, "Only the author or a moderator can edit this comment")
+		return
+	}
+	if err == service.ErrArchived {
+		delivery.WriteResponse(w, http.StatusForbidden, "Post is archived")
+		return
+	}
+	if err == service.ErrSuspended {
+		delivery.WriteResponse(w, http.StatusForbidden, "User is suspended")
+		return
+	}
 	if err != nil {
 		delivery.WriteResponse(w, http.StatusInternalServerError, "")
 		return
@@ -252,6 +344,176 @@ func (con commentControllerImpl) UpdateContent(w http.ResponseWriter, r *http.Re
 	delivery.WriteResponse(w, http.StatusOK, "Comment updated successfully")
 }
 
+func (con commentControllerImpl) Restore(w http.ResponseWriter, r *http.Request) {
+	userID, err := delivery.ParseUintParam(r, "userid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid userID provided")
+		return
+	}
+	commentID, err := delivery.ParseUintParam(r, "commentid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid commentID provided")
+		return
+	}
+
+	err = con.serv.Restore(userID, commentID)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
+		return
+	}
+	if err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusNotFound, "Deleted comment doesn't exist")
+		return
+	}
+	if err == service.ErrNotAuthorized {
+		delivery.WriteResponse(w, http.StatusForbidden, "Only the author or a moderator can restore this comment")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteResponse(w, http.StatusOK, "Comment restored successfully")
+}
+
+func (con commentControllerImpl) Vote(w http.ResponseWriter, r *http.Request) {
+	userID, ok := delivery.AuthenticatedUserID(r)
+	if !ok {
+		delivery.WriteResponse(w, http.StatusUnauthorized, "You're not authorized to this resource")
+		return
+	}
+	commentID, err := delivery.ParseUintParam(r, "commentid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid commentID provided")
+		return
+	}
+	var voteReq struct {
+		Value *int `json:"Value"`
+	}
+	err = delivery.ReadJSONRequest(r, &voteReq)
+	if err != nil || voteReq.Value == nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Incorrect request format")
+		return
+	}
+
+	err = con.serv.Vote(userID, commentID, *voteReq.Value)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
+		return
+	}
+	if err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusNotFound, "Comment doesn't exist")
+		return
+	}
+	if err == service.ErrDependencyNotSatisfied {
+		delivery.WriteResponse(w, http.StatusNotFound, "User doesn't exist")
+		return
+	}
+	if err == service.ErrNotAuthorized {
+		delivery.WriteResponse(w, http.StatusForbidden, "Only approved followers can vote on this post")
+		return
+	}
+	if err == service.ErrArchived {
+		delivery.WriteResponse(w, http.StatusForbidden, "Post is archived")
+		return
+	}
+	if err == service.ErrSuspended {
+		delivery.WriteResponse(w, http.StatusForbidden, "User is suspended")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteResponse(w, http.StatusOK, "Vote registered successfully")
+}
+
+// Hides comments by muted profiles and adds the viewer fields when the request is authenticated
+func (con commentControllerImpl) addViewer(r *http.Request, comments []domain.Comment) ([]domain.Comment, error) {
+	viewerID, ok := delivery.AuthenticatedUserID(r)
+	if !ok {
+		return comments, nil
+	}
+
+	comments, err := con.serv.HideMuted(viewerID, comments)
+	if err != nil {
+		return nil, err
+	}
+
+	return comments, con.serv.AddViewer(viewerID, comments)
+}
+
+func (con commentControllerImpl) addCommentViewer(r *http.Request, comment *domain.Comment) error {
+	viewerID, ok := delivery.AuthenticatedUserID(r)
+	if !ok {
+		return nil
+	}
+
+	comments := []domain.Comment{*comment}
+	err := con.serv.AddViewer(viewerID, comments)
+	*comment = comments[0]
+	return err
+}
+
+func (con commentControllerImpl) addThreadsViewer(r *http.Request, threads []domain.CommentThread) ([]domain.CommentThread, error) {
+	viewerID, ok := delivery.AuthenticatedUserID(r)
+	if !ok {
+		return threads, nil
+	}
+
+	threads, err := con.serv.HideMutedInThreads(viewerID, threads)
+	if err != nil {
+		return nil, err
+	}
+
+	return threads, con.serv.AddViewerToThreads(viewerID, threads)
+}
+
+// Answers and returns false when the post doesn't exist or the viewer can't see it
+func (con commentControllerImpl) checkPostVisible(w http.ResponseWriter, r *http.Request, postID uint) bool {
+	viewerID, _ := delivery.AuthenticatedUserID(r)
+	err := con.serv.CheckVisible(viewerID, postID)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
+		return false
+	}
+	if err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusNotFound, "Post doesn't exist")
+		return false
+	}
+	if err == service.ErrNotAuthorized {
+		delivery.WriteResponse(w, http.StatusForbidden, "Only approved followers can see this post")
+		return false
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return false
+	}
+
+	return true
+}
+
+// Answers and returns false when the comment doesn't exist or the viewer can't see its post
+func (con commentControllerImpl) checkCommentVisible(w http.ResponseWriter, r *http.Request, commentID uint) bool {
+	comment, err := con.serv.GetByID(commentID)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
+		return false
+	}
+	if err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusNotFound, "Comment doesn't exist")
+		return false
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return false
+	}
+
+	return con.checkPostVisible(w, r, comment.PostID)
+}
+
 func NewCommentController(serv domain.CommentService) CommentController {
 	return commentControllerImpl{serv: serv}
 }
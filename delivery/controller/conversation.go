@@ -0,0 +1,248 @@
+package controller
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/AlejandroJorge/forum-rest-api/delivery"
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/service"
+)
+
+type ConversationController interface {
+	Start(w http.ResponseWriter, r *http.Request)
+	GetByID(w http.ResponseWriter, r *http.Request)
+	GetByUser(w http.ResponseWriter, r *http.Request)
+	Send(w http.ResponseWriter, r *http.Request)
+	GetMessages(w http.ResponseWriter, r *http.Request)
+	MarkRead(w http.ResponseWriter, r *http.Request)
+}
+
+type conversationControllerImpl struct {
+	serv domain.ConversationService
+}
+
+func (con conversationControllerImpl) Start(w http.ResponseWriter, r *http.Request) {
+	userID, ok := delivery.AuthenticatedUserID(r)
+	if !ok {
+		delivery.WriteResponse(w, http.StatusUnauthorized, "You're not authorized to this resource")
+		return
+	}
+	var startReq struct {
+		Members []uint `json:"Members"`
+	}
+	err := delivery.ReadJSONRequest(r, &startReq)
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Incorrect request format")
+		return
+	}
+
+	id, err := con.serv.Start(userID, startReq.Members)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
+		return
+	}
+	if err == service.ErrDependencyNotSatisfied {
+		delivery.WriteResponse(w, http.StatusNotFound, "Profile doesn't exist")
+		return
+	}
+	if err == service.ErrNotAuthorized {
+		delivery.WriteResponse(w, http.StatusForbidden, "You can't message this profile")
+		return
+	}
+	if err == service.ErrSuspended {
+		delivery.WriteResponse(w, http.StatusForbidden, "User is suspended")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	response := struct {
+		ID uint `json:"ID"`
+	}{
+		ID: id,
+	}
+	delivery.WriteJSONResponse(w, http.StatusCreated, response)
+}
+
+func (con conversationControllerImpl) GetByID(w http.ResponseWriter, r *http.Request) {
+	userID, ok := delivery.AuthenticatedUserID(r)
+	if !ok {
+		delivery.WriteResponse(w, http.StatusUnauthorized, "You're not authorized to this resource")
+		return
+	}
+	conversationID, err := delivery.ParseUintParam(r, "conversationid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid conversationID provided")
+		return
+	}
+
+	conversation, err := con.serv.GetByID(userID, conversationID)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
+		return
+	}
+	if err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusNotFound, "Conversation doesn't exist")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteJSONResponse(w, http.StatusOK, conversation)
+}
+
+func (con conversationControllerImpl) GetByUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := delivery.AuthenticatedUserID(r)
+	if !ok {
+		delivery.WriteResponse(w, http.StatusUnauthorized, "You're not authorized to this resource")
+		return
+	}
+	page, err := delivery.ParseUintQuery(r, "page", 1)
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid page provided")
+		return
+	}
+
+	conversations, err := con.serv.GetByUser(userID, page)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteJSONResponse(w, http.StatusOK, conversations)
+}
+
+func (con conversationControllerImpl) Send(w http.ResponseWriter, r *http.Request) {
+	userID, ok := delivery.AuthenticatedUserID(r)
+	if !ok {
+		delivery.WriteResponse(w, http.StatusUnauthorized, "You're not authorized to this resource")
+		return
+	}
+	conversationID, err := delivery.ParseUintParam(r, "conversationid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid conversationID provided")
+		return
+	}
+	var sendReq struct {
+		Content string `json:"Content"`
+	}
+	err = delivery.ReadJSONRequest(r, &sendReq)
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Incorrect request format")
+		return
+	}
+
+	id, err := con.serv.Send(userID, conversationID, sendReq.Content)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
+		return
+	}
+	if err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusNotFound, "Conversation doesn't exist")
+		return
+	}
+	if err == service.ErrNotAuthorized {
+		delivery.WriteResponse(w, http.StatusForbidden, "You can't message this profile")
+		return
+	}
+	if err == service.ErrSuspended {
+		delivery.WriteResponse(w, http.StatusForbidden, "User is suspended")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	response := struct {
+		ID uint `json:"ID"`
+	}{
+		ID: id,
+	}
+	delivery.WriteJSONResponse(w, http.StatusCreated, response)
+}
+
+func (con conversationControllerImpl) GetMessages(w http.ResponseWriter, r *http.Request) {
+	userID, ok := delivery.AuthenticatedUserID(r)
+	if !ok {
+		delivery.WriteResponse(w, http.StatusUnauthorized, "You're not authorized to this resource")
+		return
+	}
+	conversationID, err := delivery.ParseUintParam(r, "conversationid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid conversationID provided")
+		return
+	}
+	page, err := delivery.ParseUintQuery(r, "page", 1)
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid page provided")
+		return
+	}
+
+	messages, err := con.serv.GetMessages(userID, conversationID, page)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
+		return
+	}
+	if err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusNotFound, "Conversation doesn't exist")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteJSONResponse(w, http.StatusOK, messages)
+}
+
+// The body is optional, without a MessageID everything in the conversation is read
+func (con conversationControllerImpl) MarkRead(w http.ResponseWriter, r *http.Request) {
+	userID, ok := delivery.AuthenticatedUserID(r)
+	if !ok {
+		delivery.WriteResponse(w, http.StatusUnauthorized, "You're not authorized to this resource")
+		return
+	}
+	conversationID, err := delivery.ParseUintParam(r, "conversationid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid conversationID provided")
+		return
+	}
+	var readReq struct {
+		MessageID uint `json:"MessageID"`
+	}
+	err = delivery.ReadJSONRequest(r, &readReq)
+	if err != nil && err != io.EOF {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Incorrect request format")
+		return
+	}
+
+	err = con.serv.MarkRead(userID, conversationID, readReq.MessageID)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
+		return
+	}
+	if err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusNotFound, "Conversation or message doesn't exist")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteResponse(w, http.StatusOK, "Conversation marked as read")
+}
+
+func NewConversationController(serv domain.ConversationService) ConversationController {
+	return conversationControllerImpl{serv: serv}
+}
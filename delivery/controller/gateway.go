@@ -0,0 +1,269 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/AlejandroJorge/forum-rest-api/config"
+	"github.com/AlejandroJorge/forum-rest-api/delivery"
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/logging"
+	"github.com/AlejandroJorge/forum-rest-api/service"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	gatewayMaxMessageSize       = 4096
+	gatewayMaxMessagesPerSecond = 10
+	gatewayOutboundBufferSize   = 64
+	gatewayTypingInterval       = 2 * time.Second
+	gatewayWriteWait            = 10 * time.Second
+	gatewayPongWait             = 60 * time.Second
+	gatewayPingPeriod           = gatewayPongWait * 9 / 10
+)
+
+type GatewayController interface {
+	Connect(w http.ResponseWriter, r *http.Request)
+}
+
+type gatewayControllerImpl struct {
+	postServ domain.PostService
+	bus      domain.EventBus
+	upgrader websocket.Upgrader
+}
+
+// What clients send: subscribe, unsubscribe or typing, always about a post
+type gatewayRequest struct {
+	Type   string `json:"Type"`
+	PostID uint   `json:"PostID"`
+}
+
+// What clients receive: subscribed, unsubscribed, event or error
+type gatewayMessage struct {
+	Type    string        `json:"Type"`
+	PostID  uint          `json:"PostID,omitempty"`
+	Event   *domain.Event `json:"Event,omitempty"`
+	Message string        `json:"Message,omitempty"`
+}
+
+type gatewayConnection struct {
+	con           gatewayControllerImpl
+	ws            *websocket.Conn
+	userID        uint
+	outbound      chan gatewayMessage
+	done          chan struct{}
+	closeOnce     sync.Once
+	subscriptions map[uint]domain.EventSubscription
+	lastTyping    map[uint]time.Time
+}
+
+// Upgrades to a WebSocket where the user follows the comments, votes and typing presence of posts
+func (con gatewayControllerImpl) Connect(w http.ResponseWriter, r *http.Request) {
+	userID, ok := delivery.AuthenticatedUserID(r)
+	if !ok {
+		delivery.WriteResponse(w, http.StatusUnauthorized, "You're not authorized to this resource")
+		return
+	}
+
+	// Upgrade answers the request itself when it fails
+	ws, err := con.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	logging.LogRawResponse(http.StatusSwitchingProtocols, "WebSocket opened")
+
+	conn := &gatewayConnection{
+		con:           con,
+		ws:            ws,
+		userID:        userID,
+		outbound:      make(chan gatewayMessage, gatewayOutboundBufferSize),
+		done:          make(chan struct{}),
+		subscriptions: make(map[uint]domain.EventSubscription),
+		lastTyping:    make(map[uint]time.Time),
+	}
+
+	go conn.writeLoop()
+	conn.readLoop()
+
+	conn.close(websocket.CloseNormalClosure, "")
+	for _, subscription := range conn.subscriptions {
+		subscription.Unsubscribe()
+	}
+}
+
+func (conn *gatewayConnection) readLoop() {
+	conn.ws.SetReadLimit(gatewayMaxMessageSize)
+	conn.ws.SetReadDeadline(time.Now().Add(gatewayPongWait))
+	conn.ws.SetPongHandler(func(string) error {
+		return conn.ws.SetReadDeadline(time.Now().Add(gatewayPongWait))
+	})
+
+	windowStart := time.Now()
+	windowMessages := 0
+	for {
+		_, data, err := conn.ws.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		if time.Since(windowStart) >= time.Second {
+			windowStart = time.Now()
+			windowMessages = 0
+		}
+		windowMessages++
+		if windowMessages > gatewayMaxMessagesPerSecond {
+			conn.close(websocket.ClosePolicyViolation, "Too many messages")
+			return
+		}
+
+		var req gatewayRequest
+		err = json.Unmarshal(data, &req)
+		if err != nil {
+			conn.send(gatewayMessage{Type: "error", Message: "Incorrect message format"})
+			continue
+		}
+
+		switch req.Type {
+		case "subscribe":
+			conn.subscribe(req.PostID)
+		case "unsubscribe":
+			conn.unsubscribe(req.PostID)
+		case "typing":
+			conn.typing(req.PostID)
+		default:
+			conn.send(gatewayMessage{Type: "error", Message: "Unknown message type"})
+		}
+	}
+}
+
+func (conn *gatewayConnection) subscribe(postID uint) {
+	if _, ok := conn.subscriptions[postID]; ok {
+		conn.send(gatewayMessage{Type: "subscribed", PostID: postID})
+		return
+	}
+	if uint(len(conn.subscriptions)) >= config.GetParams().WSMaxSubscriptions {
+		conn.send(gatewayMessage{Type: "error", PostID: postID, Message: "Too many subscriptions"})
+		return
+	}
+
+	post, err := conn.con.postServ.GetByID(postID)
+	if err == service.ErrIncorrectParameters || err == service.ErrNotExistingEntity {
+		conn.send(gatewayMessage{Type: "error", PostID: postID, Message: "Post doesn't exist"})
+		return
+	}
+	if err != nil {
+		conn.send(gatewayMessage{Type: "error", PostID: postID, Message: "Couldn't subscribe"})
+		return
+	}
+
+	err = conn.con.postServ.CheckVisible(conn.userID, post.OwnerID)
+	if err == service.ErrNotAuthorized {
+		conn.send(gatewayMessage{Type: "error", PostID: postID, Message: "Only approved followers can follow this post"})
+		return
+	}
+	if err != nil {
+		conn.send(gatewayMessage{Type: "error", PostID: postID, Message: "Couldn't subscribe"})
+		return
+	}
+
+	_, subscription := conn.con.bus.Subscribe([]string{domain.PostTopic(postID), domain.TypingTopic(postID)}, 0)
+	conn.subscriptions[postID] = subscription
+	go conn.forward(subscription)
+
+	conn.send(gatewayMessage{Type: "subscribed", PostID: postID})
+}
+
+func (conn *gatewayConnection) unsubscribe(postID uint) {
+	if subscription, ok := conn.subscriptions[postID]; ok {
+		subscription.Unsubscribe()
+		delete(conn.subscriptions, postID)
+		delete(conn.lastTyping, postID)
+	}
+
+	conn.send(gatewayMessage{Type: "unsubscribed", PostID: postID})
+}
+
+// Typing notices are dropped when they come faster than gatewayTypingInterval
+func (conn *gatewayConnection) typing(postID uint) {
+	if _, ok := conn.subscriptions[postID]; !ok {
+		conn.send(gatewayMessage{Type: "error", PostID: postID, Message: "Not subscribed to the post"})
+		return
+	}
+	if time.Since(conn.lastTyping[postID]) < gatewayTypingInterval {
+		return
+	}
+
+	conn.lastTyping[postID] = time.Now()
+	conn.con.bus.PublishEphemeral(domain.TypingTopic(postID), domain.EventPostTyping, domain.Typing{PostID: postID, UserID: conn.userID})
+}
+
+// Passes the events of a subscription to the client until it's unsubscribed, skipping the user's own typing
+func (conn *gatewayConnection) forward(subscription domain.EventSubscription) {
+	for {
+		select {
+		case <-conn.done:
+			return
+		case event, ok := <-subscription.Events():
+			if !ok {
+				return
+			}
+			if typing, isTyping := event.Data.(domain.Typing); isTyping && typing.UserID == conn.userID {
+				continue
+			}
+
+			conn.send(gatewayMessage{Type: "event", Event: &event})
+		}
+	}
+}
+
+// Never blocks, clients that don't read fast enough are disconnected
+func (conn *gatewayConnection) send(msg gatewayMessage) {
+	select {
+	case <-conn.done:
+	case conn.outbound <- msg:
+	default:
+		conn.close(websocket.ClosePolicyViolation, "Not reading fast enough")
+	}
+}
+
+func (conn *gatewayConnection) writeLoop() {
+	ping := time.NewTicker(gatewayPingPeriod)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-conn.done:
+			return
+		case msg := <-conn.outbound:
+			conn.ws.SetWriteDeadline(time.Now().Add(gatewayWriteWait))
+			err := conn.ws.WriteJSON(msg)
+			if err != nil {
+				conn.close(websocket.CloseAbnormalClosure, "")
+				return
+			}
+		case <-ping.C:
+			err := conn.ws.WriteControl(websocket.PingMessage, nil, time.Now().Add(gatewayWriteWait))
+			if err != nil {
+				conn.close(websocket.CloseAbnormalClosure, "")
+				return
+			}
+		}
+	}
+}
+
+// Safe to call from any goroutine and more than once, only the first call says goodbye to the client
+func (conn *gatewayConnection) close(code int, reason string) {
+	conn.closeOnce.Do(func() {
+		close(conn.done)
+		if code != websocket.CloseAbnormalClosure {
+			conn.ws.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(gatewayWriteWait))
+		}
+		conn.ws.Close()
+	})
+}
+
+func NewGatewayController(postServ domain.PostService, bus domain.EventBus) GatewayController {
+	return gatewayControllerImpl{postServ: postServ, bus: bus}
+}
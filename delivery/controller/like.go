@@ -0,0 +1,46 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/AlejandroJorge/forum-rest-api/delivery"
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/service"
+)
+
+type LikeController interface {
+	GetByUser(w http.ResponseWriter, r *http.Request)
+}
+
+type likeControllerImpl struct {
+	serv domain.LikeService
+}
+
+func (con likeControllerImpl) GetByUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := delivery.ParseUintParam(r, "userid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid ID provided")
+		return
+	}
+
+	viewerID, _ := delivery.AuthenticatedUserID(r)
+	likes, err := con.serv.GetByUser(viewerID, userID)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
+		return
+	}
+	if err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusNotFound, "No likes found")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteJSONResponse(w, http.StatusOK, likes)
+}
+
+func NewLikeController(serv domain.LikeService) LikeController {
+	return likeControllerImpl{serv: serv}
+}
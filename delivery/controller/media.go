@@ -0,0 +1,139 @@
+package controller
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/AlejandroJorge/forum-rest-api/config"
+	"github.com/AlejandroJorge/forum-rest-api/delivery"
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/service"
+)
+
+type MediaController interface {
+	Upload(w http.ResponseWriter, r *http.Request)
+
+	GetByID(w http.ResponseWriter, r *http.Request)
+
+	Serve(w http.ResponseWriter, r *http.Request)
+}
+
+type mediaControllerImpl struct {
+	serv domain.MediaService
+}
+
+// Room for the multipart boundaries and headers around the file
+const multipartOverhead = 1 << 20
+
+// Expects a multipart form with the image in the file field
+func (con mediaControllerImpl) Upload(w http.ResponseWriter, r *http.Request) {
+	userID, ok := delivery.AuthenticatedUserID(r)
+	if !ok {
+		delivery.WriteResponse(w, http.StatusUnauthorized, "You're not authorized to this resource")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, int64(config.GetParams().MaxUploadBytes)+multipartOverhead)
+	reader, err := r.MultipartReader()
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Incorrect request format")
+		return
+	}
+
+	var file io.Reader
+	for file == nil {
+		part, err := reader.NextPart()
+		if err != nil {
+			delivery.WriteResponse(w, http.StatusBadRequest, "Missing file field")
+			return
+		}
+		if part.FormName() == "file" {
+			file = part
+		}
+	}
+
+	media, err := con.serv.Upload(userID, file)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid upload provided")
+		return
+	}
+	if err == service.ErrTooLarge {
+		delivery.WriteResponse(w, http.StatusRequestEntityTooLarge, "Image is too large")
+		return
+	}
+	if err == service.ErrUnsupportedMedia {
+		delivery.WriteResponse(w, http.StatusUnsupportedMediaType, "Only JPEG, PNG and GIF images are supported")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteJSONResponse(w, http.StatusCreated, media)
+}
+
+func (con mediaControllerImpl) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := delivery.ParseUintParam(r, "mediaid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid ID provided")
+		return
+	}
+
+	media, err := con.serv.GetByID(id)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid ID provided")
+		return
+	}
+	if err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusNotFound, "Media not found")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteJSONResponse(w, http.StatusOK, media)
+}
+
+// Keys are content hashes, so what they serve never changes and can be cached forever
+func (con mediaControllerImpl) Serve(w http.ResponseWriter, r *http.Request) {
+	key, err := delivery.ParseStringParam(r, "key")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid key provided")
+		return
+	}
+
+	etag := `"` + key + `"`
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	blob, contentType, err := con.serv.Open(key)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid key provided")
+		return
+	}
+	if err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusNotFound, "Media not found")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+	defer blob.Close()
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.Header().Set("ETag", etag)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	io.Copy(w, blob)
+}
+
+func NewMediaController(serv domain.MediaService) MediaController {
+	return mediaControllerImpl{serv: serv}
+}
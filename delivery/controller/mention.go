@@ -0,0 +1,46 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/AlejandroJorge/forum-rest-api/delivery"
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/service"
+)
+
+type MentionController interface {
+	GetByTagName(w http.ResponseWriter, r *http.Request)
+}
+
+type mentionControllerImpl struct {
+	serv domain.MentionService
+}
+
+func (con mentionControllerImpl) GetByTagName(w http.ResponseWriter, r *http.Request) {
+	tagName, err := delivery.ParseStringParam(r, "tagname")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid tagname provided")
+		return
+	}
+
+	viewerID, _ := delivery.AuthenticatedUserID(r)
+	mentions, err := con.serv.GetByTagName(viewerID, tagName)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
+		return
+	}
+	if err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusNotFound, "Profile not found")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteJSONResponse(w, http.StatusOK, mentions)
+}
+
+func NewMentionController(serv domain.MentionService) MentionController {
+	return mentionControllerImpl{serv: serv}
+}
@@ -0,0 +1,182 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/AlejandroJorge/forum-rest-api/delivery"
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/service"
+)
+
+type NotificationController interface {
+	GetByUser(w http.ResponseWriter, r *http.Request)
+	MarkRead(w http.ResponseWriter, r *http.Request)
+	MarkAllRead(w http.ResponseWriter, r *http.Request)
+	GetPreferences(w http.ResponseWriter, r *http.Request)
+	UpdatePreferences(w http.ResponseWriter, r *http.Request)
+}
+
+type notificationControllerImpl struct {
+	serv domain.NotificationService
+}
+
+func (con notificationControllerImpl) GetByUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := delivery.AuthenticatedUserID(r)
+	if !ok {
+		delivery.WriteResponse(w, http.StatusUnauthorized, "You're not authorized to this resource")
+		return
+	}
+	page, err := delivery.ParseUintQuery(r, "page", 1)
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid page provided")
+		return
+	}
+
+	notifications, err := con.serv.GetByUser(userID, page)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteJSONResponse(w, http.StatusOK, notifications)
+}
+
+func (con notificationControllerImpl) MarkRead(w http.ResponseWriter, r *http.Request) {
+	userID, ok := delivery.AuthenticatedUserID(r)
+	if !ok {
+		delivery.WriteResponse(w, http.StatusUnauthorized, "You're not authorized to this resource")
+		return
+	}
+	notificationID, err := delivery.ParseUintParam(r, "notificationid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid notificationID provided")
+		return
+	}
+
+	err = con.serv.MarkRead(userID, notificationID)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
+		return
+	}
+	if err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusNotFound, "Notification doesn't exist")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteResponse(w, http.StatusOK, "Notification marked as read")
+}
+
+func (con notificationControllerImpl) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	userID, ok := delivery.AuthenticatedUserID(r)
+	if !ok {
+		delivery.WriteResponse(w, http.StatusUnauthorized, "You're not authorized to this resource")
+		return
+	}
+
+	marked, err := con.serv.MarkAllRead(userID)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteJSONResponse(w, http.StatusOK, struct {
+		Marked uint `json:"Marked"`
+	}{Marked: marked})
+}
+
+func (con notificationControllerImpl) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	userID, ok := delivery.AuthenticatedUserID(r)
+	if !ok {
+		delivery.WriteResponse(w, http.StatusUnauthorized, "You're not authorized to this resource")
+		return
+	}
+
+	preferences, err := con.serv.GetPreferences(userID)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteJSONResponse(w, http.StatusOK, preferences)
+}
+
+// Types left out of the request keep their current preference
+func (con notificationControllerImpl) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	userID, ok := delivery.AuthenticatedUserID(r)
+	if !ok {
+		delivery.WriteResponse(w, http.StatusUnauthorized, "You're not authorized to this resource")
+		return
+	}
+	var preferencesReq struct {
+		Reply   *bool `json:"Reply"`
+		Like    *bool `json:"Like"`
+		Follow  *bool `json:"Follow"`
+		Mention *bool `json:"Mention"`
+	}
+	err := delivery.ReadJSONRequest(r, &preferencesReq)
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Incorrect request format")
+		return
+	}
+
+	preferences, err := con.serv.GetPreferences(userID)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	updates := []struct {
+		requested *bool
+		current   *bool
+	}{
+		{preferencesReq.Reply, &preferences.Reply},
+		{preferencesReq.Like, &preferences.Like},
+		{preferencesReq.Follow, &preferences.Follow},
+		{preferencesReq.Mention, &preferences.Mention},
+	}
+	for _, update := range updates {
+		if update.requested != nil {
+			*update.current = *update.requested
+		}
+	}
+
+	err = con.serv.UpdatePreferences(userID, preferences)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
+		return
+	}
+	if err == service.ErrDependencyNotSatisfied {
+		delivery.WriteResponse(w, http.StatusNotFound, "Profile doesn't exist")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteJSONResponse(w, http.StatusOK, preferences)
+}
+
+func NewNotificationController(serv domain.NotificationService) NotificationController {
+	return notificationControllerImpl{serv: serv}
+}
@@ -2,6 +2,7 @@ package controller
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/AlejandroJorge/forum-rest-api/delivery"
 	"github.com/AlejandroJorge/forum-rest-api/domain"
@@ -19,10 +20,20 @@ type PostController interface {
 
 	UpdateContent(w http.ResponseWriter, r *http.Request)
 
+	UpdateTags(w http.ResponseWriter, r *http.Request)
+
 	GetByID(w http.ResponseWriter, r *http.Request)
 
 	GetByUser(w http.ResponseWriter, r *http.Request)
 
+	GetByIDAndSlug(w http.ResponseWriter, r *http.Request)
+
+	GetBySlug(w http.ResponseWriter, r *http.Request)
+
+	GetByTag(w http.ResponseWriter, r *http.Request)
+
+	GetFeed(w http.ResponseWriter, r *http.Request)
+
 	GetPopularToday(w http.ResponseWriter, r *http.Request)
 
 	GetPopularLastWeek(w http.ResponseWriter, r *http.Request)
@@ -31,46 +42,35 @@ type PostController interface {
 
 	GetPopularAllTime(w http.ResponseWriter, r *http.Request)
 
-	AddLike(w http.ResponseWriter, r *http.Request)
+	GetPopularInCategory(w http.ResponseWriter, r *http.Request)
 
-	DeleteLike(w http.ResponseWriter, r *http.Request)
-}
+	Vote(w http.ResponseWriter, r *http.Request)
 
-type postControllerImpl struct {
-	serv domain.PostService
-}
+	Restore(w http.ResponseWriter, r *http.Request)
 
-func (con postControllerImpl) AddLike(w http.ResponseWriter, r *http.Request) {
-	userID, err := delivery.ParseUintParam(r, "userid")
-	if err != nil {
-		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid userID provided")
-		return
-	}
-	postID, err := delivery.ParseUintParam(r, "postid")
-	if err != nil {
-		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid postID provided")
-		return
-	}
+	GetRevisions(w http.ResponseWriter, r *http.Request)
 
-	err = con.serv.AddLike(userID, postID)
-	if err == service.ErrAlreadyExisting {
-		delivery.WriteResponse(w, http.StatusConflict, "Like already exists")
-		return
-	}
-	if err == service.ErrIncorrectParameters {
-		delivery.WriteResponse(w, http.StatusBadRequest, "Incorrect parameters provided ")
-		return
-	}
-	if err == service.ErrDependencyNotSatisfied {
-		delivery.WriteResponse(w, http.StatusNotFound, "User or Post doesn't exist")
-		return
-	}
-	if err != nil {
-		delivery.WriteResponse(w, http.StatusInternalServerError, "")
-		return
-	}
+	GetRevision(w http.ResponseWriter, r *http.Request)
+
+	DiffRevisions(w http.ResponseWriter, r *http.Request)
 
-	delivery.WriteResponse(w, http.StatusCreated, "Like created successfully")
+	Rollback(w http.ResponseWriter, r *http.Request)
+
+	Lock(w http.ResponseWriter, r *http.Request)
+
+	Unlock(w http.ResponseWriter, r *http.Request)
+
+	Pin(w http.ResponseWriter, r *http.Request)
+
+	Unpin(w http.ResponseWriter, r *http.Request)
+
+	Archive(w http.ResponseWriter, r *http.Request)
+
+	Unarchive(w http.ResponseWriter, r *http.Request)
+}
+
+type postControllerImpl struct {
+	serv domain.PostService
 }
 
 func (con postControllerImpl) Create(w http.ResponseWriter, r *http.Request) {
@@ -80,9 +80,12 @@ func (con postControllerImpl) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	var createReq struct {
-		Title       string `json:"Title"`
-		Description string `json:"Description"`
-		Content     string `json:"Content"`
+		CategoryID  uint     `json:"CategoryId"`
+		Title       string   `json:"Title"`
+		Description string   `json:"Description"`
+		Content     string   `json:"Content"`
+		Tags        []string `json:"Tags"`
+		Attachments []uint   `json:"Attachments"`
 	}
 	err = delivery.ReadJSONRequest(r, &createReq)
 	if err != nil {
@@ -90,17 +93,21 @@ func (con postControllerImpl) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	id, err := con.serv.Create(userID, createReq.Title, createReq.Description, createReq.Content)
+	id, err := con.serv.Create(userID, createReq.CategoryID, createReq.Title, createReq.Description, createReq.Content, createReq.Tags, createReq.Attachments)
 	if err == service.ErrIncorrectParameters {
 		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid provided parameters")
 		return
 	}
 	if err == service.ErrDependencyNotSatisfied {
-		delivery.WriteResponse(w, http.StatusNotFound, "Unexistent user")
+		delivery.WriteResponse(w, http.StatusNotFound, "Unexistent user or category")
+		return
+	}
+	if err == service.ErrArchived {
+		delivery.WriteResponse(w, http.StatusForbidden, "Category is archived")
 		return
 	}
-	if err == service.ErrAlreadyExisting {
-		delivery.WriteResponse(w, http.StatusConflict, "Repeated title")
+	if err == service.ErrSuspended {
+		delivery.WriteResponse(w, http.StatusForbidden, "User is suspended")
 		return
 	}
 	if err != nil {
@@ -117,13 +124,18 @@ func (con postControllerImpl) Create(w http.ResponseWriter, r *http.Request) {
 }
 
 func (con postControllerImpl) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, err := delivery.ParseUintParam(r, "userid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid userID provided")
+		return
+	}
 	id, err := delivery.ParseUintParam(r, "postid")
 	if err != nil {
 		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid ID provided")
 		return
 	}
 
-	err = con.serv.Delete(id)
+	err = con.serv.Delete(userID, id)
 	if err == service.ErrIncorrectParameters {
 		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
 		return
@@ -132,6 +144,10 @@ func (con postControllerImpl) Delete(w http.ResponseWriter, r *http.Request) {
 		delivery.WriteResponse(w, http.StatusNotFound, "Post doens't exist")
 		return
 	}
+	if err == service.ErrNotAuthorized {
+		delivery.WriteResponse(w, http.StatusForbidden, "Only the author or a moderator can delete this post")
+		return
+	}
 	if err != nil {
 		delivery.WriteResponse(w, http.StatusInternalServerError, "")
 		return
@@ -140,25 +156,55 @@ func (con postControllerImpl) Delete(w http.ResponseWriter, r *http.Request) {
 	delivery.WriteResponse(w, http.StatusOK, "Post deleted successfully")
 }
 
-func (con postControllerImpl) DeleteLike(w http.ResponseWriter, r *http.Request) {
-	userID, err := delivery.ParseUintParam(r, "userid")
+func (con postControllerImpl) GetByID(w http.ResponseWriter, r *http.Request) {
+	postID, err := delivery.ParseUintParam(r, "postid")
 	if err != nil {
-		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid userID provided")
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid ID provided ")
+		return
+	}
+
+	post, err := con.serv.GetByID(postID)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
+		return
+	}
+	if err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusNotFound, "Post doesn't exist")
 		return
 	}
-	postID, err := delivery.ParseUintParam(r, "postid")
 	if err != nil {
-		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid postID provided")
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	err = con.addPostViewer(r, &post)
+	if err == service.ErrNotAuthorized {
+		delivery.WriteResponse(w, http.StatusForbidden, "Only approved followers can see this post")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteJSONResponse(w, http.StatusOK, post)
+}
+
+func (con postControllerImpl) GetByUser(w http.ResponseWriter, r *http.Request) {
+	id, err := delivery.ParseUintParam(r, "userid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid provided ID")
 		return
 	}
 
-	err = con.serv.DeleteLike(userID, postID)
+	viewerID, _ := delivery.AuthenticatedUserID(r)
+	posts, err := con.serv.GetByUser(viewerID, id)
 	if err == service.ErrIncorrectParameters {
 		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
 		return
 	}
 	if err == service.ErrNotExistingEntity {
-		delivery.WriteResponse(w, http.StatusNotFound, "Like doesn't exist")
+		delivery.WriteResponse(w, http.StatusNotFound, "No post found")
 		return
 	}
 	if err != nil {
@@ -166,13 +212,24 @@ func (con postControllerImpl) DeleteLike(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	delivery.WriteResponse(w, http.StatusOK, "Like deleted successfully")
+	posts, err = con.addViewer(r, posts)
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteJSONResponse(w, http.StatusOK, posts)
 }
 
-func (con postControllerImpl) GetByID(w http.ResponseWriter, r *http.Request) {
+func (con postControllerImpl) GetByIDAndSlug(w http.ResponseWriter, r *http.Request) {
 	postID, err := delivery.ParseUintParam(r, "postid")
 	if err != nil {
-		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid ID provided ")
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid ID provided")
+		return
+	}
+	slug, err := delivery.ParseStringParam(r, "slug")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid slug provided")
 		return
 	}
 
@@ -190,23 +247,38 @@ func (con postControllerImpl) GetByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	err = con.addPostViewer(r, &post)
+	if err == service.ErrNotAuthorized {
+		delivery.WriteResponse(w, http.StatusForbidden, "Only approved followers can see this post")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	if post.Slug != slug {
+		delivery.WriteRedirect(w, r, strings.TrimSuffix(r.URL.Path, slug)+post.Slug)
+		return
+	}
+
 	delivery.WriteJSONResponse(w, http.StatusOK, post)
 }
 
-func (con postControllerImpl) GetByUser(w http.ResponseWriter, r *http.Request) {
-	id, err := delivery.ParseUintParam(r, "userid")
+func (con postControllerImpl) GetBySlug(w http.ResponseWriter, r *http.Request) {
+	slug, err := delivery.ParseStringParam(r, "slug")
 	if err != nil {
-		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid provided ID")
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid slug provided")
 		return
 	}
 
-	posts, err := con.serv.GetByUser(id)
+	post, err := con.serv.GetBySlug(slug)
 	if err == service.ErrIncorrectParameters {
 		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
 		return
 	}
 	if err == service.ErrNotExistingEntity {
-		delivery.WriteResponse(w, http.StatusNotFound, "No post found")
+		delivery.WriteResponse(w, http.StatusNotFound, "Post doesn't exist")
 		return
 	}
 	if err != nil {
@@ -214,13 +286,9 @@ func (con postControllerImpl) GetByUser(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	delivery.WriteJSONResponse(w, http.StatusOK, posts)
-}
-
-func (con postControllerImpl) GetPopularAllTime(w http.ResponseWriter, r *http.Request) {
-	posts, err := con.serv.GetPopularAllTime()
-	if err == service.ErrNotExistingEntity {
-		delivery.WriteResponse(w, http.StatusNotFound, "No post found")
+	err = con.addPostViewer(r, &post)
+	if err == service.ErrNotAuthorized {
+		delivery.WriteResponse(w, http.StatusForbidden, "Only approved followers can see this post")
 		return
 	}
 	if err != nil {
@@ -228,11 +296,21 @@ func (con postControllerImpl) GetPopularAllTime(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	delivery.WriteJSONResponse(w, http.StatusOK, posts)
+	if post.Slug != slug {
+		delivery.WriteRedirect(w, r, strings.TrimSuffix(r.URL.Path, slug)+post.Slug)
+		return
+	}
+
+	delivery.WriteJSONResponse(w, http.StatusOK, post)
 }
 
-func (con postControllerImpl) GetPopularLastMonth(w http.ResponseWriter, r *http.Request) {
-	posts, err := con.serv.GetPopularLastMonth()
+func (con postControllerImpl) GetPopularAllTime(w http.ResponseWriter, r *http.Request) {
+	viewerID, _ := delivery.AuthenticatedUserID(r)
+	posts, err := con.serv.GetPopularAllTime(viewerID, postSortQuery(r))
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid sort provided")
+		return
+	}
 	if err == service.ErrNotExistingEntity {
 		delivery.WriteResponse(w, http.StatusNotFound, "No post found")
 		return
@@ -242,11 +320,32 @@ func (con postControllerImpl) GetPopularLastMonth(w http.ResponseWriter, r *http
 		return
 	}
 
+	posts, err = con.addViewer(r, posts)
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
 	delivery.WriteJSONResponse(w, http.StatusOK, posts)
 }
 
-func (con postControllerImpl) GetPopularLastWeek(w http.ResponseWriter, r *http.Request) {
-	posts, err := con.serv.GetPopularLastWeek()
+func (con postControllerImpl) GetFeed(w http.ResponseWriter, r *http.Request) {
+	userID, ok := delivery.AuthenticatedUserID(r)
+	if !ok {
+		delivery.WriteResponse(w, http.StatusUnauthorized, "You're not authorized to this resource")
+		return
+	}
+
+	mode := domain.FeedMode(r.URL.Query().Get("mode"))
+	if mode == "" {
+		mode = domain.FeedModeLatest
+	}
+
+	page, err := con.serv.GetFeed(userID, mode, r.URL.Query().Get("cursor"))
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
+		return
+	}
 	if err == service.ErrNotExistingEntity {
 		delivery.WriteResponse(w, http.StatusNotFound, "No post found")
 		return
@@ -256,11 +355,33 @@ func (con postControllerImpl) GetPopularLastWeek(w http.ResponseWriter, r *http.
 		return
 	}
 
-	delivery.WriteJSONResponse(w, http.StatusOK, posts)
+	page.Posts, err = con.addViewer(r, page.Posts)
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteJSONResponse(w, http.StatusOK, page)
 }
 
-func (con postControllerImpl) GetPopularToday(w http.ResponseWriter, r *http.Request) {
-	posts, err := con.serv.GetPopularToday()
+func (con postControllerImpl) GetByTag(w http.ResponseWriter, r *http.Request) {
+	tag, err := delivery.ParseStringParam(r, "name")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid tag provided")
+		return
+	}
+	page, err := delivery.ParseUintQuery(r, "page", 1)
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid page provided")
+		return
+	}
+
+	viewerID, _ := delivery.AuthenticatedUserID(r)
+	posts, err := con.serv.GetByTag(viewerID, tag, page)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
+		return
+	}
 	if err == service.ErrNotExistingEntity {
 		delivery.WriteResponse(w, http.StatusNotFound, "No post found")
 		return
@@ -270,32 +391,35 @@ func (con postControllerImpl) GetPopularToday(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	posts, err = con.addViewer(r, posts)
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
 	delivery.WriteJSONResponse(w, http.StatusOK, posts)
 }
 
-func (con postControllerImpl) UpdateContent(w http.ResponseWriter, r *http.Request) {
-	postID, err := delivery.ParseUintParam(r, "postid")
+func (con postControllerImpl) GetPopularInCategory(w http.ResponseWriter, r *http.Request) {
+	categoryID, err := delivery.ParseUintParam(r, "categoryid")
 	if err != nil {
-		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid provided ID")
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid ID provided")
 		return
 	}
-
-	var updateReq struct {
-		Content string `json:"Content"`
-	}
-	err = delivery.ReadJSONRequest(r, &updateReq)
+	period, err := delivery.ParseStringParam(r, "period")
 	if err != nil {
-		delivery.WriteResponse(w, http.StatusBadRequest, "Incorrect request format")
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid period provided")
 		return
 	}
 
-	err = con.serv.UpdateContent(postID, updateReq.Content)
+	viewerID, _ := delivery.AuthenticatedUserID(r)
+	posts, err := con.serv.GetPopularInCategory(viewerID, categoryID, domain.PopularPeriod(period), postSortQuery(r))
 	if err == service.ErrIncorrectParameters {
 		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
 		return
 	}
 	if err == service.ErrNotExistingEntity {
-		delivery.WriteResponse(w, http.StatusNotFound, "Post doesn't exist")
+		delivery.WriteResponse(w, http.StatusNotFound, "No post found")
 		return
 	}
 	if err != nil {
@@ -303,32 +427,74 @@ func (con postControllerImpl) UpdateContent(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	delivery.WriteResponse(w, http.StatusOK, "Post updated succesfully")
+	posts, err = con.addViewer(r, posts)
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteJSONResponse(w, http.StatusOK, posts)
 }
 
-func (con postControllerImpl) UpdateDescription(w http.ResponseWriter, r *http.Request) {
-	postID, err := delivery.ParseUintParam(r, "postid")
+func (con postControllerImpl) GetPopularLastMonth(w http.ResponseWriter, r *http.Request) {
+	viewerID, _ := delivery.AuthenticatedUserID(r)
+	posts, err := con.serv.GetPopularLastMonth(viewerID, postSortQuery(r))
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid sort provided")
+		return
+	}
+	if err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusNotFound, "No post found")
+		return
+	}
 	if err != nil {
-		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid provided ID")
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
 		return
 	}
 
-	var updateReq struct {
-		Description string `json:"Description"`
+	posts, err = con.addViewer(r, posts)
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteJSONResponse(w, http.StatusOK, posts)
+}
+
+func (con postControllerImpl) GetPopularLastWeek(w http.ResponseWriter, r *http.Request) {
+	viewerID, _ := delivery.AuthenticatedUserID(r)
+	posts, err := con.serv.GetPopularLastWeek(viewerID, postSortQuery(r))
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid sort provided")
+		return
+	}
+	if err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusNotFound, "No post found")
+		return
 	}
-	err = delivery.ReadJSONRequest(r, &updateReq)
 	if err != nil {
-		delivery.WriteResponse(w, http.StatusBadRequest, "Incorrect request format")
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	posts, err = con.addViewer(r, posts)
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
 		return
 	}
 
-	err = con.serv.UpdateDescription(postID, updateReq.Description)
+	delivery.WriteJSONResponse(w, http.StatusOK, posts)
+}
+
+func (con postControllerImpl) GetPopularToday(w http.ResponseWriter, r *http.Request) {
+	viewerID, _ := delivery.AuthenticatedUserID(r)
+	posts, err := con.serv.GetPopularToday(viewerID, postSortQuery(r))
 	if err == service.ErrIncorrectParameters {
-		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid sort provided")
 		return
 	}
 	if err == service.ErrNotExistingEntity {
-		delivery.WriteResponse(w, http.StatusNotFound, "Post doesn't exist")
+		delivery.WriteResponse(w, http.StatusNotFound, "No post found")
 		return
 	}
 	if err != nil {
@@ -336,10 +502,21 @@ func (con postControllerImpl) UpdateDescription(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	delivery.WriteResponse(w, http.StatusOK, "Post updated succesfully")
+	posts, err = con.addViewer(r, posts)
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteJSONResponse(w, http.StatusOK, posts)
 }
 
-func (con postControllerImpl) UpdateTitle(w http.ResponseWriter, r *http.Request) {
+func (con postControllerImpl) UpdateContent(w http.ResponseWriter, r *http.Request) {
+	userID, err := delivery.ParseUintParam(r, "userid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid userID provided")
+		return
+	}
 	postID, err := delivery.ParseUintParam(r, "postid")
 	if err != nil {
 		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid provided ID")
@@ -347,7 +524,7 @@ func (con postControllerImpl) UpdateTitle(w http.ResponseWriter, r *http.Request
 	}
 
 	var updateReq struct {
-		Title string `json:"Title"`
+		Content string `json:"Content"`
 	}
 	err = delivery.ReadJSONRequest(r, &updateReq)
 	if err != nil {
@@ -355,7 +532,7 @@ func (con postControllerImpl) UpdateTitle(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	err = con.serv.UpdateTitle(postID, updateReq.Title)
+	err = con.serv.UpdateContent(userID, postID, updateReq.Content)
 	if err == service.ErrIncorrectParameters {
 		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
 		return
@@ -364,8 +541,16 @@ func (con postControllerImpl) UpdateTitle(w http.ResponseWriter, r *http.Request
 		delivery.WriteResponse(w, http.StatusNotFound, "Post doesn't exist")
 		return
 	}
-	if err == service.ErrAlreadyExisting {
-		delivery.WriteResponse(w, http.StatusConflict, "Repeated title")
+	if err == service.ErrNotAuthorized {
+		delivery.WriteResponse(w, http.StatusForbidden, "Only the owner or a moderator can edit this post")
+		return
+	}
+	if err == service.ErrArchived {
+		delivery.WriteResponse(w, http.StatusForbidden, "Post is archived")
+		return
+	}
+	if err == service.ErrSuspended {
+		delivery.WriteResponse(w, http.StatusForbidden, "User is suspended")
 		return
 	}
 	if err != nil {
@@ -376,6 +561,512 @@ func (con postControllerImpl) UpdateTitle(w http.ResponseWriter, r *http.Request
 	delivery.WriteResponse(w, http.StatusOK, "Post updated succesfully")
 }
 
+func (con postControllerImpl) UpdateDescription(w http.ResponseWriter, r *http.Request) {
+	userID, err := delivery.ParseUintParam(r, "userid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid userID provided")
+		return
+	}
+	postID, err := delivery.ParseUintParam(r, "postid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid provided ID")
+		return
+	}
+
+	var updateReq struct {
+		Description string `json:"Description"`
+	}
+	err = delivery.ReadJSONRequest(r, &updateReq)
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Incorrect request format")
+		return
+	}
+
+	err = con.serv.UpdateDescription(userID, postID, updateReq.Description)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
+		return
+	}
+	if err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusNotFound, "Post doesn't exist")
+		return
+	}
+	if err == service.ErrNotAuthorized {
+		delivery.WriteResponse(w, http.StatusForbidden, "Only the owner or a moderator can edit this post")
+		return
+	}
+	if err == service.ErrArchived {
+		delivery.WriteResponse(w, http.StatusForbidden, "Post is archived")
+		return
+	}
+	if err == service.ErrSuspended {
+		delivery.WriteResponse(w, http.StatusForbidden, "User is suspended")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteResponse(w, http.StatusOK, "Post updated succesfully")
+}
+
+func (con postControllerImpl) UpdateTitle(w http.ResponseWriter, r *http.Request) {
+	userID, err := delivery.ParseUintParam(r, "userid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid userID provided")
+		return
+	}
+	postID, err := delivery.ParseUintParam(r, "postid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid provided ID")
+		return
+	}
+
+	var updateReq struct {
+		Title string `json:"Title"`
+	}
+	err = delivery.ReadJSONRequest(r, &updateReq)
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Incorrect request format")
+		return
+	}
+
+	err = con.serv.UpdateTitle(userID, postID, updateReq.Title)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
+		return
+	}
+	if err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusNotFound, "Post doesn't exist")
+		return
+	}
+	if err == service.ErrNotAuthorized {
+		delivery.WriteResponse(w, http.StatusForbidden, "Only the owner or a moderator can edit this post")
+		return
+	}
+	if err == service.ErrArchived {
+		delivery.WriteResponse(w, http.StatusForbidden, "Post is archived")
+		return
+	}
+	if err == service.ErrSuspended {
+		delivery.WriteResponse(w, http.StatusForbidden, "User is suspended")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteResponse(w, http.StatusOK, "Post updated succesfully")
+}
+
+func (con postControllerImpl) UpdateTags(w http.ResponseWriter, r *http.Request) {
+	userID, err := delivery.ParseUintParam(r, "userid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid userID provided")
+		return
+	}
+	postID, err := delivery.ParseUintParam(r, "postid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid provided ID")
+		return
+	}
+
+	var updateReq struct {
+		Tags []string `json:"Tags"`
+	}
+	err = delivery.ReadJSONRequest(r, &updateReq)
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Incorrect request format")
+		return
+	}
+
+	err = con.serv.UpdateTags(userID, postID, updateReq.Tags)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
+		return
+	}
+	if err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusNotFound, "Post doesn't exist")
+		return
+	}
+	if err == service.ErrNotAuthorized {
+		delivery.WriteResponse(w, http.StatusForbidden, "Only the owner or a moderator can edit this post")
+		return
+	}
+	if err == service.ErrArchived {
+		delivery.WriteResponse(w, http.StatusForbidden, "Post is archived")
+		return
+	}
+	if err == service.ErrSuspended {
+		delivery.WriteResponse(w, http.StatusForbidden, "User is suspended")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteResponse(w, http.StatusOK, "Post updated succesfully")
+}
+
+func (con postControllerImpl) Restore(w http.ResponseWriter, r *http.Request) {
+	userID, err := delivery.ParseUintParam(r, "userid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid userID provided")
+		return
+	}
+	postID, err := delivery.ParseUintParam(r, "postid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid postID provided")
+		return
+	}
+
+	err = con.serv.Restore(userID, postID)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
+		return
+	}
+	if err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusNotFound, "Deleted post doesn't exist")
+		return
+	}
+	if err == service.ErrNotAuthorized {
+		delivery.WriteResponse(w, http.StatusForbidden, "Only the author or a moderator can restore this post")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteResponse(w, http.StatusOK, "Post restored successfully")
+}
+
+func (con postControllerImpl) GetRevisions(w http.ResponseWriter, r *http.Request) {
+	postID, err := delivery.ParseUintParam(r, "postid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid ID provided")
+		return
+	}
+
+	if !con.checkPostVisible(w, r, postID) {
+		return
+	}
+
+	revisions, err := con.serv.GetRevisions(postID)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
+		return
+	}
+	if err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusNotFound, "No revisions found")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteJSONResponse(w, http.StatusOK, revisions)
+}
+
+func (con postControllerImpl) GetRevision(w http.ResponseWriter, r *http.Request) {
+	postID, err := delivery.ParseUintParam(r, "postid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid ID provided")
+		return
+	}
+	revision, err := delivery.ParseUintParam(r, "revision")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid revision provided")
+		return
+	}
+
+	if !con.checkPostVisible(w, r, postID) {
+		return
+	}
+
+	postRevision, err := con.serv.GetRevision(postID, revision)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
+		return
+	}
+	if err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusNotFound, "Revision doesn't exist")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteJSONResponse(w, http.StatusOK, postRevision)
+}
+
+func (con postControllerImpl) DiffRevisions(w http.ResponseWriter, r *http.Request) {
+	postID, err := delivery.ParseUintParam(r, "postid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid ID provided")
+		return
+	}
+	fromRevision, err := delivery.ParseUintParam(r, "revision")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid revision provided")
+		return
+	}
+	toRevision, err := delivery.ParseUintParam(r, "otherrevision")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid revision provided")
+		return
+	}
+
+	if !con.checkPostVisible(w, r, postID) {
+		return
+	}
+
+	diff, err := con.serv.DiffRevisions(postID, fromRevision, toRevision)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
+		return
+	}
+	if err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusNotFound, "Revision doesn't exist")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteJSONResponse(w, http.StatusOK, diff)
+}
+
+func (con postControllerImpl) Rollback(w http.ResponseWriter, r *http.Request) {
+	userID, err := delivery.ParseUintParam(r, "userid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid userID provided")
+		return
+	}
+	postID, err := delivery.ParseUintParam(r, "postid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid postID provided")
+		return
+	}
+	revision, err := delivery.ParseUintParam(r, "revision")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid revision provided")
+		return
+	}
+
+	err = con.serv.Rollback(userID, postID, revision)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
+		return
+	}
+	if err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusNotFound, "Post or revision doesn't exist")
+		return
+	}
+	if err == service.ErrNotAuthorized {
+		delivery.WriteResponse(w, http.StatusForbidden, "Only the owner can roll back this post")
+		return
+	}
+	if err == service.ErrArchived {
+		delivery.WriteResponse(w, http.StatusForbidden, "Post is archived")
+		return
+	}
+	if err == service.ErrSuspended {
+		delivery.WriteResponse(w, http.StatusForbidden, "User is suspended")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteResponse(w, http.StatusOK, "Post rolled back successfully")
+}
+
+func (con postControllerImpl) Vote(w http.ResponseWriter, r *http.Request) {
+	userID, ok := delivery.AuthenticatedUserID(r)
+	if !ok {
+		delivery.WriteResponse(w, http.StatusUnauthorized, "You're not authorized to this resource")
+		return
+	}
+	postID, err := delivery.ParseUintParam(r, "postid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid postID provided")
+		return
+	}
+	var voteReq struct {
+		Value *int `json:"Value"`
+	}
+	err = delivery.ReadJSONRequest(r, &voteReq)
+	if err != nil || voteReq.Value == nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Incorrect request format")
+		return
+	}
+
+	err = con.serv.Vote(userID, postID, *voteReq.Value)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
+		return
+	}
+	if err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusNotFound, "Post doesn't exist")
+		return
+	}
+	if err == service.ErrDependencyNotSatisfied {
+		delivery.WriteResponse(w, http.StatusNotFound, "User doesn't exist")
+		return
+	}
+	if err == service.ErrNotAuthorized {
+		delivery.WriteResponse(w, http.StatusForbidden, "Only approved followers can vote on this post")
+		return
+	}
+	if err == service.ErrArchived {
+		delivery.WriteResponse(w, http.StatusForbidden, "Post is archived")
+		return
+	}
+	if err == service.ErrSuspended {
+		delivery.WriteResponse(w, http.StatusForbidden, "User is suspended")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteResponse(w, http.StatusOK, "Vote registered successfully")
+}
+
+func (con postControllerImpl) Lock(w http.ResponseWriter, r *http.Request) {
+	con.updateState(w, r, con.serv.Lock, "Post locked successfully")
+}
+
+func (con postControllerImpl) Unlock(w http.ResponseWriter, r *http.Request) {
+	con.updateState(w, r, con.serv.Unlock, "Post unlocked successfully")
+}
+
+func (con postControllerImpl) Pin(w http.ResponseWriter, r *http.Request) {
+	con.updateState(w, r, con.serv.Pin, "Post pinned successfully")
+}
+
+func (con postControllerImpl) Unpin(w http.ResponseWriter, r *http.Request) {
+	con.updateState(w, r, con.serv.Unpin, "Post unpinned successfully")
+}
+
+func (con postControllerImpl) Archive(w http.ResponseWriter, r *http.Request) {
+	con.updateState(w, r, con.serv.Archive, "Post archived successfully")
+}
+
+func (con postControllerImpl) Unarchive(w http.ResponseWriter, r *http.Request) {
+	con.updateState(w, r, con.serv.Unarchive, "Post unarchived successfully")
+}
+
+func (con postControllerImpl) updateState(w http.ResponseWriter, r *http.Request, update func(userID, postID uint) error, success string) {
+	userID, err := delivery.ParseUintParam(r, "userid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid userID provided")
+		return
+	}
+	postID, err := delivery.ParseUintParam(r, "postid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid postID provided")
+		return
+	}
+
+	err = update(userID, postID)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
+		return
+	}
+	if err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusNotFound, "Post doesn't exist")
+		return
+	}
+	if err == service.ErrNotAuthorized {
+		delivery.WriteResponse(w, http.StatusForbidden, "Only the owner or a moderator can change this post")
+		return
+	}
+	if err == service.ErrSuspended {
+		delivery.WriteResponse(w, http.StatusForbidden, "User is suspended")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteResponse(w, http.StatusOK, success)
+}
+
+// Adds the viewer fields when the request is authenticated
+func (con postControllerImpl) addViewer(r *http.Request, posts []domain.Post) ([]domain.Post, error) {
+	viewerID, ok := delivery.AuthenticatedUserID(r)
+	if !ok {
+		return posts, nil
+	}
+
+	return posts, con.serv.AddViewer(viewerID, posts)
+}
+
+// Fails with ErrNotAuthorized when the viewer can't see the post and adds the viewer fields when the request is authenticated
+func (con postControllerImpl) addPostViewer(r *http.Request, post *domain.Post) error {
+	viewerID, ok := delivery.AuthenticatedUserID(r)
+	err := con.serv.CheckVisible(viewerID, post.OwnerID)
+	if err != nil || !ok {
+		return err
+	}
+
+	posts := []domain.Post{*post}
+	err = con.serv.AddViewer(viewerID, posts)
+	*post = posts[0]
+	return err
+}
+
+// Answers and returns false when the post doesn't exist or the viewer can't see it
+func (con postControllerImpl) checkPostVisible(w http.ResponseWriter, r *http.Request, postID uint) bool {
+	post, err := con.serv.GetByID(postID)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
+		return false
+	}
+	if err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusNotFound, "Post doesn't exist")
+		return false
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return false
+	}
+
+	viewerID, _ := delivery.AuthenticatedUserID(r)
+	err = con.serv.CheckVisible(viewerID, post.OwnerID)
+	if err == service.ErrNotAuthorized {
+		delivery.WriteResponse(w, http.StatusForbidden, "Only approved followers can see this post")
+		return false
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return false
+	}
+
+	return true
+}
+
+// Popular posts are sorted by hot score unless ?sort= says otherwise
+func postSortQuery(r *http.Request) domain.PostSort {
+	order := domain.PostSort(r.URL.Query().Get("sort"))
+	if order == "" {
+		return domain.PostSortHot
+	}
+
+	return order
+}
+
 func NewPostController(serv domain.PostService) PostController {
 	return postControllerImpl{serv: serv}
 }
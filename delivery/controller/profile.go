@@ -36,6 +36,26 @@ type ProfileController interface {
 	AddFollow(w http.ResponseWriter, r *http.Request)
 
 	DeleteFollow(w http.ResponseWriter, r *http.Request)
+
+	Block(w http.ResponseWriter, r *http.Request)
+
+	Unblock(w http.ResponseWriter, r *http.Request)
+
+	GetBlocked(w http.ResponseWriter, r *http.Request)
+
+	Mute(w http.ResponseWriter, r *http.Request)
+
+	Unmute(w http.ResponseWriter, r *http.Request)
+
+	GetMuted(w http.ResponseWriter, r *http.Request)
+
+	UpdatePrivacy(w http.ResponseWriter, r *http.Request)
+
+	GetFollowRequests(w http.ResponseWriter, r *http.Request)
+
+	ApproveFollowRequest(w http.ResponseWriter, r *http.Request)
+
+	RejectFollowRequest(w http.ResponseWriter, r *http.Request)
 }
 
 type profileControllerImpl struct {
@@ -55,7 +75,7 @@ func (con profileControllerImpl) AddFollow(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	err = con.serv.AddFollow(userID, followedID)
+	pending, err := con.serv.AddFollow(userID, followedID)
 	if err == service.ErrAlreadyExisting {
 		delivery.WriteResponse(w, http.StatusConflict, "This follow already exists")
 		return
@@ -68,11 +88,20 @@ func (con profileControllerImpl) AddFollow(w http.ResponseWriter, r *http.Reques
 		delivery.WriteResponse(w, http.StatusBadRequest, "Incorrect parameters provided")
 		return
 	}
+	if err == service.ErrNotAuthorized {
+		delivery.WriteResponse(w, http.StatusForbidden, "You can't follow this profile")
+		return
+	}
 	if err != nil {
 		delivery.WriteResponse(w, http.StatusInternalServerError, "")
 		return
 	}
 
+	if pending {
+		delivery.WriteResponse(w, http.StatusAccepted, "Follow request sent")
+		return
+	}
+
 	delivery.WriteResponse(w, http.StatusCreated, "Follow successfully created")
 }
 
@@ -324,6 +353,10 @@ func (con profileControllerImpl) UpdateBackgroundPath(w http.ResponseWriter, r *
 	}
 
 	err = con.serv.UpdateBackgroundPath(id, updateReq.BackgroundPath)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Path has to be an uploaded image")
+		return
+	}
 	if err == service.ErrNotExistingEntity {
 		delivery.WriteResponse(w, http.StatusNotFound, "Profile with that ID doesn't exist")
 		return
@@ -382,6 +415,10 @@ func (con profileControllerImpl) UpdatePicturePath(w http.ResponseWriter, r *htt
 	}
 
 	err = con.serv.UpdatePicturePath(id, updateReq.PicturePath)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Path has to be an uploaded image")
+		return
+	}
 	if err == service.ErrNotExistingEntity {
 		delivery.WriteResponse(w, http.StatusNotFound, "Profile with that ID doesn't exist")
 		return
@@ -427,6 +464,295 @@ func (con profileControllerImpl) UpdateTagName(w http.ResponseWriter, r *http.Re
 	delivery.WriteResponse(w, http.StatusOK, "Profile updated successfully")
 }
 
+func (con profileControllerImpl) Block(w http.ResponseWriter, r *http.Request) {
+	userID, err := delivery.ParseUintParam(r, "userid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid userID provided")
+		return
+	}
+
+	blockedID, err := delivery.ParseUintParam(r, "blockedid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid userID provided")
+		return
+	}
+
+	err = con.serv.Block(userID, blockedID)
+	if err == service.ErrAlreadyExisting {
+		delivery.WriteResponse(w, http.StatusConflict, "This block already exists")
+		return
+	}
+	if err == service.ErrDependencyNotSatisfied {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Profile doesn't exist")
+		return
+	}
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Incorrect parameters provided")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteResponse(w, http.StatusCreated, "Profile successfully blocked")
+}
+
+func (con profileControllerImpl) Unblock(w http.ResponseWriter, r *http.Request) {
+	userID, err := delivery.ParseUintParam(r, "userid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid userID provided")
+		return
+	}
+
+	blockedID, err := delivery.ParseUintParam(r, "blockedid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid userID provided")
+		return
+	}
+
+	err = con.serv.Unblock(userID, blockedID)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Incorrect parameters provided")
+		return
+	}
+	if err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusNotFound, "This block doesn't exist")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteResponse(w, http.StatusOK, "Profile successfully unblocked")
+}
+
+func (con profileControllerImpl) GetBlocked(w http.ResponseWriter, r *http.Request) {
+	id, err := delivery.ParseUintParam(r, "userid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid id provided")
+		return
+	}
+
+	profiles, err := con.serv.GetBlocked(id)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Incorrect parameters provided")
+		return
+	}
+	if err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusNotFound, "No blocked profiles found")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteJSONResponse(w, http.StatusOK, profiles)
+}
+
+func (con profileControllerImpl) Mute(w http.ResponseWriter, r *http.Request) {
+	userID, err := delivery.ParseUintParam(r, "userid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid userID provided")
+		return
+	}
+
+	mutedID, err := delivery.ParseUintParam(r, "mutedid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid userID provided")
+		return
+	}
+
+	err = con.serv.Mute(userID, mutedID)
+	if err == service.ErrAlreadyExisting {
+		delivery.WriteResponse(w, http.StatusConflict, "This mute already exists")
+		return
+	}
+	if err == service.ErrDependencyNotSatisfied {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Profile doesn't exist")
+		return
+	}
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Incorrect parameters provided")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteResponse(w, http.StatusCreated, "Profile successfully muted")
+}
+
+func (con profileControllerImpl) Unmute(w http.ResponseWriter, r *http.Request) {
+	userID, err := delivery.ParseUintParam(r, "userid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid userID provided")
+		return
+	}
+
+	mutedID, err := delivery.ParseUintParam(r, "mutedid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid userID provided")
+		return
+	}
+
+	err = con.serv.Unmute(userID, mutedID)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Incorrect parameters provided")
+		return
+	}
+	if err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusNotFound, "This mute doesn't exist")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteResponse(w, http.StatusOK, "Profile successfully unmuted")
+}
+
+func (con profileControllerImpl) GetMuted(w http.ResponseWriter, r *http.Request) {
+	id, err := delivery.ParseUintParam(r, "userid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid id provided")
+		return
+	}
+
+	profiles, err := con.serv.GetMuted(id)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Incorrect parameters provided")
+		return
+	}
+	if err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusNotFound, "No muted profiles found")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteJSONResponse(w, http.StatusOK, profiles)
+}
+
+func (con profileControllerImpl) UpdatePrivacy(w http.ResponseWriter, r *http.Request) {
+	id, err := delivery.ParseUintParam(r, "userid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid id provided")
+		return
+	}
+
+	var updateReq struct {
+		IsPrivate bool `json:"IsPrivate"`
+	}
+	err = delivery.ReadJSONRequest(r, &updateReq)
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Incorrect request format")
+		return
+	}
+
+	err = con.serv.UpdatePrivacy(id, updateReq.IsPrivate)
+	if err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusNotFound, "Profile with that ID doesn't exist")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteResponse(w, http.StatusOK, "Profile updated successfully")
+}
+
+func (con profileControllerImpl) GetFollowRequests(w http.ResponseWriter, r *http.Request) {
+	id, err := delivery.ParseUintParam(r, "userid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid id provided")
+		return
+	}
+
+	profiles, err := con.serv.GetFollowRequests(id)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Incorrect parameters provided")
+		return
+	}
+	if err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusNotFound, "No follow requests found")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteJSONResponse(w, http.StatusOK, profiles)
+}
+
+func (con profileControllerImpl) ApproveFollowRequest(w http.ResponseWriter, r *http.Request) {
+	userID, err := delivery.ParseUintParam(r, "userid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid userID provided")
+		return
+	}
+
+	requesterID, err := delivery.ParseUintParam(r, "requesterid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid userID provided")
+		return
+	}
+
+	err = con.serv.ApproveFollowRequest(userID, requesterID)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Incorrect parameters provided")
+		return
+	}
+	if err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusNotFound, "This follow request doesn't exist")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteResponse(w, http.StatusOK, "Follow request successfully approved")
+}
+
+func (con profileControllerImpl) RejectFollowRequest(w http.ResponseWriter, r *http.Request) {
+	userID, err := delivery.ParseUintParam(r, "userid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid userID provided")
+		return
+	}
+
+	requesterID, err := delivery.ParseUintParam(r, "requesterid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid userID provided")
+		return
+	}
+
+	err = con.serv.RejectFollowRequest(userID, requesterID)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Incorrect parameters provided")
+		return
+	}
+	if err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusNotFound, "This follow request doesn't exist")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteResponse(w, http.StatusOK, "Follow request successfully rejected")
+}
+
 func NewProfileController(serv domain.ProfileService) ProfileController {
 	return profileControllerImpl{serv: serv}
 }
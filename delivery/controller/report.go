@@ -0,0 +1,231 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/AlejandroJorge/forum-rest-api/delivery"
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/service"
+)
+
+type ReportController interface {
+	ReportPost(w http.ResponseWriter, r *http.Request)
+	ReportComment(w http.ResponseWriter, r *http.Request)
+	GetQueue(w http.ResponseWriter, r *http.Request)
+	GetByID(w http.ResponseWriter, r *http.Request)
+	Act(w http.ResponseWriter, r *http.Request)
+	GetLog(w http.ResponseWriter, r *http.Request)
+}
+
+type reportControllerImpl struct {
+	serv domain.ReportService
+}
+
+func (con reportControllerImpl) ReportPost(w http.ResponseWriter, r *http.Request) {
+	con.report(w, r, domain.ReportTargetPost, "postid")
+}
+
+func (con reportControllerImpl) ReportComment(w http.ResponseWriter, r *http.Request) {
+	con.report(w, r, domain.ReportTargetComment, "commentid")
+}
+
+func (con reportControllerImpl) report(w http.ResponseWriter, r *http.Request, targetType domain.ReportTarget, param string) {
+	userID, ok := delivery.AuthenticatedUserID(r)
+	if !ok {
+		delivery.WriteResponse(w, http.StatusUnauthorized, "You're not authorized to this resource")
+		return
+	}
+	targetID, err := delivery.ParseUintParam(r, param)
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid ID provided")
+		return
+	}
+	var reportReq struct {
+		Reason  domain.ReportReason `json:"Reason"`
+		Details string              `json:"Details"`
+	}
+	err = delivery.ReadJSONRequest(r, &reportReq)
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Incorrect request format")
+		return
+	}
+
+	id, err := con.serv.Report(userID, targetType, targetID, reportReq.Reason, reportReq.Details)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
+		return
+	}
+	if err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusNotFound, "Reported content doesn't exist")
+		return
+	}
+	if err == service.ErrAlreadyExisting {
+		delivery.WriteResponse(w, http.StatusConflict, "You already reported this content")
+		return
+	}
+	if err == service.ErrDependencyNotSatisfied {
+		delivery.WriteResponse(w, http.StatusNotFound, "User doesn't exist")
+		return
+	}
+	if err == service.ErrSuspended {
+		delivery.WriteResponse(w, http.StatusForbidden, "User is suspended")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	response := struct {
+		ID uint `json:"ID"`
+	}{
+		ID: id,
+	}
+	delivery.WriteJSONResponse(w, http.StatusCreated, response)
+}
+
+func (con reportControllerImpl) GetQueue(w http.ResponseWriter, r *http.Request) {
+	userID, ok := delivery.AuthenticatedUserID(r)
+	if !ok {
+		delivery.WriteResponse(w, http.StatusUnauthorized, "You're not authorized to this resource")
+		return
+	}
+	page, err := delivery.ParseUintQuery(r, "page", 1)
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid page provided")
+		return
+	}
+	status := domain.ReportStatus(r.URL.Query().Get("status"))
+	if status == "" {
+		status = domain.ReportStatusOpen
+	}
+
+	reports, err := con.serv.GetQueue(userID, status, page)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
+		return
+	}
+	if err == service.ErrNotAuthorized || err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusForbidden, "Only moderators can review reports")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteJSONResponse(w, http.StatusOK, reports)
+}
+
+func (con reportControllerImpl) GetByID(w http.ResponseWriter, r *http.Request) {
+	userID, ok := delivery.AuthenticatedUserID(r)
+	if !ok {
+		delivery.WriteResponse(w, http.StatusUnauthorized, "You're not authorized to this resource")
+		return
+	}
+	reportID, err := delivery.ParseUintParam(r, "reportid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid reportID provided")
+		return
+	}
+
+	report, err := con.serv.GetByID(userID, reportID)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
+		return
+	}
+	if err == service.ErrNotAuthorized {
+		delivery.WriteResponse(w, http.StatusForbidden, "Only moderators can review reports")
+		return
+	}
+	if err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusNotFound, "Report doesn't exist")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteJSONResponse(w, http.StatusOK, report)
+}
+
+func (con reportControllerImpl) Act(w http.ResponseWriter, r *http.Request) {
+	userID, ok := delivery.AuthenticatedUserID(r)
+	if !ok {
+		delivery.WriteResponse(w, http.StatusUnauthorized, "You're not authorized to this resource")
+		return
+	}
+	reportID, err := delivery.ParseUintParam(r, "reportid")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid reportID provided")
+		return
+	}
+	var actReq struct {
+		Action domain.ModerationAction `json:"Action"`
+		Note   string                  `json:"Note"`
+		Days   uint                    `json:"Days"`
+	}
+	err = delivery.ReadJSONRequest(r, &actReq)
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Incorrect request format")
+		return
+	}
+
+	err = con.serv.Act(userID, reportID, actReq.Action, actReq.Note, actReq.Days)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
+		return
+	}
+	if err == service.ErrNotAuthorized {
+		delivery.WriteResponse(w, http.StatusForbidden, "Only moderators can act on reports")
+		return
+	}
+	if err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusNotFound, "Report or reported content doesn't exist")
+		return
+	}
+	if err == service.ErrArchived {
+		delivery.WriteResponse(w, http.StatusConflict, "Report is already resolved")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteResponse(w, http.StatusOK, "Report resolved")
+}
+
+func (con reportControllerImpl) GetLog(w http.ResponseWriter, r *http.Request) {
+	userID, ok := delivery.AuthenticatedUserID(r)
+	if !ok {
+		delivery.WriteResponse(w, http.StatusUnauthorized, "You're not authorized to this resource")
+		return
+	}
+	page, err := delivery.ParseUintQuery(r, "page", 1)
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid page provided")
+		return
+	}
+
+	entries, err := con.serv.GetLog(userID, page)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
+		return
+	}
+	if err == service.ErrNotAuthorized || err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusForbidden, "Only moderators can review the moderation log")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteJSONResponse(w, http.StatusOK, entries)
+}
+
+func NewReportController(serv domain.ReportService) ReportController {
+	return reportControllerImpl{serv: serv}
+}
@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/AlejandroJorge/forum-rest-api/delivery"
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/service"
+)
+
+type SearchController interface {
+	Search(w http.ResponseWriter, r *http.Request)
+}
+
+type searchControllerImpl struct {
+	serv domain.SearchService
+}
+
+func (con searchControllerImpl) Search(w http.ResponseWriter, r *http.Request) {
+	searchType := domain.SearchType(r.URL.Query().Get("type"))
+	if searchType == "" {
+		searchType = domain.SearchTypePosts
+	}
+
+	var filter domain.SearchFilter
+	var err error
+	filter.AuthorID, err = delivery.ParseUintQuery(r, "author", 0)
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid author provided")
+		return
+	}
+	filter.CategoryID, err = delivery.ParseUintQuery(r, "category", 0)
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid category provided")
+		return
+	}
+	filter.From, err = delivery.ParseDateQuery(r, "from")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid from date provided")
+		return
+	}
+	filter.To, err = delivery.ParseDateQuery(r, "to")
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid to date provided")
+		return
+	}
+	if !filter.To.IsZero() {
+		// The to date is inclusive
+		filter.To = filter.To.AddDate(0, 0, 1)
+	}
+	filter.ViewerID, _ = delivery.AuthenticatedUserID(r)
+	page, err := delivery.ParseUintQuery(r, "page", 1)
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid page provided")
+		return
+	}
+
+	results, err := con.serv.Search(r.URL.Query().Get("q"), searchType, filter, page)
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid parameters provided")
+		return
+	}
+	if err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusNotFound, "No result found")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteJSONResponse(w, http.StatusOK, results)
+}
+
+func NewSearchController(serv domain.SearchService) SearchController {
+	return searchControllerImpl{serv: serv}
+}
@@ -0,0 +1,157 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/AlejandroJorge/forum-rest-api/config"
+	"github.com/AlejandroJorge/forum-rest-api/delivery"
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/logging"
+	"github.com/AlejandroJorge/forum-rest-api/service"
+)
+
+type StreamController interface {
+	Subscribe(w http.ResponseWriter, r *http.Request)
+}
+
+type streamControllerImpl struct {
+	postServ domain.PostService
+	bus      domain.EventBus
+}
+
+// Streams the events of the requested posts, profiles and the user's own notifications as Server-Sent Events until the client leaves
+// Posts and profiles of private profiles are only streamed to their approved followers
+func (con streamControllerImpl) Subscribe(w http.ResponseWriter, r *http.Request) {
+	viewerID, authenticated := delivery.AuthenticatedUserID(r)
+
+	var topics []string
+	query := r.URL.Query()
+	for _, key := range []string{"post", "profile"} {
+		for _, value := range query[key] {
+			id, err := strconv.ParseUint(value, 10, 0)
+			if err != nil || id == 0 {
+				delivery.WriteResponse(w, http.StatusBadRequest, "Invalid "+key+" provided")
+				return
+			}
+
+			ownerID := uint(id)
+			if key == "post" {
+				post, err := con.postServ.GetByID(uint(id))
+				if err == service.ErrNotExistingEntity {
+					delivery.WriteResponse(w, http.StatusNotFound, "Post doesn't exist")
+					return
+				}
+				if err != nil {
+					delivery.WriteResponse(w, http.StatusInternalServerError, "")
+					return
+				}
+				ownerID = post.OwnerID
+			}
+
+			err = con.postServ.CheckVisible(viewerID, ownerID)
+			if err == service.ErrNotAuthorized {
+				delivery.WriteResponse(w, http.StatusForbidden, "Only approved followers can follow this "+key)
+				return
+			}
+			if err != nil {
+				delivery.WriteResponse(w, http.StatusInternalServerError, "")
+				return
+			}
+
+			if key == "post" {
+				topics = append(topics, domain.PostTopic(uint(id)))
+			} else {
+				topics = append(topics, domain.ProfileTopic(uint(id)))
+			}
+		}
+	}
+
+	if query.Get("notifications") == "true" {
+		if !authenticated {
+			delivery.WriteResponse(w, http.StatusUnauthorized, "You're not authorized to this resource")
+			return
+		}
+		topics = append(topics, domain.NotificationsTopic(viewerID))
+	}
+
+	if len(topics) == 0 {
+		delivery.WriteResponse(w, http.StatusBadRequest, "No post, profile or notifications to subscribe to")
+		return
+	}
+
+	var lastEventID uint64
+	if header := r.Header.Get("Last-Event-ID"); header != "" {
+		var err error
+		lastEventID, err = strconv.ParseUint(header, 10, 64)
+		if err != nil {
+			delivery.WriteResponse(w, http.StatusBadRequest, "Invalid Last-Event-ID provided")
+			return
+		}
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	missed, subscription := con.bus.Subscribe(topics, lastEventID)
+	defer subscription.Unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	logging.LogRawResponse(http.StatusOK, "Event stream opened")
+
+	for _, event := range missed {
+		if writeEvent(w, event) != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(time.Duration(config.GetParams().StreamHeartbeatSeconds) * time.Second)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			_, err := fmt.Fprint(w, ": heartbeat\n\n")
+			if err != nil {
+				return
+			}
+		case event, ok := <-subscription.Events():
+			// The subscriber fell behind, the client resumes from the last event it got when reconnecting
+			if !ok {
+				return
+			}
+			if writeEvent(w, event) != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+func writeEvent(w http.ResponseWriter, event domain.Event) error {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
+
+func NewStreamController(postServ domain.PostService, bus domain.EventBus) StreamController {
+	return streamControllerImpl{postServ: postServ, bus: bus}
+}
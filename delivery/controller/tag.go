@@ -0,0 +1,55 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/AlejandroJorge/forum-rest-api/delivery"
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/service"
+)
+
+type TagController interface {
+	Autocomplete(w http.ResponseWriter, r *http.Request)
+
+	GetTrending(w http.ResponseWriter, r *http.Request)
+}
+
+type tagControllerImpl struct {
+	serv domain.TagService
+}
+
+func (con tagControllerImpl) Autocomplete(w http.ResponseWriter, r *http.Request) {
+	tags, err := con.serv.Autocomplete(r.URL.Query().Get("q"))
+	if err == service.ErrIncorrectParameters {
+		delivery.WriteResponse(w, http.StatusBadRequest, "Invalid prefix provided")
+		return
+	}
+	if err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusNotFound, "No tag found")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteJSONResponse(w, http.StatusOK, tags)
+}
+
+func (con tagControllerImpl) GetTrending(w http.ResponseWriter, r *http.Request) {
+	tags, err := con.serv.GetTrending()
+	if err == service.ErrNotExistingEntity {
+		delivery.WriteResponse(w, http.StatusNotFound, "No tag found")
+		return
+	}
+	if err != nil {
+		delivery.WriteResponse(w, http.StatusInternalServerError, "")
+		return
+	}
+
+	delivery.WriteJSONResponse(w, http.StatusOK, tags)
+}
+
+func NewTagController(serv domain.TagService) TagController {
+	return tagControllerImpl{serv: serv}
+}
@@ -43,3 +43,51 @@ func Auth(next http.HandlerFunc) http.HandlerFunc {
 		next(w, r)
 	}
 }
+
+// Identifies the user from the auth cookie for routes that don't carry a user ID
+func Authenticated(next http.HandlerFunc) http.HandlerFunc {
+	serv := service.NewUserService(repository.NewSQLiteUserRepository(config.SQLiteDatabase()))
+	return func(w http.ResponseWriter, r *http.Request) {
+		authCookie, err := r.Cookie("jwtToken")
+		if err != nil {
+			delivery.WriteResponse(w, http.StatusBadRequest, "No auth cookie provided")
+			return
+		}
+
+		id, err := serv.Identify(authCookie.Value)
+		if err == service.ErrNotValidCredentials {
+			delivery.WriteResponse(w, http.StatusUnauthorized, "You're not authorized to this resource")
+			return
+		}
+		if err != nil {
+			delivery.WriteResponse(w, http.StatusInternalServerError, "")
+			return
+		}
+
+		next(w, delivery.WithAuthenticatedUser(r, id))
+	}
+}
+
+// Identifies the user from the auth cookie when there's a valid one, anyone else goes through anonymously
+func OptionallyAuthenticated(next http.HandlerFunc) http.HandlerFunc {
+	serv := service.NewUserService(repository.NewSQLiteUserRepository(config.SQLiteDatabase()))
+	return func(w http.ResponseWriter, r *http.Request) {
+		authCookie, err := r.Cookie("jwtToken")
+		if err != nil {
+			next(w, r)
+			return
+		}
+
+		id, err := serv.Identify(authCookie.Value)
+		if err == service.ErrNotValidCredentials {
+			next(w, r)
+			return
+		}
+		if err != nil {
+			delivery.WriteResponse(w, http.StatusInternalServerError, "")
+			return
+		}
+
+		next(w, delivery.WithAuthenticatedUser(r, id))
+	}
+}
@@ -4,10 +4,13 @@ import (
 	"database/sql"
 	"net/http"
 
+	"github.com/AlejandroJorge/forum-rest-api/config"
 	"github.com/AlejandroJorge/forum-rest-api/delivery/controller"
 	"github.com/AlejandroJorge/forum-rest-api/delivery/middleware"
+	"github.com/AlejandroJorge/forum-rest-api/events"
 	"github.com/AlejandroJorge/forum-rest-api/repository"
 	"github.com/AlejandroJorge/forum-rest-api/service"
+	"github.com/AlejandroJorge/forum-rest-api/storage"
 	"github.com/gorilla/mux"
 )
 
@@ -30,8 +33,20 @@ func initializeRouter(router *mux.Router, db *sql.DB) {
 
 	initializeUserRoutes(apiRouter, db)
 	initializeProfileRoutes(apiRouter, db)
+	initializeCategoryRoutes(apiRouter, db)
 	initializePostRoutes(apiRouter, db)
 	initializeCommentRoutes(apiRouter, db)
+	initializeTagRoutes(apiRouter, db)
+	initializeLikeRoutes(apiRouter, db)
+	initializeMentionRoutes(apiRouter, db)
+	initializeMediaRoutes(apiRouter, db)
+	initializeNotificationRoutes(apiRouter, db)
+	initializeConversationRoutes(apiRouter, db)
+	initializeReportRoutes(apiRouter, db)
+	initializeStreamRoutes(apiRouter, db)
+	if config.SearchEnabled() {
+		initializeSearchRoutes(apiRouter, db)
+	}
 }
 
 func initializeUserRoutes(router *mux.Router, db *sql.DB) {
@@ -59,8 +74,10 @@ func initializeUserRoutes(router *mux.Router, db *sql.DB) {
 }
 
 func initializeProfileRoutes(router *mux.Router, db *sql.DB) {
+	mediaRepository := repository.NewSQLiteMediaRepository(db)
+	notificationRepository := repository.NewSQLiteNotificationRepository(db)
 	repository := repository.NewSQLiteProfileRepository(db)
-	service := service.NewProfileService(repository)
+	service := service.NewProfileService(repository, mediaRepository, notificationRepository, events.DefaultBus())
 	controller := controller.NewProfileController(service)
 
 	router.HandleFunc("/users/{userid:[0-9]+}/profiles",
@@ -102,35 +119,122 @@ func initializeProfileRoutes(router *mux.Router, db *sql.DB) {
 	router.HandleFunc("/profiles/{userid:[0-9]+}/follows/{followedid:[0-9]+}",
 		middleware.Auth(controller.DeleteFollow)).Methods("DELETE")
 
+	router.HandleFunc("/profiles/{userid:[0-9]+}/privacy",
+		middleware.Auth(controller.UpdatePrivacy)).Methods("PUT")
+
+	router.HandleFunc("/profiles/{userid:[0-9]+}/requests",
+		middleware.Auth(controller.GetFollowRequests)).Methods("GET")
+
+	router.HandleFunc("/profiles/{userid:[0-9]+}/requests/{requesterid:[0-9]+}",
+		middleware.Auth(controller.ApproveFollowRequest)).Methods("PUT")
+
+	router.HandleFunc("/profiles/{userid:[0-9]+}/requests/{requesterid:[0-9]+}",
+		middleware.Auth(controller.RejectFollowRequest)).Methods("DELETE")
+
+	router.HandleFunc("/profiles/{userid:[0-9]+}/blocks",
+		middleware.Auth(controller.GetBlocked)).Methods("GET")
+
+	router.HandleFunc("/profiles/{userid:[0-9]+}/blocks/{blockedid:[0-9]+}",
+		middleware.Auth(controller.Block)).Methods("POST")
+
+	router.HandleFunc("/profiles/{userid:[0-9]+}/blocks/{blockedid:[0-9]+}",
+		middleware.Auth(controller.Unblock)).Methods("DELETE")
+
+	router.HandleFunc("/profiles/{userid:[0-9]+}/mutes",
+		middleware.Auth(controller.GetMuted)).Methods("GET")
+
+	router.HandleFunc("/profiles/{userid:[0-9]+}/mutes/{mutedid:[0-9]+}",
+		middleware.Auth(controller.Mute)).Methods("POST")
+
+	router.HandleFunc("/profiles/{userid:[0-9]+}/mutes/{mutedid:[0-9]+}",
+		middleware.Auth(controller.Unmute)).Methods("DELETE")
+
 	router.HandleFunc("/profiles/{userid:[0-9]+}",
 		middleware.Auth(controller.Delete)).Methods("DELETE")
 }
 
+func initializeCategoryRoutes(router *mux.Router, db *sql.DB) {
+	userRepository := repository.NewSQLiteUserRepository(db)
+	repository := repository.NewSQLiteCategoryRepository(db)
+	service := service.NewCategoryService(repository, userRepository)
+	controller := controller.NewCategoryController(service)
+
+	router.HandleFunc("/users/{userid:[0-9]+}/categories",
+		middleware.Auth(controller.Create)).Methods("POST")
+
+	router.HandleFunc("/categories",
+		controller.GetTree).Methods("GET")
+
+	router.HandleFunc("/categories/{categoryid:[0-9]+}",
+		controller.GetByID).Methods("GET")
+
+	router.HandleFunc("/users/{userid:[0-9]+}/categories/{categoryid:[0-9]+}/name",
+		middleware.Auth(controller.UpdateName)).Methods("PUT")
+
+	router.HandleFunc("/users/{userid:[0-9]+}/categories/{categoryid:[0-9]+}/position",
+		middleware.Auth(controller.UpdatePosition)).Methods("PUT")
+
+	router.HandleFunc("/users/{userid:[0-9]+}/categories/{categoryid:[0-9]+}/archive",
+		middleware.Auth(controller.Archive)).Methods("POST")
+
+	router.HandleFunc("/users/{userid:[0-9]+}/categories/{categoryid:[0-9]+}/archive",
+		middleware.Auth(controller.Unarchive)).Methods("DELETE")
+}
+
 func initializePostRoutes(router *mux.Router, db *sql.DB) {
+	userRepository := repository.NewSQLiteUserRepository(db)
+	categoryRepository := repository.NewSQLiteCategoryRepository(db)
+	profileRepository := repository.NewSQLiteProfileRepository(db)
+	mediaRepository := repository.NewSQLiteMediaRepository(db)
+	notificationRepository := repository.NewSQLiteNotificationRepository(db)
 	repository := repository.NewSQLitePostRepository(db)
-	service := service.NewPostService(repository)
+	service := service.NewPostService(repository, userRepository, categoryRepository, profileRepository, mediaRepository, notificationRepository, events.DefaultBus())
 	controller := controller.NewPostController(service)
 
 	router.HandleFunc("/users/{userid:[0-9]+}/posts",
 		middleware.Auth(controller.Create)).Methods("POST")
 
 	router.HandleFunc("/posts/today",
-		controller.GetPopularToday).Methods("GET")
+		middleware.OptionallyAuthenticated(controller.GetPopularToday)).Methods("GET")
 
 	router.HandleFunc("/posts/week",
-		controller.GetPopularLastWeek).Methods("GET")
+		middleware.OptionallyAuthenticated(controller.GetPopularLastWeek)).Methods("GET")
 
 	router.HandleFunc("/posts/month",
-		controller.GetPopularLastMonth).Methods("GET")
+		middleware.OptionallyAuthenticated(controller.GetPopularLastMonth)).Methods("GET")
 
 	router.HandleFunc("/posts/alltime",
-		controller.GetPopularAllTime).Methods("GET")
+		middleware.OptionallyAuthenticated(controller.GetPopularAllTime)).Methods("GET")
+
+	router.HandleFunc("/categories/{categoryid:[0-9]+}/posts/{period:today|week|month|alltime}",
+		middleware.OptionallyAuthenticated(controller.GetPopularInCategory)).Methods("GET")
+
+	router.HandleFunc("/tags/{name:[a-z0-9-]+}/posts",
+		middleware.OptionallyAuthenticated(controller.GetByTag)).Methods("GET")
+
+	router.HandleFunc("/feed",
+		middleware.Authenticated(controller.GetFeed)).Methods("GET")
 
 	router.HandleFunc("/posts/{postid:[0-9]+}",
-		controller.GetByID).Methods("GET")
+		middleware.OptionallyAuthenticated(controller.GetByID)).Methods("GET")
+
+	router.HandleFunc("/posts/{postid:[0-9]+}-{slug:[a-z0-9-]+}",
+		middleware.OptionallyAuthenticated(controller.GetByIDAndSlug)).Methods("GET")
+
+	router.HandleFunc("/posts/by-slug/{slug:[a-z0-9-]+}",
+		middleware.OptionallyAuthenticated(controller.GetBySlug)).Methods("GET")
 
 	router.HandleFunc("/users/{userid:[0-9]+}/posts",
-		controller.GetByUser).Methods("GET")
+		middleware.OptionallyAuthenticated(controller.GetByUser)).Methods("GET")
+
+	router.HandleFunc("/posts/{postid:[0-9]+}/revisions",
+		middleware.OptionallyAuthenticated(controller.GetRevisions)).Methods("GET")
+
+	router.HandleFunc("/posts/{postid:[0-9]+}/revisions/{revision:[0-9]+}",
+		middleware.OptionallyAuthenticated(controller.GetRevision)).Methods("GET")
+
+	router.HandleFunc("/posts/{postid:[0-9]+}/revisions/{revision:[0-9]+}/diff/{otherrevision:[0-9]+}",
+		middleware.OptionallyAuthenticated(controller.DiffRevisions)).Methods("GET")
 
 	router.HandleFunc("/users/{userid:[0-9]+}/posts/{postid:[0-9]+}/title",
 		middleware.Auth(controller.UpdateTitle)).Methods("PUT")
@@ -141,42 +245,218 @@ func initializePostRoutes(router *mux.Router, db *sql.DB) {
 	router.HandleFunc("/users/{userid:[0-9]+}/posts/{postid:[0-9]+}/content",
 		middleware.Auth(controller.UpdateContent)).Methods("PUT")
 
-	router.HandleFunc("/users/{userid:[0-9]+}/posts/{postid:[0-9]+}/likes",
-		middleware.Auth(controller.AddLike)).Methods("POST")
+	router.HandleFunc("/users/{userid:[0-9]+}/posts/{postid:[0-9]+}/tags",
+		middleware.Auth(controller.UpdateTags)).Methods("PUT")
 
-	router.HandleFunc("/users/{userid:[0-9]+}/posts/{postid:[0-9]+}/likes",
-		middleware.Auth(controller.DeleteLike)).Methods("DELETE")
+	router.HandleFunc("/posts/{postid:[0-9]+}/vote",
+		middleware.Authenticated(controller.Vote)).Methods("PUT")
 
 	router.HandleFunc("/users/{userid:[0-9]+}/posts/{postid:[0-9]+}",
 		middleware.Auth(controller.Delete)).Methods("DELETE")
+
+	router.HandleFunc("/users/{userid:[0-9]+}/posts/{postid:[0-9]+}/restore",
+		middleware.Auth(controller.Restore)).Methods("POST")
+
+	router.HandleFunc("/users/{userid:[0-9]+}/posts/{postid:[0-9]+}/revisions/{revision:[0-9]+}/rollback",
+		middleware.Auth(controller.Rollback)).Methods("POST")
+
+	router.HandleFunc("/users/{userid:[0-9]+}/posts/{postid:[0-9]+}/lock",
+		middleware.Auth(controller.Lock)).Methods("POST")
+
+	router.HandleFunc("/users/{userid:[0-9]+}/posts/{postid:[0-9]+}/lock",
+		middleware.Auth(controller.Unlock)).Methods("DELETE")
+
+	router.HandleFunc("/users/{userid:[0-9]+}/posts/{postid:[0-9]+}/pin",
+		middleware.Auth(controller.Pin)).Methods("POST")
+
+	router.HandleFunc("/users/{userid:[0-9]+}/posts/{postid:[0-9]+}/pin",
+		middleware.Auth(controller.Unpin)).Methods("DELETE")
+
+	router.HandleFunc("/users/{userid:[0-9]+}/posts/{postid:[0-9]+}/archive",
+		middleware.Auth(controller.Archive)).Methods("POST")
+
+	router.HandleFunc("/users/{userid:[0-9]+}/posts/{postid:[0-9]+}/archive",
+		middleware.Auth(controller.Unarchive)).Methods("DELETE")
 }
 
 func initializeCommentRoutes(router *mux.Router, db *sql.DB) {
+	userRepository := repository.NewSQLiteUserRepository(db)
+	postRepository := repository.NewSQLitePostRepository(db)
+	profileRepository := repository.NewSQLiteProfileRepository(db)
+	notificationRepository := repository.NewSQLiteNotificationRepository(db)
 	repository := repository.NewSQLiteCommentRepository(db)
-	service := service.NewCommentService(repository)
+	service := service.NewCommentService(repository, userRepository, postRepository, profileRepository, notificationRepository, events.DefaultBus())
 	controller := controller.NewCommentController(service)
 
 	router.HandleFunc("/users/{userid:[0-9]+}/comments",
 		middleware.Auth(controller.Create)).Methods("POST")
 
 	router.HandleFunc("/comments/{commentid:[0-9]+}",
-		controller.GetByID).Methods("GET")
+		middleware.OptionallyAuthenticated(controller.GetByID)).Methods("GET")
+
+	router.HandleFunc("/comments/{commentid:[0-9]+}/replies",
+		middleware.OptionallyAuthenticated(controller.GetReplies)).Methods("GET")
+
+	router.HandleFunc("/comments/{commentid:[0-9]+}/revisions",
+		middleware.OptionallyAuthenticated(controller.GetRevisions)).Methods("GET")
 
 	router.HandleFunc("/users/{userid:[0-9]+}/comments",
-		controller.GetByUser).Methods("GET")
+		middleware.OptionallyAuthenticated(controller.GetByUser)).Methods("GET")
 
 	router.HandleFunc("/posts/{postid:[0-9]+}/comments",
-		controller.GetByPost).Methods("GET")
+		middleware.OptionallyAuthenticated(controller.GetByPost)).Methods("GET")
 
 	router.HandleFunc("/users/{userid:[0-9]+}/comments/{commentid:[0-9]+}",
 		middleware.Auth(controller.UpdateContent)).Methods("PUT")
 
-	router.HandleFunc("/users/{userid:[0-9]+}/comments/{commentid:[0-9]+}/likes",
-		middleware.Auth(controller.AddLike)).Methods("POST")
-
-	router.HandleFunc("/users/{userid:[0-9]+}/comments/{commentid:[0-9]+}/likes",
-		middleware.Auth(controller.DeleteLike)).Methods("DELETE")
+	router.HandleFunc("/comments/{commentid:[0-9]+}/vote",
+		middleware.Authenticated(controller.Vote)).Methods("PUT")
 
 	router.HandleFunc("/users/{userid:[0-9]+}/comments/{commentid:[0-9]+}",
 		middleware.Auth(controller.Delete)).Methods("DELETE")
+
+	router.HandleFunc("/users/{userid:[0-9]+}/comments/{commentid:[0-9]+}/restore",
+		middleware.Auth(controller.Restore)).Methods("POST")
+}
+
+func initializeTagRoutes(router *mux.Router, db *sql.DB) {
+	repository := repository.NewSQLiteTagRepository(db)
+	service := service.NewTagService(repository)
+	controller := controller.NewTagController(service)
+
+	router.HandleFunc("/tags/autocomplete",
+		controller.Autocomplete).Methods("GET")
+
+	router.HandleFunc("/tags/trending",
+		controller.GetTrending).Methods("GET")
+}
+
+func initializeLikeRoutes(router *mux.Router, db *sql.DB) {
+	service := service.NewLikeService(repository.NewSQLitePostRepository(db), repository.NewSQLiteCommentRepository(db))
+	controller := controller.NewLikeController(service)
+
+	router.HandleFunc("/users/{userid:[0-9]+}/likes",
+		middleware.OptionallyAuthenticated(controller.GetByUser)).Methods("GET")
+}
+
+func initializeMentionRoutes(router *mux.Router, db *sql.DB) {
+	service := service.NewMentionService(repository.NewSQLiteProfileRepository(db), repository.NewSQLitePostRepository(db), repository.NewSQLiteCommentRepository(db))
+	controller := controller.NewMentionController(service)
+
+	router.HandleFunc("/profiles/{tagname:[a-zA-Z][a-zA-Z0-9]*}/mentions",
+		middleware.OptionallyAuthenticated(controller.GetByTagName)).Methods("GET")
+}
+
+func initializeMediaRoutes(router *mux.Router, db *sql.DB) {
+	repository := repository.NewSQLiteMediaRepository(db)
+	service := service.NewMediaService(repository, storage.DefaultStore())
+	controller := controller.NewMediaController(service)
+
+	router.HandleFunc("/media",
+		middleware.Authenticated(controller.Upload)).Methods("POST")
+
+	router.HandleFunc("/media/{mediaid:[0-9]+}",
+		controller.GetByID).Methods("GET")
+
+	router.HandleFunc("/media/{key:[0-9a-f]{64}\\.(?:jpg|png)}",
+		controller.Serve).Methods("GET")
+}
+
+func initializeNotificationRoutes(router *mux.Router, db *sql.DB) {
+	repository := repository.NewSQLiteNotificationRepository(db)
+	service := service.NewNotificationService(repository)
+	controller := controller.NewNotificationController(service)
+
+	router.HandleFunc("/notifications",
+		middleware.Authenticated(controller.GetByUser)).Methods("GET")
+
+	router.HandleFunc("/notifications/read",
+		middleware.Authenticated(controller.MarkAllRead)).Methods("PUT")
+
+	router.HandleFunc("/notifications/{notificationid:[0-9]+}/read",
+		middleware.Authenticated(controller.MarkRead)).Methods("PUT")
+
+	router.HandleFunc("/notifications/preferences",
+		middleware.Authenticated(controller.GetPreferences)).Methods("GET")
+
+	router.HandleFunc("/notifications/preferences",
+		middleware.Authenticated(controller.UpdatePreferences)).Methods("PUT")
+}
+
+func initializeConversationRoutes(router *mux.Router, db *sql.DB) {
+	userRepository := repository.NewSQLiteUserRepository(db)
+	profileRepository := repository.NewSQLiteProfileRepository(db)
+	repository := repository.NewSQLiteConversationRepository(db)
+	service := service.NewConversationService(repository, userRepository, profileRepository, events.DefaultBus())
+	controller := controller.NewConversationController(service)
+
+	router.HandleFunc("/conversations",
+		middleware.Authenticated(controller.Start)).Methods("POST")
+
+	router.HandleFunc("/conversations",
+		middleware.Authenticated(controller.GetByUser)).Methods("GET")
+
+	router.HandleFunc("/conversations/{conversationid:[0-9]+}",
+		middleware.Authenticated(controller.GetByID)).Methods("GET")
+
+	router.HandleFunc("/conversations/{conversationid:[0-9]+}/messages",
+		middleware.Authenticated(controller.Send)).Methods("POST")
+
+	router.HandleFunc("/conversations/{conversationid:[0-9]+}/messages",
+		middleware.Authenticated(controller.GetMessages)).Methods("GET")
+
+	router.HandleFunc("/conversations/{conversationid:[0-9]+}/read",
+		middleware.Authenticated(controller.MarkRead)).Methods("PUT")
+}
+
+func initializeReportRoutes(router *mux.Router, db *sql.DB) {
+	userRepository := repository.NewSQLiteUserRepository(db)
+	postRepository := repository.NewSQLitePostRepository(db)
+	commentRepository := repository.NewSQLiteCommentRepository(db)
+	profileRepository := repository.NewSQLiteProfileRepository(db)
+	notificationRepository := repository.NewSQLiteNotificationRepository(db)
+	repository := repository.NewSQLiteReportRepository(db)
+	service := service.NewReportService(repository, userRepository, postRepository, commentRepository, profileRepository, notificationRepository, events.DefaultBus())
+	controller := controller.NewReportController(service)
+
+	router.HandleFunc("/posts/{postid:[0-9]+}/reports",
+		middleware.Authenticated(controller.ReportPost)).Methods("POST")
+
+	router.HandleFunc("/comments/{commentid:[0-9]+}/reports",
+		middleware.Authenticated(controller.ReportComment)).Methods("POST")
+
+	router.HandleFunc("/moderation/reports",
+		middleware.Authenticated(controller.GetQueue)).Methods("GET")
+
+	router.HandleFunc("/moderation/reports/{reportid:[0-9]+}",
+		middleware.Authenticated(controller.GetByID)).Methods("GET")
+
+	router.HandleFunc("/moderation/reports/{reportid:[0-9]+}/actions",
+		middleware.Authenticated(controller.Act)).Methods("POST")
+
+	router.HandleFunc("/moderation/log",
+		middleware.Authenticated(controller.GetLog)).Methods("GET")
+}
+
+func initializeStreamRoutes(router *mux.Router, db *sql.DB) {
+	postService := service.NewPostService(repository.NewSQLitePostRepository(db), repository.NewSQLiteUserRepository(db),
+		repository.NewSQLiteCategoryRepository(db), repository.NewSQLiteProfileRepository(db), repository.NewSQLiteMediaRepository(db),
+		repository.NewSQLiteNotificationRepository(db), events.DefaultBus())
+	gatewayController := controller.NewGatewayController(postService, events.DefaultBus())
+	controller := controller.NewStreamController(postService, events.DefaultBus())
+
+	router.HandleFunc("/stream",
+		middleware.OptionallyAuthenticated(controller.Subscribe)).Methods("GET")
+
+	router.HandleFunc("/ws",
+		middleware.Authenticated(gatewayController.Connect)).Methods("GET")
+}
+
+func initializeSearchRoutes(router *mux.Router, db *sql.DB) {
+	repository := repository.NewSQLiteSearchRepository(db)
+	service := service.NewSearchService(repository)
+	controller := controller.NewSearchController(service)
+
+	router.HandleFunc("/search",
+		middleware.OptionallyAuthenticated(controller.Search)).Methods("GET")
 }
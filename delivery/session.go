@@ -0,0 +1,20 @@
+package delivery
+
+import (
+	"context"
+	"net/http"
+)
+
+type authenticatedUserKey struct{}
+
+// Returns a copy of the request carrying the ID of the user who made it
+func WithAuthenticatedUser(r *http.Request, userID uint) *http.Request {
+	ctx := context.WithValue(r.Context(), authenticatedUserKey{}, userID)
+	return r.WithContext(ctx)
+}
+
+// Returns the ID of the user who made the request, false when it wasn't identified
+func AuthenticatedUserID(r *http.Request) (uint, bool) {
+	userID, ok := r.Context().Value(authenticatedUserKey{}).(uint)
+	return userID, ok
+}
@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/AlejandroJorge/forum-rest-api/util"
+)
+
+// A subforum posts belong to, root categories have no parent
+type Category struct {
+	CategoryID   uint      `json:"CategoryID"`
+	ParentID     uint      `json:"ParentID"`
+	Name         string    `json:"Name"`
+	Position     uint      `json:"Position"`
+	Archived     bool      `json:"Archived"`
+	CreationDate time.Time `json:"CreationDate"`
+}
+
+// A category along with its subcategories
+type CategoryTree struct {
+	Category
+	Subcategories []CategoryTree `json:"Subcategories"`
+}
+
+func (c Category) Validate() bool {
+	conditions := []bool{
+		c.CategoryID != 0,
+		c.Name != "",
+		!c.CreationDate.IsZero(),
+	}
+
+	return util.MergeAND(conditions)
+}
+
+type CategoryRepository interface {
+	// Returns the id of the created category placed after its siblings, can return ErrRepeatedEntity, ErrNoMatchingDependency
+	Create(parentID uint, name string) (uint, error)
+
+	// Returns a valid category and can return ErrEmptySelection
+	GetByID(id uint) (Category, error)
+
+	// Returns an slice of valid categories sorted by position, can return ErrEmptySelection
+	GetAll() ([]Category, error)
+
+	// Can return ErrNoRowsAffected, ErrRepeatedEntity
+	UpdateName(id uint, newName string) error
+
+	// Moves the category among its siblings shifting the rest, can return ErrNoRowsAffected
+	UpdatePosition(id uint, newPosition uint) error
+
+	// Can return ErrNoRowsAffected
+	UpdateArchived(id uint, archived bool) error
+}
+
+type CategoryService interface {
+	// Returns the ID of the created category, can return ErrIncorrectParameters, ErrNotAuthorized, ErrAlreadyExisting, ErrDependencyNotSatisfied
+	// Only moderators can create categories
+	Create(userId, parentId uint, name string) (uint, error)
+
+	// Returns a valid category, can return ErrIncorrectParameters, ErrNotExistingEntity
+	GetByID(id uint) (Category, error)
+
+	// Returns the trees of categories that aren't archived, can return ErrNotExistingEntity
+	GetTree() ([]CategoryTree, error)
+
+	// Only moderators can rename, can return ErrIncorrectParameters, ErrNotAuthorized, ErrNotExistingEntity, ErrAlreadyExisting
+	UpdateName(userId, id uint, name string) error
+
+	// Only moderators can reorder, can return ErrIncorrectParameters, ErrNotAuthorized, ErrNotExistingEntity
+	UpdatePosition(userId, id uint, position uint) error
+
+	// Only moderators can archive, can return ErrIncorrectParameters, ErrNotAuthorized, ErrNotExistingEntity
+	Archive(userId, id uint) error
+
+	// Only moderators can unarchive, can return ErrIncorrectParameters, ErrNotAuthorized, ErrNotExistingEntity
+	Unarchive(userId, id uint) error
+}
@@ -1,13 +1,50 @@
 package domain
 
-import "github.com/AlejandroJorge/forum-rest-api/util"
+import (
+	"time"
+
+	"github.com/AlejandroJorge/forum-rest-api/util"
+)
 
 type Comment struct {
-	ID      uint   `json:"ID"`
-	PostID  uint   `json:"PostID"`
-	UserID  uint   `json:"UserID"`
-	Content string `json:"Content"`
-	Likes   uint   `json:"Likes"`
+	ID          uint             `json:"ID"`
+	PostID      uint             `json:"PostID"`
+	UserID      uint             `json:"UserID"`
+	ParentID    uint             `json:"ParentID"`
+	Depth       uint             `json:"Depth"`
+	Content     string           `json:"Content"`
+	ContentHTML string           `json:"ContentHTML"`
+	Score       int              `json:"Score"`
+	Upvotes     uint             `json:"Upvotes"`
+	Downvotes   uint             `json:"Downvotes"`
+	ReplyCount  uint             `json:"ReplyCount"`
+	CreatedAt   time.Time        `json:"CreatedAt"`
+	EditedAt    *time.Time       `json:"EditedAt,omitempty"`
+	Entities    []ContentEntity  `json:"Entities"`
+	Moderation  ModerationAction `json:"Moderation,omitempty"`
+	*Viewer
+}
+
+// A previous content of a comment, replaced at RevisionDate
+type CommentRevision struct {
+	ID           uint      `json:"ID"`
+	CommentID    uint      `json:"CommentID"`
+	Content      string    `json:"Content"`
+	RevisionDate time.Time `json:"RevisionDate"`
+}
+
+type CommentOrder string
+
+const (
+	CommentOrderOldest CommentOrder = "oldest"
+	CommentOrderNewest CommentOrder = "newest"
+	CommentOrderTop    CommentOrder = "top"
+)
+
+// A comment along with its nested replies
+type CommentThread struct {
+	Comment
+	Replies []CommentThread `json:"Replies"`
 }
 
 func (c Comment) Validate() bool {
@@ -16,59 +53,124 @@ func (c Comment) Validate() bool {
 		c.PostID != 0,
 		c.UserID != 0,
 		c.Content != "",
+		!c.CreatedAt.IsZero(),
 	}
 
 	return util.MergeAND(conditions)
 }
 
 type CommentRepository interface {
-	// Returns the id of the created comment, can return ErrNoMatchingDependency
-	Create(postID, userID uint, content string) (uint, error)
+	// Returns the id of the created comment, parentID is optional, counts it in its post and parent, can return ErrNoMatchingDependency
+	Create(postID, userID, parentID uint, content string) (uint, error)
 
-	// Can return ErrNoRowsAffected
+	// Marks the comment as deleted and stops counting it in its post and parent, can return ErrNoRowsAffected
 	Delete(id uint) error
 
-	// Can return ErrNoRowsAffected
+	// Keeps the previous content as a revision, can return ErrNoRowsAffected
 	UpdateContent(id uint, newContent string) error
 
+	// Returns an slice of revisions from oldest to newest, can return ErrEmptySelection
+	GetRevisions(commentID uint) ([]CommentRevision, error)
+
 	// Returns a valid comment and can return ErrEmptySelection
 	GetByID(id uint) (Comment, error)
 
-	// Returns an slice of valid comments, can return ErrEmptySelection
+	// Returns an slice of valid comments ordered by creation, can return ErrEmptySelection
 	GetByPost(postID uint) ([]Comment, error)
 
+	// Returns an slice of valid comments replying directly to the comment, can return ErrEmptySelection
+	GetReplies(commentID uint) ([]Comment, error)
+
 	// Returns an slice of valid comments, can return ErrEmptySelection
-	GetByUser(userID uint) ([]Comment, error)
+	// Only returns comments on posts the viewer can see, viewerID is 0 for anonymous viewers
+	GetByUser(viewerID, userID uint) ([]Comment, error)
+
+	// A value of 0 removes the vote, keeps the vote counters in sync, can return ErrNoMatchingDependency
+	Vote(userId uint, commentId uint, value int) error
+
+	// Stores the rendered HTML and the entities of the comment, replaces its mentions and hashtags, can return ErrNoRowsAffected
+	SetRendered(id uint, contentHTML string, entities []ContentEntity) error
+
+	// Returns an slice of valid comments mentioning the user from newest to oldest, can return ErrEmptySelection
+	// Only returns comments on posts the viewer can see, viewerID is 0 for anonymous viewers
+	GetByMention(viewerID, userID uint) ([]Comment, error)
+
+	// Returns the viewer fields of the comments for the user by comment ID
+	GetViewerStates(viewerID uint, commentIDs []uint) (map[uint]Viewer, error)
+
+	// Returns an slice of valid comments the user upvoted from newest to oldest, can return ErrEmptySelection
+	// Only returns comments on posts the viewer can see, viewerID is 0 for anonymous viewers
+	GetLikedBy(viewerID, userID uint) ([]Comment, error)
 
-	// Can return ErrNoMatchingDependency
-	AddLike(userId uint, commentId uint) error
+	// Counts the comment again in its post and parent and clears the moderation action that removed it, can return ErrNoRowsAffected
+	Restore(id uint) error
 
-	// Can return ErrNoRowsAffected
-	DeleteLike(userId uint, commentId uint) error
+	// Returns a valid soft deleted comment along with the moderation action that removed it if any, can return ErrEmptySelection
+	GetDeletedByID(id uint) (Comment, error)
+
+	// Returns the amount of purged comments, votes on them are purged too, comments hidden by moderators are kept
+	PurgeDeletedBefore(moment time.Time) (uint, error)
+
+	// Marks the comment as deleted by the action even if its author already deleted it, stops counting it if it was valid, can return ErrNoRowsAffected
+	Moderate(id uint, action ModerationAction) error
 }
 
 type CommentService interface {
-	// Returns the ID of the generated comment, can return ErrIncorrectParameters, ErrDependencySatisfied
-	Create(userID, postID uint, content string) (uint, error)
+	// Returns the ID of the generated comment, parentID is optional, can return ErrIncorrectParameters, ErrDependencyNotSatisfied, ErrMaxDepthExceeded, ErrNotAuthorized, ErrSuspended, ErrLocked, ErrArchived
+	// Fails when the post's owner blocked the user or is private and not followed by them
+	// Notifies the author of the parent or the post and the mentioned profiles, publishes the comment to the post's topic
+	Create(userID, postID, parentID uint, content string) (uint, error)
 
-	// Can return ErrIncorrectParameters, ErrNotExistingEntity
-	Delete(id uint) error
+	// Only the author or a moderator can delete, can return ErrIncorrectParameters, ErrNotExistingEntity, ErrNotAuthorized
+	Delete(userId, id uint) error
+
+	// Only the author or a moderator can edit, can return ErrIncorrectParameters, ErrNotExistingEntity, ErrNotAuthorized, ErrArchived, ErrSuspended
+	// Renders the content again and notifies newly mentioned profiles
+	Update(userId, id uint, updatedContent string) error
 
-	// Can return ErrIncorrectParameters, ErrNotExistingEntity
-	Update(id uint, updatedContent string) error
+	// Returns a slice of revisions from oldest to newest, can return ErrIncorrectParameters, ErrNotExistingEntity
+	GetRevisions(commentID uint) ([]CommentRevision, error)
 
 	// Returns a valid comment, can return ErrIncorrectParameters, ErrNotExistingEntity
 	GetByID(id uint) (Comment, error)
 
-	// Returns a slice of valid comments, can return ErrIncorrectParameters, ErrNotExistingEntity
-	GetByPost(postID uint) ([]Comment, error)
+	// Returns a slice of valid comments in thread order, replies to the same comment are sorted by order, can return ErrIncorrectParameters, ErrNotExistingEntity
+	GetByPost(postID uint, order CommentOrder) ([]Comment, error)
+
+	// Returns a slice of valid comment threads, replies to the same comment are sorted by order, can return ErrIncorrectParameters, ErrNotExistingEntity
+	GetThreadsByPost(postID uint, order CommentOrder) ([]CommentThread, error)
+
+	// Returns a slice of valid comments replying directly to the comment, can return ErrIncorrectParameters, ErrNotExistingEntity
+	GetReplies(commentID uint) ([]Comment, error)
 
 	// Returns a slice of valid comments, can return ErrIncorrectParameters, ErrNotExistingEntity
-	GetByUser(userID uint) ([]Comment, error)
+	// Only returns comments on posts the viewer can see, viewerId is 0 for anonymous viewers
+	GetByUser(viewerId, userId uint) ([]Comment, error)
+
+	// Value is 1 or -1 and 0 removes the vote, can return ErrIncorrectParameters, ErrNotExistingEntity, ErrDependencyNotSatisfied, ErrNotAuthorized, ErrArchived, ErrSuspended
+	// Upvotes notify the author, the new counts are published to the post's topic
+	Vote(userId uint, commentId uint, value int) error
+
+	// Fills the viewer fields of the comments for the user, can return ErrIncorrectParameters
+	AddViewer(viewerId uint, comments []Comment) error
+
+	// Fills the viewer fields of the comments and every nested reply for the user, can return ErrIncorrectParameters
+	AddViewerToThreads(viewerId uint, threads []CommentThread) error
+
+	// Private profiles' posts and their comments are only visible to their approved followers, viewerId is 0 for anonymous viewers
+	// Can return ErrIncorrectParameters, ErrNotExistingEntity, ErrNotAuthorized
+	CheckVisible(viewerId, postId uint) error
+
+	// Returns the comments without the ones by profiles the user muted, can return ErrIncorrectParameters
+	HideMuted(viewerId uint, comments []Comment) ([]Comment, error)
+
+	// Returns the threads without the comments by profiles the user muted along with their replies, can return ErrIncorrectParameters
+	HideMutedInThreads(viewerId uint, threads []CommentThread) ([]CommentThread, error)
 
-	// Can return ErrIncorrectParameters, ErrDependencyNotSatisfied
-	AddLike(userId uint, commentId uint) error
+	// Only the author or a moderator can restore, can return ErrIncorrectParameters, ErrNotExistingEntity, ErrNotAuthorized
+	// Only moderators can restore comments removed by moderation
+	Restore(userId uint, commentId uint) error
 
-	// Can return ErrIncorrectParameters, ErrNotExistingEntity
-	DeleteLike(userId uint, commentId uint) error
+	// Returns the amount of purged comments, can return ErrIncorrectParameters
+	PurgeDeleted(retention time.Duration) (uint, error)
 }
@@ -0,0 +1,29 @@
+package domain
+
+type ContentEntityType string
+
+const (
+	ContentEntityMention ContentEntityType = "mention"
+	ContentEntityHashtag ContentEntityType = "hashtag"
+)
+
+// A mention or hashtag found in content, Offset and Length count characters and include the @ or #
+type ContentEntity struct {
+	Type   ContentEntityType `json:"Type"`
+	Text   string            `json:"Text"`
+	Offset uint              `json:"Offset"`
+	Length uint              `json:"Length"`
+	UserID uint              `json:"UserID,omitempty"`
+}
+
+// Posts and comments mentioning a profile
+type ProfileMentions struct {
+	Posts    []Post    `json:"Posts"`
+	Comments []Comment `json:"Comments"`
+}
+
+type MentionService interface {
+	// Returns what mentions the profile from newest to oldest, can return ErrIncorrectParameters, ErrNotExistingEntity
+	// Only returns what the viewer can see, viewerId is 0 for anonymous viewers
+	GetByTagName(viewerId uint, tagName string) (ProfileMentions, error)
+}
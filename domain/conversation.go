@@ -0,0 +1,72 @@
+package domain
+
+import "time"
+
+// A private conversation, between two profiles or a small group, Unread counts the messages the viewer hasn't read
+type Conversation struct {
+	ID            uint                 `json:"ID"`
+	CreatorID     uint                 `json:"CreatorID"`
+	IsGroup       bool                 `json:"IsGroup"`
+	Members       []ConversationMember `json:"Members"`
+	Unread        uint                 `json:"Unread"`
+	CreatedAt     time.Time            `json:"CreatedAt"`
+	LastMessageAt time.Time            `json:"LastMessageAt"`
+}
+
+// A member of a conversation, every message up to LastReadMessageID has been read by them
+type ConversationMember struct {
+	UserID            uint      `json:"UserID"`
+	LastReadMessageID uint      `json:"LastReadMessageID"`
+	LastReadAt        time.Time `json:"LastReadAt"`
+}
+
+type Message struct {
+	ID             uint      `json:"ID"`
+	ConversationID uint      `json:"ConversationID"`
+	SenderID       uint      `json:"SenderID"`
+	Content        string    `json:"Content"`
+	CreatedAt      time.Time `json:"CreatedAt"`
+}
+
+type ConversationRepository interface {
+	// Returns the id of the conversation, a conversation between two profiles is reused instead of created again, can return ErrNoMatchingDependency
+	Create(creatorID uint, memberIDs []uint) (uint, error)
+
+	// Returns a valid conversation with its members, Unread is left empty, can return ErrEmptySelection
+	GetByID(id uint) (Conversation, error)
+
+	// Returns an slice of the user's conversations by latest message with their unread counts, can return ErrEmptySelection
+	GetByUser(userID uint, limit, offset uint) ([]Conversation, error)
+
+	// Returns the id of the created message, the sender reads it, can return ErrNoMatchingDependency
+	CreateMessage(conversationID, senderID uint, content string) (uint, error)
+
+	// Returns an slice of messages from newest to oldest, can return ErrEmptySelection
+	GetMessages(conversationID uint, limit, offset uint) ([]Message, error)
+
+	// Marks every message up to messageID as read, 0 reads up to the latest one, can return ErrNoRowsAffected
+	MarkRead(conversationID, userID, messageID uint) error
+}
+
+type ConversationService interface {
+	// Returns the ID of the conversation, can return ErrIncorrectParameters, ErrDependencyNotSatisfied, ErrNotAuthorized, ErrSuspended
+	// Starting one with a single profile again returns the same one
+	// Profiles that blocked the creator or were blocked by it can't be added
+	Start(creatorID uint, memberIDs []uint) (uint, error)
+
+	// Returns a valid conversation the user is a member of, can return ErrIncorrectParameters, ErrNotExistingEntity
+	GetByID(userID, conversationID uint) (Conversation, error)
+
+	// Returns a page of the user's conversations by latest message, can return ErrIncorrectParameters
+	GetByUser(userID uint, page uint) ([]Conversation, error)
+
+	// Returns the ID of the sent message, can return ErrIncorrectParameters, ErrNotExistingEntity, ErrNotAuthorized, ErrSuspended
+	// Publishes the message to the members, one-to-one messages fail when either profile blocked the other
+	Send(senderID, conversationID uint, content string) (uint, error)
+
+	// Returns a page of messages from newest to oldest, can return ErrIncorrectParameters, ErrNotExistingEntity
+	GetMessages(userID, conversationID uint, page uint) ([]Message, error)
+
+	// Marks every message up to messageID as read, 0 reads up to the latest one, can return ErrIncorrectParameters, ErrNotExistingEntity
+	MarkRead(userID, conversationID, messageID uint) error
+}
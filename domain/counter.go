@@ -0,0 +1,18 @@
+package domain
+
+// How many rows had counters out of sync with what they count
+type CounterDrift struct {
+	Posts    uint `json:"Posts"`
+	Comments uint `json:"Comments"`
+	Profiles uint `json:"Profiles"`
+}
+
+type CounterRepository interface {
+	// Recomputes the vote, comment, reply and follow counters and the last activity of posts and repairs the ones that drifted, returns how many rows were repaired
+	Reconcile() (CounterDrift, error)
+}
+
+type CounterService interface {
+	// Repairs every counter that drifted, returns how many rows were repaired
+	Reconcile() (CounterDrift, error)
+}
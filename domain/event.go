@@ -0,0 +1,87 @@
+package domain
+
+import "fmt"
+
+type EventType string
+
+const (
+	EventPostCreated         EventType = "post.created"
+	EventPostVoted           EventType = "post.voted"
+	EventCommentCreated      EventType = "comment.created"
+	EventCommentVoted        EventType = "comment.voted"
+	EventProfileFollowed     EventType = "profile.followed"
+	EventNotificationCreated EventType = "notification.created"
+	EventPostTyping          EventType = "post.typing"
+	EventMessageCreated      EventType = "message.created"
+)
+
+// Something that happened on a topic, IDs grow with every published event and are 0 for ephemeral ones
+type Event struct {
+	ID    uint64      `json:"ID"`
+	Topic string      `json:"Topic"`
+	Type  EventType   `json:"Type"`
+	Data  interface{} `json:"Data"`
+}
+
+// What changed on a post or comment after a vote
+type VoteCounts struct {
+	PostID    uint `json:"PostID"`
+	CommentID uint `json:"CommentID"`
+	Score     int  `json:"Score"`
+	Upvotes   uint `json:"Upvotes"`
+	Downvotes uint `json:"Downvotes"`
+}
+
+// Who followed whom
+type Follow struct {
+	FollowerID uint `json:"FollowerID"`
+	FollowedID uint `json:"FollowedID"`
+}
+
+// Someone is writing a comment on the post
+type Typing struct {
+	PostID uint `json:"PostID"`
+	UserID uint `json:"UserID"`
+}
+
+// Comments and votes on the post
+func PostTopic(postID uint) string {
+	return fmt.Sprintf("posts/%d", postID)
+}
+
+// Who is writing a comment on the post
+func TypingTopic(postID uint) string {
+	return fmt.Sprintf("posts/%d/typing", postID)
+}
+
+// Posts and followers of the profile
+func ProfileTopic(userID uint) string {
+	return fmt.Sprintf("profiles/%d", userID)
+}
+
+// Notifications and direct messages of the user, only the user can subscribe to them
+func NotificationsTopic(userID uint) string {
+	return fmt.Sprintf("notifications/%d", userID)
+}
+
+type EventPublisher interface {
+	// Delivers the event to the current subscribers of the topic without waiting for them
+	Publish(topic string, eventType EventType, data interface{})
+
+	// Delivers the event like Publish but doesn't keep it for subscribers resuming later
+	PublishEphemeral(topic string, eventType EventType, data interface{})
+}
+
+type EventSubscription interface {
+	// Delivers the events published after subscribing, closed after Unsubscribe or when the subscriber falls too far behind
+	Events() <-chan Event
+
+	Unsubscribe()
+}
+
+type EventBus interface {
+	EventPublisher
+
+	// Returns the recent events of the topics published after lastEventID along with the subscription, a lastEventID of 0 replays nothing
+	Subscribe(topics []string, lastEventID uint64) ([]Event, EventSubscription)
+}
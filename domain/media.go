@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"io"
+	"time"
+)
+
+// Stored media is served from content-addressed keys under this path
+const MediaURLPrefix = "/api/v1/media/"
+
+func MediaURL(key string) string {
+	return MediaURLPrefix + key
+}
+
+// An uploaded image, re-encoded without metadata and stored along with a thumbnail
+type Media struct {
+	ID           uint      `json:"ID"`
+	OwnerID      uint      `json:"OwnerID"`
+	ContentType  string    `json:"ContentType"`
+	Size         uint      `json:"Size"`
+	Width        uint      `json:"Width"`
+	Height       uint      `json:"Height"`
+	URL          string    `json:"URL"`
+	ThumbnailURL string    `json:"ThumbnailURL"`
+	CreatedAt    time.Time `json:"CreatedAt"`
+}
+
+// Media attached to a post, in the order it was attached
+type Attachment struct {
+	MediaID      uint   `json:"MediaID"`
+	URL          string `json:"URL"`
+	ThumbnailURL string `json:"ThumbnailURL"`
+}
+
+type BlobStore interface {
+	// Stores data under key, storing the same key again replaces it
+	Put(key, contentType string, data []byte) error
+
+	// Returns the data stored under key, the caller closes it, can return ErrBlobNotFound
+	Get(key string) (io.ReadCloser, error)
+}
+
+type MediaRepository interface {
+	// Returns the id of the created media, can return ErrRepeatedEntity, ErrNoMatchingDependency
+	Create(ownerID uint, key, thumbnailKey, contentType string, size, width, height uint) (uint, error)
+
+	// Returns a valid media and can return ErrEmptySelection
+	GetByID(id uint) (Media, error)
+
+	// Returns the media of the owner stored under the key or with it as thumbnail, can return ErrEmptySelection
+	GetByKey(ownerID uint, key string) (Media, error)
+}
+
+type MediaService interface {
+	// Returns the stored media, can return ErrIncorrectParameters, ErrTooLarge, ErrUnsupportedMedia
+	// Re-encodes the image and stores it with a thumbnail under content-addressed keys
+	// Uploading the same image again returns the same media
+	Upload(ownerID uint, data io.Reader) (Media, error)
+
+	// Returns a valid media, can return ErrIncorrectParameters, ErrNotExistingEntity
+	GetByID(id uint) (Media, error)
+
+	// Returns the stored data and its content type, the caller closes it, can return ErrIncorrectParameters, ErrNotExistingEntity
+	Open(key string) (io.ReadCloser, string, error)
+}
@@ -0,0 +1,99 @@
+package domain
+
+import "time"
+
+type NotificationType string
+
+const (
+	NotificationReply          NotificationType = "reply"
+	NotificationLike           NotificationType = "like"
+	NotificationFollow         NotificationType = "follow"
+	NotificationFollowRequest  NotificationType = "follow_request"
+	NotificationFollowAccepted NotificationType = "follow_accepted"
+	NotificationMention        NotificationType = "mention"
+	NotificationWarning        NotificationType = "warning"
+	NotificationSuspension     NotificationType = "suspension"
+)
+
+// Something ActorID did that concerns UserID, PostID and CommentID are 0 when it isn't about them
+type Notification struct {
+	ID        uint             `json:"ID"`
+	UserID    uint             `json:"UserID"`
+	ActorID   uint             `json:"ActorID"`
+	Type      NotificationType `json:"Type"`
+	PostID    uint             `json:"PostID"`
+	CommentID uint             `json:"CommentID"`
+	Read      bool             `json:"Read"`
+	CreatedAt time.Time        `json:"CreatedAt"`
+}
+
+// A page of notifications along with how many of all of them are unread
+type NotificationPage struct {
+	Unread        uint           `json:"Unread"`
+	Notifications []Notification `json:"Notifications"`
+}
+
+// Which types of notifications the user receives, all of them by default, moderation notices can't be turned off
+type NotificationPreferences struct {
+	Reply   bool `json:"Reply"`
+	Like    bool `json:"Like"`
+	Follow  bool `json:"Follow"`
+	Mention bool `json:"Mention"`
+}
+
+func (p NotificationPreferences) Allows(notificationType NotificationType) bool {
+	switch notificationType {
+	case NotificationReply:
+		return p.Reply
+	case NotificationLike:
+		return p.Like
+	case NotificationFollow, NotificationFollowRequest, NotificationFollowAccepted:
+		return p.Follow
+	case NotificationMention:
+		return p.Mention
+	case NotificationWarning, NotificationSuspension:
+		return true
+	}
+
+	return false
+}
+
+type NotificationRepository interface {
+	// Returns the id of the created notification, can return ErrRepeatedEntity, ErrNoMatchingDependency
+	Create(notification Notification) (uint, error)
+
+	// Returns an slice of notifications from newest to oldest, can return ErrEmptySelection
+	GetByUser(userID uint, limit, offset uint) ([]Notification, error)
+
+	// Returns how many notifications of the user are unread
+	CountUnread(userID uint) (uint, error)
+
+	// Can return ErrNoRowsAffected
+	MarkRead(userID, id uint) error
+
+	// Returns how many notifications were marked as read
+	MarkAllRead(userID uint) (uint, error)
+
+	// Returns the defaults when the user never changed them
+	GetPreferences(userID uint) (NotificationPreferences, error)
+
+	// Can return ErrNoMatchingDependency
+	UpdatePreferences(userID uint, preferences NotificationPreferences) error
+}
+
+type NotificationService interface {
+	// Returns a page of notifications from newest to oldest with the unread count, can return ErrIncorrectParameters
+	GetByUser(userID uint, page uint) (NotificationPage, error)
+
+	// Can return ErrIncorrectParameters, ErrNotExistingEntity
+	MarkRead(userID, id uint) error
+
+	// Returns how many notifications were marked as read, can return ErrIncorrectParameters
+	MarkAllRead(userID uint) (uint, error)
+
+	// Can return ErrIncorrectParameters
+	GetPreferences(userID uint) (NotificationPreferences, error)
+
+	// Can return ErrIncorrectParameters, ErrDependencyNotSatisfied
+	UpdatePreferences(userID uint, preferences NotificationPreferences) error
+}
@@ -7,20 +7,108 @@ import (
 )
 
 type Post struct {
-	PostID       uint      `json:"PostID"`
-	OwnerID      uint      `json:"OwnerID"`
-	Title        string    `json:"Title"`
-	Description  string    `json:"Description"`
-	Content      string    `json:"Content"`
-	CreationDate time.Time `json:"CreationDate"`
-	Likes        uint      `json:"Likes"`
+	PostID         uint             `json:"PostID"`
+	OwnerID        uint             `json:"OwnerID"`
+	CategoryID     uint             `json:"CategoryID"`
+	Title          string           `json:"Title"`
+	Slug           string           `json:"Slug"`
+	Description    string           `json:"Description"`
+	Content        string           `json:"Content"`
+	ContentHTML    string           `json:"ContentHTML"`
+	CreationDate   time.Time        `json:"CreationDate"`
+	Score          int              `json:"Score"`
+	Upvotes        uint             `json:"Upvotes"`
+	Downvotes      uint             `json:"Downvotes"`
+	CommentCount   uint             `json:"CommentCount"`
+	LastActivityAt time.Time        `json:"LastActivityAt"`
+	Tags           []string         `json:"Tags"`
+	Entities       []ContentEntity  `json:"Entities"`
+	Attachments    []Attachment     `json:"Attachments"`
+	Locked         bool             `json:"Locked"`
+	Pinned         bool             `json:"Pinned"`
+	Archived       bool             `json:"Archived"`
+	Moderation     ModerationAction `json:"Moderation,omitempty"`
+	*Viewer
+}
+
+// The state of a post after an edit, revisions are numbered from 1 for each post
+type PostRevision struct {
+	PostID        uint      `json:"PostID"`
+	Number        uint      `json:"Number"`
+	EditorID      uint      `json:"EditorID"`
+	Title         string    `json:"Title"`
+	Description   string    `json:"Description"`
+	Content       string    `json:"Content"`
+	ChangedFields []string  `json:"ChangedFields"`
+	RevisionDate  time.Time `json:"RevisionDate"`
+}
+
+// Line based diffs of every field between two revisions of a post
+type PostRevisionDiff struct {
+	PostID       uint   `json:"PostID"`
+	FromRevision uint   `json:"FromRevision"`
+	ToRevision   uint   `json:"ToRevision"`
+	Title        string `json:"Title"`
+	Description  string `json:"Description"`
+	Content      string `json:"Content"`
+}
+
+type PopularPeriod string
+
+const (
+	PopularPeriodToday   PopularPeriod = "today"
+	PopularPeriodWeek    PopularPeriod = "week"
+	PopularPeriodMonth   PopularPeriod = "month"
+	PopularPeriodAllTime PopularPeriod = "alltime"
+)
+
+type PostSort string
+
+const (
+	PostSortHot    PostSort = "hot"
+	PostSortTop    PostSort = "top"
+	PostSortNew    PostSort = "new"
+	PostSortRising PostSort = "rising"
+	PostSortActive PostSort = "active"
+)
+
+// What the ranking of a post is computed from
+type PostEngagement struct {
+	PostID       uint
+	Upvotes      uint
+	Downvotes    uint
+	Comments     uint
+	CreationDate time.Time
+}
+
+// Precomputed ranking scores of a post, higher ranks first
+type PostScore struct {
+	PostID uint
+	Hot    float64
+	Top    float64
+	Rising float64
+}
+
+type FeedMode string
+
+const (
+	FeedModeLatest FeedMode = "latest"
+	FeedModeRanked FeedMode = "ranked"
+)
+
+// A page of a feed, NextCursor is empty on the last page
+type FeedPage struct {
+	Posts      []Post `json:"Posts"`
+	NextCursor string `json:"NextCursor"`
 }
 
 func (p Post) Validate() bool {
 	conditions := []bool{
 		p.PostID != 0,
 		p.OwnerID != 0,
+		p.CategoryID != 0,
 		p.Title != "",
+		p.Slug != "",
 		p.Description != "",
 		p.Content != "",
 		!p.CreationDate.IsZero(),
@@ -30,74 +118,226 @@ func (p Post) Validate() bool {
 }
 
 type PostRepository interface {
-	// Returns the id of the created post, can return ErrNoMatchingDependency, ErrRepeatedEntity
-	Create(ownerID uint, title, description, content string) (uint, error)
+	// Returns the id of the created post, generates its slug, records its first revision and attaches the media in order, can return ErrNoMatchingDependency
+	Create(ownerID, categoryID uint, title, description, content string, tags []string, mediaIDs []uint) (uint, error)
 
-	// Can return ErrNoRowsAffected
+	// Marks the post as deleted, can return ErrNoRowsAffected
 	Delete(id uint) error
 
-	// Can return ErrIncorrectParameters, ErrNotExistingEntity,
-	UpdateTitle(id uint, newTitle string) error
+	// Regenerates the slug keeping the previous one for redirects and records a revision, can return ErrNoRowsAffected
+	UpdateTitle(id, editorID uint, newTitle string) error
 
-	// Can return ErrNoRowsAffected
-	UpdateDescription(id uint, newDescription string) error
+	// Replaces every tag of the post, can return ErrNoRowsAffected
+	UpdateTags(id uint, tags []string) error
 
-	// Can return ErrNoRowsAffected
-	UpdateContent(id uint, newContent string) error
+	// Records a revision, can return ErrNoRowsAffected
+	UpdateDescription(id, editorID uint, newDescription string) error
+
+	// Records a revision, can return ErrNoRowsAffected
+	UpdateContent(id, editorID uint, newContent string) error
+
+	// Sets the fields of the post back to the ones of the revision and records a new revision, can return ErrNoRowsAffected
+	Rollback(id, editorID, revision uint) error
+
+	// Returns an slice of revisions from oldest to newest, can return ErrEmptySelection
+	GetRevisions(postID uint) ([]PostRevision, error)
+
+	// Returns a revision and can return ErrEmptySelection
+	GetRevision(postID, revision uint) (PostRevision, error)
 
 	// Returns a valid profile and can return ErrEmptySelection
 	GetByID(id uint) (Post, error)
 
-	// Returns an slice of valid posts, can return ErrEmptySelection
-	GetByUser(userId uint) ([]Post, error)
+	// Returns an slice of valid posts with the pinned ones first, can return ErrEmptySelection
+	// Only selects posts the viewer can see, viewerID is 0 for anonymous viewers
+	GetByUser(viewerID, userId uint) ([]Post, error)
+
+	// Matches current and previous slugs, returns a valid post and can return ErrEmptySelection
+	GetBySlug(slug string) (Post, error)
+
+	// Orders by the precomputed scores, returns an slice of valid posts, can return ErrEmptySelection
+	// Only selects posts the viewer can see, viewerID is 0 for anonymous viewers
+	GetPopularAfter(viewerID uint, moment time.Time, order PostSort, amount uint) ([]Post, error)
+
+	// Returns an slice of valid posts by profiles the follower follows from newest to oldest, can return ErrEmptySelection
+	// Starts after the given post, or from the newest when beforeID is 0
+	// Skips profiles the follower muted
+	GetByFollowerBefore(followerID uint, beforeDate time.Time, beforeID uint, amount uint) ([]Post, error)
+
+	// Returns an slice of valid posts by profiles the follower follows created after moment, can return ErrEmptySelection
+	// Skips profiles the follower muted
+	GetByFollowerAfter(followerID uint, moment time.Time, amount uint) ([]Post, error)
+
+	// Returns an slice of valid posts from newest to oldest, can return ErrEmptySelection
+	// Only selects posts the viewer can see, viewerID is 0 for anonymous viewers
+	GetByTag(viewerID uint, tag string, limit, offset uint) ([]Post, error)
+
+	// Orders by the precomputed scores, returns an slice of valid posts, can return ErrEmptySelection
+	// Includes posts in subcategories, pinned posts come first even if they're older than moment
+	// Only selects posts the viewer can see, viewerID is 0 for anonymous viewers
+	GetPopularInCategoryAfter(viewerID, categoryID uint, moment time.Time, order PostSort, amount uint) ([]Post, error)
+
+	// Returns what the ranking of every valid post is computed from
+	GetEngagement() ([]PostEngagement, error)
+
+	// Replaces the precomputed scores of the posts
+	SaveScores(scores []PostScore, refreshedAt time.Time) error
 
-	// Returns an slice of valid posts, can return ErrEmptySelection
-	GetPopularAfter(moment time.Time, amount uint) ([]Post, error)
+	// A value of 0 removes the vote, keeps the vote counters in sync, can return ErrNoMatchingDependency
+	Vote(userId uint, postId uint, value int) error
 
-	// Can return ErrRepeatedEntity, ErrNoMatchingDependency
-	AddLike(userId uint, postId uint) error
+	// Stores the rendered HTML and the entities of the post, replaces its mentions and hashtags, can return ErrNoRowsAffected
+	SetRendered(id uint, contentHTML string, entities []ContentEntity) error
+
+	// Returns an slice of valid posts mentioning the user from newest to oldest, can return ErrEmptySelection
+	// Only returns posts the viewer can see, viewerID is 0 for anonymous viewers
+	GetByMention(viewerID, userID uint) ([]Post, error)
+
+	// Returns the viewer fields of the posts for the user by post ID
+	GetViewerStates(viewerID uint, postIDs []uint) (map[uint]Viewer, error)
+
+	// Returns an slice of valid posts the user upvoted from newest to oldest, can return ErrEmptySelection
+	// Only returns posts the viewer can see, viewerID is 0 for anonymous viewers
+	GetLikedBy(viewerID, userID uint) ([]Post, error)
+
+	// Clears the moderation action that removed it, can return ErrNoRowsAffected
+	Restore(id uint) error
+
+	// Returns a valid soft deleted post along with the moderation action that removed it if any, can return ErrEmptySelection
+	GetDeletedByID(id uint) (Post, error)
+
+	// Returns the amount of purged posts, comments and votes on them are purged too, posts hidden by moderators are kept
+	PurgeDeletedBefore(moment time.Time) (uint, error)
+
+	// Marks the post as deleted by the action even if its owner already deleted it, can return ErrNoRowsAffected
+	Moderate(id uint, action ModerationAction) error
+
+	// Can return ErrNoRowsAffected
+	UpdateLocked(id uint, locked bool) error
 
 	// Can return ErrNoRowsAffected
-	DeleteLike(userId uint, postId uint) error
+	UpdatePinned(id uint, pinned bool) error
+
+	// Unarchiving restarts the inactivity of the post, can return ErrNoRowsAffected
+	UpdateArchived(id uint, archived bool) error
+
+	// Archives the valid posts without activity since moment that aren't pinned, returns the amount of archived posts
+	ArchiveInactiveBefore(moment time.Time) (uint, error)
 }
 
 type PostService interface {
-	// Returns the ID of the created post, can return ErrIncorrectParameters, ErrDependencyNotSatisfied, ErrAlreadyExisting
-	Create(ownerID uint, title, description, content string) (uint, error)
+	// Returns the ID of the created post, can return ErrIncorrectParameters, ErrDependencyNotSatisfied, ErrArchived, ErrSuspended
+	// Tags are normalized and attachments must be media uploaded by the owner
+	// Renders the content, notifies the mentioned profiles and publishes the post to its owner's topic
+	Create(ownerID, categoryID uint, title, description, content string, tags []string, attachments []uint) (uint, error)
 
-	// Can return ErrIncorrectParameters, ErrNotExistingEntity
-	Delete(id uint) error
+	// Only the owner or a moderator can delete, can return ErrIncorrectParameters, ErrNotExistingEntity, ErrNotAuthorized
+	Delete(userId, id uint) error
+
+	// Only the owner or a moderator can edit, can return ErrIncorrectParameters, ErrNotExistingEntity, ErrNotAuthorized, ErrArchived, ErrSuspended
+	UpdateTitle(userId, id uint, title string) error
+
+	// Only the owner or a moderator can edit, can return ErrIncorrectParameters, ErrNotExistingEntity, ErrNotAuthorized, ErrArchived, ErrSuspended
+	// Tags are normalized
+	UpdateTags(userId, id uint, tags []string) error
+
+	// Only the owner or a moderator can edit, can return ErrIncorrectParameters, ErrNotExistingEntity, ErrNotAuthorized, ErrArchived, ErrSuspended
+	UpdateDescription(userId, id uint, description string) error
+
+	// Only the owner or a moderator can edit, can return ErrIncorrectParameters, ErrNotExistingEntity, ErrNotAuthorized, ErrArchived, ErrSuspended
+	// Renders the content again and notifies newly mentioned profiles
+	UpdateContent(userId, id uint, content string) error
+
+	// Returns a slice of revisions from oldest to newest, can return ErrIncorrectParameters, ErrNotExistingEntity
+	GetRevisions(postId uint) ([]PostRevision, error)
 
-	// Can return ErrIncorrectParameters, ErrNotExistingEntity, ErrAlreadyExisting
-	UpdateTitle(id uint, title string) error
+	// Returns a revision, can return ErrIncorrectParameters, ErrNotExistingEntity
+	GetRevision(postId, revision uint) (PostRevision, error)
 
-	// Can return ErrIncorrectParameters, ErrNotExistingEntity,
-	UpdateDescription(id uint, description string) error
+	// Returns the differences from one revision to another, can return ErrIncorrectParameters, ErrNotExistingEntity
+	DiffRevisions(postId, fromRevision, toRevision uint) (PostRevisionDiff, error)
 
-	// Can return ErrIncorrectParameters, ErrNotExistingEntity,
-	UpdateContent(id uint, content string) error
+	// Only the owner can roll back, can return ErrIncorrectParameters, ErrNotExistingEntity, ErrNotAuthorized, ErrArchived, ErrSuspended
+	// Renders the restored content again
+	Rollback(userId, postId, revision uint) error
 
 	// Returns a valid post, can return ErrIncorrectParameters, ErrNotExistingEntity
 	GetByID(id uint) (Post, error)
 
-	// Returns a slice of valid posts, can return ErrIncorrectParameters, ErrNotExistingEntity
-	GetByUser(userId uint) ([]Post, error)
+	// Returns a slice of valid posts with the pinned ones first, can return ErrIncorrectParameters, ErrNotExistingEntity
+	// Only returns posts the viewer can see, viewerId is 0 for anonymous viewers
+	GetByUser(viewerId, userId uint) ([]Post, error)
+
+	// Returns a valid post whose current or previous slug matches, can return ErrIncorrectParameters, ErrNotExistingEntity
+	GetBySlug(slug string) (Post, error)
 
 	// Returns a slice of valid posts, can return ErrIncorrectParameters, ErrNotExistingEntity
-	GetPopularToday() ([]Post, error)
+	// Only returns posts the viewer can see, viewerId is 0 for anonymous viewers
+	GetPopularToday(viewerId uint, order PostSort) ([]Post, error)
 
 	// Returns a slice of valid posts, can return ErrIncorrectParameters, ErrNotExistingEntity
-	GetPopularLastWeek() ([]Post, error)
+	// Only returns posts the viewer can see, viewerId is 0 for anonymous viewers
+	GetPopularLastWeek(viewerId uint, order PostSort) ([]Post, error)
 
 	// Returns a slice of valid posts, can return ErrIncorrectParameters, ErrNotExistingEntity
-	GetPopularLastMonth() ([]Post, error)
+	// Only returns posts the viewer can see, viewerId is 0 for anonymous viewers
+	GetPopularLastMonth(viewerId uint, order PostSort) ([]Post, error)
 
 	// Returns a slice of valid posts, can return ErrIncorrectParameters, ErrNotExistingEntity
-	GetPopularAllTime() ([]Post, error)
+	// Only returns posts the viewer can see, viewerId is 0 for anonymous viewers
+	GetPopularAllTime(viewerId uint, order PostSort) ([]Post, error)
+
+	// Recomputes the ranking scores of every post, returns the amount of ranked posts
+	RefreshScores() (uint, error)
+
+	// Returns a page of valid posts by followed profiles, an empty cursor starts from the first page, can return ErrIncorrectParameters, ErrNotExistingEntity
+	GetFeed(userId uint, mode FeedMode, cursor string) (FeedPage, error)
+
+	// Returns a page of valid posts from newest to oldest, pages start at 1, can return ErrIncorrectParameters, ErrNotExistingEntity
+	// Only returns posts the viewer can see, viewerId is 0 for anonymous viewers
+	GetByTag(viewerId uint, tag string, page uint) ([]Post, error)
+
+	// Includes posts in subcategories, returns a slice of valid posts with the pinned ones first, can return ErrIncorrectParameters, ErrNotExistingEntity
+	// Only returns posts the viewer can see, viewerId is 0 for anonymous viewers
+	GetPopularInCategory(viewerId, categoryId uint, period PopularPeriod, order PostSort) ([]Post, error)
+
+	// Value is 1 or -1 and 0 removes the vote, can return ErrIncorrectParameters, ErrNotExistingEntity, ErrDependencyNotSatisfied, ErrNotAuthorized, ErrArchived, ErrSuspended
+	// Upvotes notify the owner, the new counts are published to the post's topic
+	Vote(userId uint, postId uint, value int) error
+
+	// Fills the viewer fields of the posts for the user, can return ErrIncorrectParameters
+	AddViewer(viewerId uint, posts []Post) error
+
+	// Private profiles' posts are only visible to their approved followers, viewerId is 0 for anonymous viewers, can return ErrNotAuthorized
+	CheckVisible(viewerId, ownerId uint) error
+
+	// Only the owner or a moderator can restore, can return ErrIncorrectParameters, ErrNotExistingEntity, ErrNotAuthorized
+	// Only moderators can restore posts removed by moderation
+	Restore(userId uint, postId uint) error
+
+	// Returns the amount of purged posts, can return ErrIncorrectParameters
+	PurgeDeleted(retention time.Duration) (uint, error)
+
+	// Only the owner or a moderator can lock, can return ErrIncorrectParameters, ErrNotExistingEntity, ErrNotAuthorized, ErrSuspended
+	// Locked posts don't accept comments
+	Lock(userId, postId uint) error
+
+	// Only the owner or a moderator can unlock, can return ErrIncorrectParameters, ErrNotExistingEntity, ErrNotAuthorized, ErrSuspended
+	Unlock(userId, postId uint) error
+
+	// Only the owner or a moderator can pin, can return ErrIncorrectParameters, ErrNotExistingEntity, ErrNotAuthorized, ErrSuspended
+	// Pinned posts come first in their category and their owner's posts
+	Pin(userId, postId uint) error
+
+	// Only the owner or a moderator can unpin, can return ErrIncorrectParameters, ErrNotExistingEntity, ErrNotAuthorized, ErrSuspended
+	Unpin(userId, postId uint) error
+
+	// Only the owner or a moderator can archive, archived posts are read-only, can return ErrIncorrectParameters, ErrNotExistingEntity, ErrNotAuthorized, ErrSuspended
+	Archive(userId, postId uint) error
 
-	// Can return ErIncorrectParameters, ErrAlreadyExisting, ErrDependencyNotSatisfied
-	AddLike(userId uint, postId uint) error
+	// Only the owner or a moderator can unarchive, can return ErrIncorrectParameters, ErrNotExistingEntity, ErrNotAuthorized, ErrSuspended
+	Unarchive(userId, postId uint) error
 
-	// Can return ErrIncorrectParameters, ErrNotExistingEntity
-	DeleteLike(userId uint, postId uint) error
+	// Archives the posts without activity for longer than inactivity, returns the amount of archived posts, can return ErrIncorrectParameters
+	ArchiveInactive(inactivity time.Duration) (uint, error)
 }
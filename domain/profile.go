@@ -10,6 +10,7 @@ type Profile struct {
 	BackgroundPath string `json:"BackgroundPath"`
 	Followers      uint   `json:"Followers"`
 	Follows        uint   `json:"Follows"`
+	IsPrivate      bool   `json:"IsPrivate"`
 }
 
 func (p Profile) Validate() bool {
@@ -59,11 +60,51 @@ type ProfileRepository interface {
 	// Returns an slice of valid profiles, can return ErrEmptySelection
 	GetFollowsByTagName(tagName string) ([]Profile, error)
 
-	// Can return ErrRepeatedEntity, ErrNoMatchingDependency
+	// Keeps the follow counters in sync, can return ErrRepeatedEntity, ErrNoMatchingDependency
 	AddFollow(followerId uint, followedId uint) error
 
-	// Can return ErrNoRowsAffected
+	// Keeps the follow counters in sync, can return ErrNoRowsAffected
 	DeleteFollow(followerId uint, followedId uint) error
+
+	// Stops following each other and drops their follow requests, keeps the follow counters in sync, can return ErrRepeatedEntity, ErrNoMatchingDependency
+	AddBlock(blockerID, blockedID uint) error
+
+	// Can return ErrNoRowsAffected
+	DeleteBlock(blockerID, blockedID uint) error
+
+	IsBlocked(blockerID, blockedID uint) (bool, error)
+
+	// Returns an slice of valid profiles, can return ErrEmptySelection
+	GetBlocked(blockerID uint) ([]Profile, error)
+
+	// Can return ErrRepeatedEntity, ErrNoMatchingDependency
+	AddMute(muterID, mutedID uint) error
+
+	// Can return ErrNoRowsAffected
+	DeleteMute(muterID, mutedID uint) error
+
+	// Returns an slice of valid profiles, can return ErrEmptySelection
+	GetMuted(muterID uint) ([]Profile, error)
+
+	GetMutedIDs(muterID uint) ([]uint, error)
+
+	// Making the profile public accepts its pending follow requests, keeps the follow counters in sync, can return ErrNoRowsAffected
+	UpdatePrivacy(id uint, isPrivate bool) error
+
+	// Can return ErrRepeatedEntity, ErrNoMatchingDependency
+	AddFollowRequest(requesterID, targetID uint) error
+
+	// Can return ErrNoRowsAffected
+	DeleteFollowRequest(requesterID, targetID uint) error
+
+	// Turns the request into a follow, keeps the follow counters in sync, can return ErrNoRowsAffected
+	ApproveFollowRequest(requesterID, targetID uint) error
+
+	// Returns an slice of valid profiles that requested to follow the target, can return ErrEmptySelection
+	GetFollowRequests(targetID uint) ([]Profile, error)
+
+	// Returns which of the profiles are private and not followed by the viewer, the viewer itself is never restricted
+	GetRestrictedIDs(viewerID uint, userIDs []uint) ([]uint, error)
 }
 
 type ProfileService interface {
@@ -79,10 +120,10 @@ type ProfileService interface {
 	// Can return ErrNotExistingEntity
 	UpdateDisplayName(id uint, displayName string) error
 
-	// Can return ErrNotExistingEntity
+	// The path has to be the URL of media uploaded by the user, can return ErrIncorrectParameters, ErrNotExistingEntity
 	UpdatePicturePath(id uint, picturePath string) error
 
-	// Can return ErrNotExistingEntity
+	// The path has to be the URL of media uploaded by the user, can return ErrIncorrectParameters, ErrNotExistingEntity
 	UpdateBackgroundPath(id uint, backgroundPath string) error
 
 	// Returns a valid profile, can return ErrIncorrectParameters, ErrNotExistingEntity
@@ -103,9 +144,41 @@ type ProfileService interface {
 	// Returns a slice of valid profiles, can return ErrIncorrectParameters, ErrNotExistingEntity
 	GetFollowsByTagName(tagName string) ([]Profile, error)
 
-	// Can return ErrAlreadyExisting, ErrIncorrectParameters, ErrDependencyNotSatisfied
-	AddFollow(followerId uint, followedId uint) error
+	// Returns true when the follow was only requested, can return ErrAlreadyExisting, ErrIncorrectParameters, ErrDependencyNotSatisfied, ErrNotAuthorized
+	// Following a private profile only requests it and profiles that blocked each other can't follow
+	// Notifies the followed profile and publishes the follow to its topic
+	AddFollow(followerId uint, followedId uint) (bool, error)
 
-	// Can return ErrIncorrectParameters, ErrNotExistingEntity
+	// Cancels the follow request when it's still pending, can return ErrIncorrectParameters, ErrNotExistingEntity
 	DeleteFollow(followerId uint, followedId uint) error
+
+	// Removes the follows between both profiles, can return ErrIncorrectParameters, ErrAlreadyExisting, ErrDependencyNotSatisfied
+	Block(userId uint, blockedId uint) error
+
+	// Can return ErrIncorrectParameters, ErrNotExistingEntity
+	Unblock(userId uint, blockedId uint) error
+
+	// Returns a slice of valid profiles, can return ErrIncorrectParameters, ErrNotExistingEntity
+	GetBlocked(userId uint) ([]Profile, error)
+
+	// Hides the posts and comments of the profile from the user, can return ErrIncorrectParameters, ErrAlreadyExisting, ErrDependencyNotSatisfied
+	Mute(userId uint, mutedId uint) error
+
+	// Can return ErrIncorrectParameters, ErrNotExistingEntity
+	Unmute(userId uint, mutedId uint) error
+
+	// Returns a slice of valid profiles, can return ErrIncorrectParameters, ErrNotExistingEntity
+	GetMuted(userId uint) ([]Profile, error)
+
+	// Making the profile public accepts its pending follow requests, can return ErrIncorrectParameters, ErrNotExistingEntity
+	UpdatePrivacy(id uint, isPrivate bool) error
+
+	// Returns a slice of the profiles waiting for the user to approve them, can return ErrIncorrectParameters, ErrNotExistingEntity
+	GetFollowRequests(userId uint) ([]Profile, error)
+
+	// Turns the request into a follow, notifies the requester and publishes the follow to the user's topic, can return ErrIncorrectParameters, ErrNotExistingEntity
+	ApproveFollowRequest(userId uint, requesterId uint) error
+
+	// Can return ErrIncorrectParameters, ErrNotExistingEntity
+	RejectFollowRequest(userId uint, requesterId uint) error
 }
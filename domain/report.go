@@ -0,0 +1,125 @@
+package domain
+
+import "time"
+
+type ReportTarget string
+
+const (
+	ReportTargetPost    ReportTarget = "post"
+	ReportTargetComment ReportTarget = "comment"
+)
+
+type ReportReason string
+
+const (
+	ReportReasonSpam           ReportReason = "spam"
+	ReportReasonHarassment     ReportReason = "harassment"
+	ReportReasonHate           ReportReason = "hate"
+	ReportReasonViolence       ReportReason = "violence"
+	ReportReasonSexual         ReportReason = "sexual"
+	ReportReasonMisinformation ReportReason = "misinformation"
+	ReportReasonOther          ReportReason = "other"
+)
+
+func (r ReportReason) Valid() bool {
+	switch r {
+	case ReportReasonSpam, ReportReasonHarassment, ReportReasonHate, ReportReasonViolence, ReportReasonSexual, ReportReasonMisinformation, ReportReasonOther:
+		return true
+	}
+
+	return false
+}
+
+type ReportStatus string
+
+const (
+	ReportStatusOpen      ReportStatus = "open"
+	ReportStatusActioned  ReportStatus = "actioned"
+	ReportStatusDismissed ReportStatus = "dismissed"
+)
+
+func (s ReportStatus) Valid() bool {
+	return s == ReportStatusOpen || s == ReportStatusActioned || s == ReportStatusDismissed
+}
+
+// What a moderator decided about a report, hide keeps the content out of purges so it can be restored later
+type ModerationAction string
+
+const (
+	ModerationHide    ModerationAction = "hide"
+	ModerationDelete  ModerationAction = "delete"
+	ModerationWarn    ModerationAction = "warn"
+	ModerationSuspend ModerationAction = "suspend"
+	ModerationDismiss ModerationAction = "dismiss"
+)
+
+func (a ModerationAction) Valid() bool {
+	switch a {
+	case ModerationHide, ModerationDelete, ModerationWarn, ModerationSuspend, ModerationDismiss:
+		return true
+	}
+
+	return false
+}
+
+// An entry of the moderation queue, every report of the same content while it's open is grouped in it
+type Report struct {
+	ID            uint                  `json:"ID"`
+	TargetType    ReportTarget          `json:"TargetType"`
+	TargetID      uint                  `json:"TargetID"`
+	AuthorID      uint                  `json:"AuthorID"`
+	Status        ReportStatus          `json:"Status"`
+	ReporterCount uint                  `json:"ReporterCount"`
+	Reasons       map[ReportReason]uint `json:"Reasons"`
+	CreatedAt     time.Time             `json:"CreatedAt"`
+	ResolvedAt    time.Time             `json:"ResolvedAt"`
+}
+
+// A decision of a moderator on a report, kept as the audit trail of the moderation
+type ModerationLogEntry struct {
+	ID          uint             `json:"ID"`
+	ModeratorID uint             `json:"ModeratorID"`
+	ReportID    uint             `json:"ReportID"`
+	Action      ModerationAction `json:"Action"`
+	TargetType  ReportTarget     `json:"TargetType"`
+	TargetID    uint             `json:"TargetID"`
+	AuthorID    uint             `json:"AuthorID"`
+	Note        string           `json:"Note"`
+	CreatedAt   time.Time        `json:"CreatedAt"`
+}
+
+type ReportRepository interface {
+	// Returns the id of the open report of the content, opening one if there isn't, can return ErrRepeatedEntity, ErrNoMatchingDependency
+	Create(targetType ReportTarget, targetID, authorID, reporterID uint, reason ReportReason, details string) (uint, error)
+
+	// Returns a valid report with its reasons, can return ErrEmptySelection
+	GetByID(id uint) (Report, error)
+
+	// Returns an slice of reports with their reasons, open ones by most reporters first and the rest by latest resolution, can return ErrEmptySelection
+	GetByStatus(status ReportStatus, limit, offset uint) ([]Report, error)
+
+	// Closes an open report with the status and records the decision, can return ErrNoRowsAffected
+	Resolve(status ReportStatus, entry ModerationLogEntry) error
+
+	// Returns an slice of decisions from newest to oldest, can return ErrEmptySelection
+	GetLog(limit, offset uint) ([]ModerationLogEntry, error)
+}
+
+type ReportService interface {
+	// Returns the id of the report the content is queued in, can return ErrIncorrectParameters, ErrNotExistingEntity, ErrAlreadyExisting, ErrDependencyNotSatisfied, ErrSuspended
+	// Authors can't report their own content
+	Report(reporterID uint, targetType ReportTarget, targetID uint, reason ReportReason, details string) (uint, error)
+
+	// Returns a page of reports with the status, only moderators can see them, can return ErrIncorrectParameters, ErrNotAuthorized
+	GetQueue(moderatorID uint, status ReportStatus, page uint) ([]Report, error)
+
+	// Only moderators can see reports, can return ErrIncorrectParameters, ErrNotAuthorized, ErrNotExistingEntity
+	GetByID(moderatorID, id uint) (Report, error)
+
+	// Applies the action and closes the report, can return ErrIncorrectParameters, ErrNotAuthorized, ErrNotExistingEntity, ErrArchived
+	// Days is only used to suspend the author
+	Act(moderatorID, id uint, action ModerationAction, note string, days uint) error
+
+	// Returns a page of decisions from newest to oldest, only moderators can see them, can return ErrIncorrectParameters, ErrNotAuthorized
+	GetLog(moderatorID uint, page uint) ([]ModerationLogEntry, error)
+}
@@ -0,0 +1,49 @@
+package domain
+
+import "time"
+
+type SearchType string
+
+const (
+	SearchTypePosts    SearchType = "posts"
+	SearchTypeComments SearchType = "comments"
+	SearchTypeProfiles SearchType = "profiles"
+)
+
+// Narrows a search down, zero values don't filter, profiles ignore every filter
+type SearchFilter struct {
+	AuthorID   uint
+	CategoryID uint
+	From       time.Time
+	To         time.Time
+	// Posts of private profiles and their comments only match for the owner and approved followers, 0 is an anonymous viewer
+	ViewerID uint
+}
+
+// A match of a search, matched terms in Title and Snippet are wrapped in <mark> tags and the rest is HTML escaped
+type SearchResult struct {
+	Type         SearchType `json:"Type"`
+	ID           uint       `json:"ID"`
+	PostID       uint       `json:"PostID,omitempty"`
+	AuthorID     uint       `json:"AuthorID"`
+	Title        string     `json:"Title,omitempty"`
+	Snippet      string     `json:"Snippet"`
+	Rank         float64    `json:"Rank"`
+	CreationDate time.Time  `json:"CreationDate"`
+}
+
+type SearchRepository interface {
+	// Expects a valid FTS5 query, returns an slice of results from best to worst match, can return ErrEmptySelection
+	SearchPosts(query string, filter SearchFilter, limit, offset uint) ([]SearchResult, error)
+
+	// Expects a valid FTS5 query, returns an slice of results from best to worst match, can return ErrEmptySelection
+	SearchComments(query string, filter SearchFilter, limit, offset uint) ([]SearchResult, error)
+
+	// Expects a valid FTS5 query, returns an slice of results from best to worst match, can return ErrEmptySelection
+	SearchProfiles(query string, limit, offset uint) ([]SearchResult, error)
+}
+
+type SearchService interface {
+	// Matches every word of text, returns a page of results from best to worst match, pages start at 1, can return ErrIncorrectParameters, ErrNotExistingEntity
+	Search(text string, searchType SearchType, filter SearchFilter, page uint) ([]SearchResult, error)
+}
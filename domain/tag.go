@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+// A topic label shared by posts, Activity is only filled when ranking trending tags
+type Tag struct {
+	Name      string `json:"Name"`
+	PostCount uint   `json:"PostCount"`
+	Activity  uint   `json:"Activity,omitempty"`
+}
+
+type TagRepository interface {
+	// Returns an slice of tags in use starting with prefix sorted by usage, can return ErrEmptySelection
+	GetByPrefix(prefix string, amount uint) ([]Tag, error)
+
+	// Ranks tags by the posts and comments created on their posts after moment, returns an slice of tags, can return ErrEmptySelection
+	GetTrendingAfter(moment time.Time, amount uint) ([]Tag, error)
+}
+
+type TagService interface {
+	// Returns a slice of tags completing the prefix, can return ErrIncorrectParameters, ErrNotExistingEntity
+	Autocomplete(prefix string) ([]Tag, error)
+
+	// Returns a slice of the tags with the most activity during the last week, can return ErrNotExistingEntity
+	GetTrending() ([]Tag, error)
+}
@@ -11,6 +11,13 @@ type User struct {
 	Email            string    `json:"Email"`
 	HashedPassword   string    `json:"HashedPassword"`
 	RegistrationDate time.Time `json:"RegistrationDate"`
+	IsModerator      bool      `json:"IsModerator"`
+	SuspendedUntil   time.Time `json:"SuspendedUntil"`
+}
+
+// Reports whether a moderator suspended the user until after moment
+func (u User) IsSuspended(moment time.Time) bool {
+	return u.SuspendedUntil.After(moment)
 }
 
 func (u User) Validate() bool {
@@ -43,6 +50,9 @@ type UserRepository interface {
 
 	// Returns a valid user and can return ErrEmptySelection
 	GetByEmail(email string) (User, error)
+
+	// Can return ErrNoRowsAffected
+	Suspend(id uint, until time.Time) error
 }
 
 type UserService interface {
@@ -69,4 +79,7 @@ type UserService interface {
 
 	// Returns nil if user is authorized, can return ErrNotValidCredentials, ErrNotExistingEntity
 	Authorize(id uint, jwtTokenString string) error
+
+	// Returns the ID of the user the token was issued to, can return ErrNotValidCredentials
+	Identify(jwtTokenString string) (uint, error)
 }
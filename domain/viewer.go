@@ -0,0 +1,21 @@
+package domain
+
+// What a post or comment looks like to the authenticated user reading it, left out of anonymous reads
+type Viewer struct {
+	MyVote        int  `json:"MyVote"`
+	LikedByMe     bool `json:"LikedByMe"`
+	IsOwner       bool `json:"IsOwner"`
+	FollowsAuthor bool `json:"FollowsAuthor"`
+}
+
+// Valid posts and comments a user has upvoted
+type UserLikes struct {
+	Posts    []Post    `json:"Posts"`
+	Comments []Comment `json:"Comments"`
+}
+
+type LikeService interface {
+	// Returns what the user has upvoted, can return ErrIncorrectParameters, ErrNotExistingEntity
+	// Only returns what the viewer can see, viewerId is 0 for anonymous viewers
+	GetByUser(viewerId, userId uint) (UserLikes, error)
+}
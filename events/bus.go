@@ -0,0 +1,128 @@
+package events
+
+import (
+	"sync"
+
+	"github.com/AlejandroJorge/forum-rest-api/config"
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+)
+
+// How many undelivered events a subscriber can have before it's dropped
+const subscriberBufferSize = 64
+
+type memoryBus struct {
+	mu          sync.Mutex
+	lastID      uint64
+	replay      []domain.Event
+	replaySize  int
+	subscribers map[*memorySubscription]bool
+}
+
+type memorySubscription struct {
+	bus    *memoryBus
+	topics map[string]bool
+	events chan domain.Event
+}
+
+// Delivers the event to the current subscribers of the topic without waiting for them
+func (bus *memoryBus) Publish(topic string, eventType domain.EventType, data interface{}) {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+
+	bus.lastID++
+	event := domain.Event{ID: bus.lastID, Topic: topic, Type: eventType, Data: data}
+
+	if bus.replaySize > 0 {
+		if len(bus.replay) == bus.replaySize {
+			bus.replay = bus.replay[1:]
+		}
+		bus.replay = append(bus.replay, event)
+	}
+
+	bus.deliver(event)
+}
+
+// Delivers the event like Publish but doesn't keep it for subscribers resuming later
+func (bus *memoryBus) PublishEphemeral(topic string, eventType domain.EventType, data interface{}) {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+
+	bus.deliver(domain.Event{Topic: topic, Type: eventType, Data: data})
+}
+
+// Must be called holding the lock, subscribers that can't keep up are dropped
+func (bus *memoryBus) deliver(event domain.Event) {
+	for subscriber := range bus.subscribers {
+		if !subscriber.topics[event.Topic] {
+			continue
+		}
+
+		select {
+		case subscriber.events <- event:
+		default:
+			bus.drop(subscriber)
+		}
+	}
+}
+
+// Returns the recent events of the topics published after lastEventID along with the subscription, a lastEventID of 0 replays nothing
+func (bus *memoryBus) Subscribe(topics []string, lastEventID uint64) ([]domain.Event, domain.EventSubscription) {
+	subscriber := &memorySubscription{
+		bus:    bus,
+		topics: make(map[string]bool),
+		events: make(chan domain.Event, subscriberBufferSize),
+	}
+	for _, topic := range topics {
+		subscriber.topics[topic] = true
+	}
+
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+
+	var missed []domain.Event
+	if lastEventID != 0 {
+		for _, event := range bus.replay {
+			if event.ID > lastEventID && subscriber.topics[event.Topic] {
+				missed = append(missed, event)
+			}
+		}
+	}
+
+	bus.subscribers[subscriber] = true
+
+	return missed, subscriber
+}
+
+// Must be called holding the lock
+func (bus *memoryBus) drop(subscriber *memorySubscription) {
+	if bus.subscribers[subscriber] {
+		delete(bus.subscribers, subscriber)
+		close(subscriber.events)
+	}
+}
+
+func (subscription *memorySubscription) Events() <-chan domain.Event {
+	return subscription.events
+}
+
+func (subscription *memorySubscription) Unsubscribe() {
+	subscription.bus.mu.Lock()
+	defer subscription.bus.mu.Unlock()
+
+	subscription.bus.drop(subscription)
+}
+
+// Keeps the last replaySize events for subscribers resuming after a disconnect
+func NewMemoryBus(replaySize uint) domain.EventBus {
+	return &memoryBus{replaySize: int(replaySize), subscribers: make(map[*memorySubscription]bool)}
+}
+
+var defaultBus domain.EventBus
+
+// The bus shared by every service in the process
+func DefaultBus() domain.EventBus {
+	if defaultBus == nil {
+		defaultBus = NewMemoryBus(config.GetParams().StreamReplaySize)
+	}
+	return defaultBus
+}
@@ -0,0 +1,32 @@
+package jobs
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/logging"
+)
+
+// Periodically archives posts that have had no activity for longer than inactivity
+func StartArchive(postServ domain.PostService, interval, inactivity time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			runArchive(postServ, inactivity)
+			<-ticker.C
+		}
+	}()
+}
+
+func runArchive(postServ domain.PostService, inactivity time.Duration) {
+	archived, err := postServ.ArchiveInactive(inactivity)
+	if err != nil {
+		logging.LogJob("archive", "couldn't archive inactive posts")
+		return
+	}
+
+	logging.LogJob("archive", fmt.Sprintf("archived %d posts", archived))
+}
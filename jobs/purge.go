@@ -0,0 +1,38 @@
+package jobs
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/logging"
+)
+
+// Periodically removes posts and comments that have been soft deleted for longer than retention
+func StartPurge(postServ domain.PostService, commentServ domain.CommentService, interval, retention time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			runPurge(postServ, commentServ, retention)
+			<-ticker.C
+		}
+	}()
+}
+
+func runPurge(postServ domain.PostService, commentServ domain.CommentService, retention time.Duration) {
+	purgedComments, err := commentServ.PurgeDeleted(retention)
+	if err != nil {
+		logging.LogJob("purge", "couldn't purge comments")
+		return
+	}
+
+	purgedPosts, err := postServ.PurgeDeleted(retention)
+	if err != nil {
+		logging.LogJob("purge", "couldn't purge posts")
+		return
+	}
+
+	logging.LogJob("purge", fmt.Sprintf("purged %d posts and %d comments", purgedPosts, purgedComments))
+}
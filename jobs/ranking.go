@@ -0,0 +1,32 @@
+package jobs
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/logging"
+)
+
+// Periodically recomputes the precomputed ranking scores of posts
+func StartRankingRefresh(postServ domain.PostService, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			runRankingRefresh(postServ)
+			<-ticker.C
+		}
+	}()
+}
+
+func runRankingRefresh(postServ domain.PostService) {
+	ranked, err := postServ.RefreshScores()
+	if err != nil {
+		logging.LogJob("ranking", "couldn't refresh post scores")
+		return
+	}
+
+	logging.LogJob("ranking", fmt.Sprintf("refreshed scores of %d posts", ranked))
+}
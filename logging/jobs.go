@@ -0,0 +1,11 @@
+package logging
+
+import "log"
+
+func LogJob(name string, message string) {
+	msg := `
+	[JOB] %s: %s
+	`
+
+	log.Printf(msg, name, message)
+}
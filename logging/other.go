@@ -14,7 +14,36 @@ func LogSetup() {
 	[CONFIG] %s
 	[CONFIG] %d
 	[CONFIG] %s
+	[CONFIG] %d
+	[CONFIG] %d
+	[CONFIG] %d
+	[CONFIG] %d
+	[CONFIG] %d
+	[CONFIG] %.2f
+	[CONFIG] %.2f
+	[CONFIG] %d
+	[CONFIG] %d
+	[CONFIG] %d
+	[CONFIG] %d
+	[CONFIG] %d
+	[CONFIG] %s
+	[CONFIG] %s
+	[CONFIG] %s
+	[CONFIG] %s
+	[CONFIG] %d
+	[CONFIG] %d
+	[CONFIG] %d
+	[CONFIG] %d
+	[CONFIG] %d
+	[CONFIG] %d
 	`
 
-	log.Printf(msg, configParams.DbFolderName, configParams.DbFileName, configParams.Port, configParams.AuthSecret)
+	log.Printf(msg, configParams.DbFolderName, configParams.DbFileName, configParams.Port, configParams.AuthSecret,
+		configParams.DeletedRetentionDays, configParams.PurgeIntervalMinutes, configParams.MaxCommentDepth,
+		configParams.MaxTagsPerPost, configParams.PageSize, configParams.RankingGravity, configParams.RankingCommentWeight,
+		configParams.RankingRefreshMinutes, configParams.RisingWindowHours, configParams.StreamHeartbeatSeconds,
+		configParams.StreamReplaySize, configParams.WSMaxSubscriptions, configParams.MediaStore, configParams.MediaFolderName,
+		configParams.S3Endpoint, configParams.S3Bucket, configParams.MaxUploadBytes, configParams.ThumbnailSize,
+		configParams.MaxAttachmentsPerPost, configParams.MaxConversationMembers,
+		configParams.ArchiveAfterDays, configParams.ArchiveIntervalMinutes)
 }
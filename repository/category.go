@@ -0,0 +1,250 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/logging"
+	"github.com/mattn/go-sqlite3"
+)
+
+type sqliteCategoryRepository struct {
+	db *sql.DB
+}
+
+// Returns the id of the created category placed after its siblings, can return ErrRepeatedEntity, ErrNoMatchingDependency
+func (repo sqliteCategoryRepository) Create(parentID uint, name string) (uint, error) {
+	db := repo.db
+
+	query := `
+	INSERT INTO Category(Parent_ID, Name, Position, Creation_Date)
+	VALUES (NULLIF(?, 0), ?, (
+		SELECT COALESCE(MAX(Position), 0) + 1 FROM Category WHERE COALESCE(Parent_ID, 0) = ?
+	), ?)
+	`
+	res, err := db.Exec(query, parentID, name, parentID, time.Now().Unix())
+	if sqliteErr, ok := err.(sqlite3.Error); ok {
+		if sqliteErr.ExtendedCode == sqlite3.ErrConstraintForeignKey {
+			logging.LogRepositoryError(ErrNoMatchingDependency)
+			return 0, ErrNoMatchingDependency
+		}
+		if sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			logging.LogRepositoryError(ErrRepeatedEntity)
+			return 0, ErrRepeatedEntity
+		}
+	}
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return 0, ErrUnknown
+	}
+
+	newId, err := res.LastInsertId()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return 0, ErrUnknown
+	}
+
+	return uint(newId), nil
+}
+
+// Returns a valid category and can return ErrEmptySelection
+func (repo sqliteCategoryRepository) GetByID(id uint) (domain.Category, error) {
+	db := repo.db
+
+	var category domain.Category
+	var creationDate int64
+	query := `
+	SELECT Category_ID, COALESCE(Parent_ID, 0), Name, Position, Is_Archived, Creation_Date
+	FROM Category
+	WHERE Category_ID = ?
+	`
+	row := db.QueryRow(query, id)
+	err := row.Scan(&category.CategoryID, &category.ParentID, &category.Name, &category.Position, &category.Archived, &creationDate)
+	if err == sql.ErrNoRows {
+		logging.LogRepositoryError(ErrEmptySelection)
+		return domain.Category{}, ErrEmptySelection
+	}
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return domain.Category{}, ErrUnknown
+	}
+
+	category.CreationDate = time.Unix(creationDate, 0)
+
+	return category, nil
+}
+
+// Returns an slice of valid categories sorted by position, can return ErrEmptySelection
+func (repo sqliteCategoryRepository) GetAll() ([]domain.Category, error) {
+	db := repo.db
+
+	var categories []domain.Category
+	query := `
+	SELECT Category_ID, COALESCE(Parent_ID, 0), Name, Position, Is_Archived, Creation_Date
+	FROM Category
+	ORDER BY Position, Category_ID
+	`
+	rows, err := db.Query(query)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return nil, ErrUnknown
+	}
+
+	for rows.Next() {
+		var category domain.Category
+		var creationDate int64
+
+		err = rows.Scan(&category.CategoryID, &category.ParentID, &category.Name, &category.Position, &category.Archived, &creationDate)
+		if err != nil {
+			logging.LogUnexpectedRepositoryError(err)
+			return nil, ErrUnknown
+		}
+
+		category.CreationDate = time.Unix(creationDate, 0)
+		categories = append(categories, category)
+	}
+
+	if len(categories) == 0 {
+		logging.LogRepositoryError(ErrEmptySelection)
+		return nil, ErrEmptySelection
+	}
+
+	return categories, nil
+}
+
+// Can return ErrNoRowsAffected, ErrRepeatedEntity
+func (repo sqliteCategoryRepository) UpdateName(id uint, newName string) error {
+	db := repo.db
+
+	query := `
+	UPDATE Category
+	SET Name = ?
+	WHERE Category_ID = ?
+	`
+	res, err := db.Exec(query, newName, id)
+	if sqliteErr, ok := err.(sqlite3.Error); ok {
+		if sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			logging.LogRepositoryError(ErrRepeatedEntity)
+			return ErrRepeatedEntity
+		}
+	}
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	amountAffected, err := res.RowsAffected()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	if amountAffected == 0 {
+		logging.LogRepositoryError(ErrNoRowsAffected)
+		return ErrNoRowsAffected
+	}
+
+	return nil
+}
+
+// Moves the category among its siblings shifting the rest, can return ErrNoRowsAffected
+func (repo sqliteCategoryRepository) UpdatePosition(id uint, newPosition uint) error {
+	db := repo.db
+
+	tx, err := db.Begin()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+	defer tx.Rollback()
+
+	var parentID, currentPosition, lastPosition uint
+	query := `
+	SELECT COALESCE(c.Parent_ID, 0), c.Position, (
+		SELECT MAX(s.Position) FROM Category s WHERE COALESCE(s.Parent_ID, 0) = COALESCE(c.Parent_ID, 0)
+	)
+	FROM Category c
+	WHERE c.Category_ID = ?
+	`
+	err = tx.QueryRow(query, id).Scan(&parentID, &currentPosition, &lastPosition)
+	if err == sql.ErrNoRows {
+		logging.LogRepositoryError(ErrNoRowsAffected)
+		return ErrNoRowsAffected
+	}
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	if newPosition > lastPosition {
+		newPosition = lastPosition
+	}
+
+	shift := `
+	UPDATE Category
+	SET Position = Position - 1
+	WHERE COALESCE(Parent_ID, 0) = ? AND Position > ? AND Position <= ?
+	`
+	from, to := currentPosition, newPosition
+	if newPosition < currentPosition {
+		shift = `
+		UPDATE Category
+		SET Position = Position + 1
+		WHERE COALESCE(Parent_ID, 0) = ? AND Position >= ? AND Position < ?
+		`
+		from, to = newPosition, currentPosition
+	}
+	_, err = tx.Exec(shift, parentID, from, to)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	_, err = tx.Exec(`UPDATE Category SET Position = ? WHERE Category_ID = ?`, newPosition, id)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	return nil
+}
+
+// Can return ErrNoRowsAffected
+func (repo sqliteCategoryRepository) UpdateArchived(id uint, archived bool) error {
+	db := repo.db
+
+	query := `
+	UPDATE Category
+	SET Is_Archived = ?
+	WHERE Category_ID = ?
+	`
+	res, err := db.Exec(query, archived, id)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	amountAffected, err := res.RowsAffected()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	if amountAffected == 0 {
+		logging.LogRepositoryError(ErrNoRowsAffected)
+		return ErrNoRowsAffected
+	}
+
+	return nil
+}
+
+func NewSQLiteCategoryRepository(db *sql.DB) domain.CategoryRepository {
+	return sqliteCategoryRepository{db: db}
+}
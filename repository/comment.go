@@ -2,6 +2,7 @@ package repository
 
 import (
 	"database/sql"
+	"time"
 
 	"github.com/AlejandroJorge/forum-rest-api/domain"
 	"github.com/AlejandroJorge/forum-rest-api/logging"
@@ -12,15 +13,42 @@ type sqliteCommentRepository struct {
 	db *sql.DB
 }
 
-// Can return ErrNoMatchingDependency
-func (repo sqliteCommentRepository) AddLike(userId uint, commentId uint) error {
+// A value of 0 removes the vote, keeps the vote counters in sync, can return ErrNoMatchingDependency
+func (repo sqliteCommentRepository) Vote(userId uint, commentId uint, value int) error {
 	db := repo.db
 
+	tx, err := db.Begin()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+	defer tx.Rollback()
+
+	var previous int
 	query := `
-	INSERT INTO Comment_Likings(Liker_ID, Comment_ID)
-	VALUES (?,?)
+	SELECT Value FROM Comment_Votes
+	WHERE Voter_ID = ? AND Comment_ID = ?
 	`
-	_, err := db.Exec(query, userId, commentId)
+	err = tx.QueryRow(query, userId, commentId).Scan(&previous)
+	if err != nil && err != sql.ErrNoRows {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	if value == 0 {
+		query = `
+		DELETE FROM Comment_Votes
+		WHERE Voter_ID = ? AND Comment_ID = ?
+		`
+		_, err = tx.Exec(query, userId, commentId)
+	} else {
+		query = `
+		INSERT INTO Comment_Votes(Voter_ID, Comment_ID, Value)
+		VALUES (?,?,?)
+		ON CONFLICT(Comment_ID, Voter_ID) DO UPDATE SET Value = excluded.Value
+		`
+		_, err = tx.Exec(query, userId, commentId, value)
+	}
 	if sqliteErr, ok := err.(sqlite3.Error); ok {
 		if sqliteErr.ExtendedCode == sqlite3.ErrConstraintForeignKey {
 			logging.LogRepositoryError(ErrNoMatchingDependency)
@@ -32,18 +60,189 @@ func (repo sqliteCommentRepository) AddLike(userId uint, commentId uint) error {
 		return ErrUnknown
 	}
 
+	upvotes, downvotes := voteCountDeltas(previous, value)
+	query = `
+	UPDATE Comment
+	SET Upvotes = Upvotes + ?, Downvotes = Downvotes + ?
+	WHERE Comment_ID = ?
+	`
+	_, err = tx.Exec(query, upvotes, downvotes, commentId)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	return nil
+}
+
+// Returns the viewer fields of the comments for the user by comment ID
+func (repo sqliteCommentRepository) GetViewerStates(viewerID uint, commentIDs []uint) (map[uint]domain.Viewer, error) {
+	db := repo.db
+
+	query := `
+	SELECT c.Comment_ID, COALESCE(v.Value, 0), c.User_ID = ?,
+		EXISTS (SELECT 1 FROM Following f WHERE f.Follower_ID = ? AND f.Followed_ID = c.User_ID)
+	FROM Comment c
+	LEFT JOIN Comment_Votes v ON c.Comment_ID = v.Comment_ID AND v.Voter_ID = ?
+	WHERE c.Comment_ID IN (` + sqlPlaceholders(len(commentIDs)) + `)
+	`
+	args := []any{viewerID, viewerID, viewerID}
+	for _, commentID := range commentIDs {
+		args = append(args, commentID)
+	}
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return nil, ErrUnknown
+	}
+
+	viewers := make(map[uint]domain.Viewer)
+	for rows.Next() {
+		var commentID uint
+		var viewer domain.Viewer
+		err = rows.Scan(&commentID, &viewer.MyVote, &viewer.IsOwner, &viewer.FollowsAuthor)
+		if err != nil {
+			logging.LogUnexpectedRepositoryError(err)
+			return nil, ErrUnknown
+		}
+
+		viewer.LikedByMe = viewer.MyVote > 0
+		viewers[commentID] = viewer
+	}
+
+	return viewers, nil
+}
+
+// Returns an slice of valid comments the user upvoted from newest to oldest, can return ErrEmptySelection
+// Only returns comments on posts the viewer can see, viewerID is 0 for anonymous viewers
+func (repo sqliteCommentRepository) GetLikedBy(viewerID, userID uint) ([]domain.Comment, error) {
+	db := repo.db
+
+	var comments []domain.Comment
+	query := `
+	SELECT ` + commentColumns + `
+	FROM Comment c
+	JOIN Post p ON p.Post_ID = c.Post_ID
+	WHERE c.Deleted_At IS NULL AND p.Deleted_At IS NULL AND c.Comment_ID IN (
+		SELECT Comment_ID FROM Comment_Votes WHERE Voter_ID = ? AND Value > 0
+	) AND ` + visiblePostCondition + `
+	ORDER BY c.Creation_Date DESC, c.Comment_ID DESC
+	`
+	rows, err := db.Query(query, userID, viewerID, viewerID)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return nil, ErrUnknown
+	}
+
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			logging.LogUnexpectedRepositoryError(err)
+			return nil, ErrUnknown
+		}
+
+		comments = append(comments, comment)
+	}
+
+	if len(comments) == 0 {
+		logging.LogRepositoryError(ErrEmptySelection)
+		return nil, ErrEmptySelection
+	}
+
+	return comments, nil
+}
+
+// Stores the rendered HTML and the entities of the comment, replaces its mentions and hashtags, can return ErrNoRowsAffected
+func (repo sqliteCommentRepository) SetRendered(id uint, contentHTML string, entities []domain.ContentEntity) error {
+	db := repo.db
+
+	tx, err := db.Begin()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+	defer tx.Rollback()
+
+	err = setRenderedContent(tx, "Comment", "Comment_ID", id, contentHTML, entities)
+	if err == ErrNoRowsAffected {
+		logging.LogRepositoryError(ErrNoRowsAffected)
+		return ErrNoRowsAffected
+	}
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
 	return nil
 }
 
-// Returns the id of the created comment, can return ErrNoMatchingDependency
-func (repo sqliteCommentRepository) Create(postID, userID uint, content string) (uint, error) {
+// Returns an slice of valid comments mentioning the user from newest to oldest, can return ErrEmptySelection
+// Only returns comments on posts the viewer can see, viewerID is 0 for anonymous viewers
+func (repo sqliteCommentRepository) GetByMention(viewerID, userID uint) ([]domain.Comment, error) {
 	db := repo.db
 
+	var comments []domain.Comment
+	query := `
+	SELECT ` + commentColumns + `
+	FROM Comment c
+	JOIN Post p ON p.Post_ID = c.Post_ID
+	WHERE c.Deleted_At IS NULL AND p.Deleted_At IS NULL AND c.Comment_ID IN (
+		SELECT Comment_ID FROM Comment_Mentions WHERE User_ID = ?
+	) AND ` + visiblePostCondition + `
+	ORDER BY c.Creation_Date DESC, c.Comment_ID DESC
+	`
+	rows, err := db.Query(query, userID, viewerID, viewerID)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return nil, ErrUnknown
+	}
+
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			logging.LogUnexpectedRepositoryError(err)
+			return nil, ErrUnknown
+		}
+
+		comments = append(comments, comment)
+	}
+
+	if len(comments) == 0 {
+		logging.LogRepositoryError(ErrEmptySelection)
+		return nil, ErrEmptySelection
+	}
+
+	return comments, nil
+}
+
+// Returns the id of the created comment, parentID is optional, counts it in its post and parent, can return ErrNoMatchingDependency
+func (repo sqliteCommentRepository) Create(postID, userID, parentID uint, content string) (uint, error) {
+	db := repo.db
+
+	tx, err := db.Begin()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return 0, ErrUnknown
+	}
+	defer tx.Rollback()
+
 	query := `
-	INSERT INTO Comment(Post_ID, User_ID, Content)
-	VALUES (?,?,?)
+	INSERT INTO Comment(Post_ID, User_ID, Parent_ID, Depth, Content, Creation_Date)
+	VALUES (?,?,NULLIF(?, 0),COALESCE((SELECT Depth + 1 FROM Comment WHERE Comment_ID = ?), 0),?,?)
 	`
-	res, err := db.Exec(query, postID, userID, content)
+	res, err := tx.Exec(query, postID, userID, parentID, parentID, content, time.Now().Unix())
 	if sqliteErr, ok := err.(sqlite3.Error); ok {
 		if sqliteErr.ExtendedCode == sqlite3.ErrConstraintForeignKey {
 			logging.LogRepositoryError(ErrNoMatchingDependency)
@@ -61,18 +260,38 @@ func (repo sqliteCommentRepository) Create(postID, userID uint, content string)
 		return 0, ErrUnknown
 	}
 
+	err = adjustCommentCounters(tx, uint(newId), 1)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return 0, ErrUnknown
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return 0, ErrUnknown
+	}
+
 	return uint(newId), nil
 }
 
-// Can return ErrNoRowsAffected
+// Marks the comment as deleted and stops counting it in its post and parent, can return ErrNoRowsAffected
 func (repo sqliteCommentRepository) Delete(id uint) error {
 	db := repo.db
 
+	tx, err := db.Begin()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+	defer tx.Rollback()
+
 	query := `
-	DELETE FROM Comment
-	WHERE Comment_ID = ?
+	UPDATE Comment
+	SET Deleted_At = ?
+	WHERE Comment_ID = ? AND Deleted_At IS NULL
 	`
-	res, err := db.Exec(query, id)
+	res, err := tx.Exec(query, time.Now().Unix(), id)
 	if err != nil {
 		logging.LogUnexpectedRepositoryError(err)
 		return ErrUnknown
@@ -89,34 +308,18 @@ func (repo sqliteCommentRepository) Delete(id uint) error {
 		return ErrNoRowsAffected
 	}
 
-	return nil
-}
-
-// Can return ErrNoRowsAffected
-func (repo sqliteCommentRepository) DeleteLike(userId uint, commentId uint) error {
-	db := repo.db
-
-	query := `
-	DELETE FROM Comment_Likings
-	WHERE Liker_ID = ? AND Comment_ID = ?
-	`
-	res, err := db.Exec(query, userId, commentId)
+	err = adjustCommentCounters(tx, id, -1)
 	if err != nil {
 		logging.LogUnexpectedRepositoryError(err)
 		return ErrUnknown
 	}
 
-	amountAffected, err := res.RowsAffected()
+	err = tx.Commit()
 	if err != nil {
 		logging.LogUnexpectedRepositoryError(err)
 		return ErrUnknown
 	}
 
-	if amountAffected == 0 {
-		logging.LogRepositoryError(ErrNoRowsAffected)
-		return ErrNoRowsAffected
-	}
-
 	return nil
 }
 
@@ -124,16 +327,13 @@ func (repo sqliteCommentRepository) DeleteLike(userId uint, commentId uint) erro
 func (repo sqliteCommentRepository) GetByID(id uint) (domain.Comment, error) {
 	db := repo.db
 
-	var comment domain.Comment
 	query := `
-	SELECT c.Comment_ID, c.Post_ID, c.User_ID, c.Content, COUNT(l.Liker_ID) AS Like_Count
+	SELECT ` + commentColumns + `
 	FROM Comment c
-	LEFT JOIN Comment_Likings l ON c.Comment_ID = l.Comment_ID
-	WHERE c.Comment_ID = ?
-	GROUP BY c.Comment_ID
+	WHERE c.Comment_ID = ? AND c.Deleted_At IS NULL
 	`
 	row := db.QueryRow(query, id)
-	err := row.Scan(&comment.ID, &comment.PostID, &comment.UserID, &comment.Content, &comment.Likes)
+	comment, err := scanComment(row)
 	if err == sql.ErrNoRows {
 		logging.LogRepositoryError(ErrEmptySelection)
 		return domain.Comment{}, ErrEmptySelection
@@ -152,13 +352,13 @@ func (repo sqliteCommentRepository) GetByPost(postID uint) ([]domain.Comment, er
 
 	var comments []domain.Comment
 	query := `
-	SELECT c.Comment_ID, c.Post_ID, c.User_ID, c.Content, COUNT(l.Liker_ID) AS Like_Count
+	SELECT ` + commentColumns + `
 	FROM Comment c
-	LEFT JOIN Comment_Likings l ON c.Comment_ID = l.Comment_ID
-	WHERE c.Comment_ID IN(
-		SELECT Comment_ID FROM Comment WHERE Post_ID = ?
+	WHERE c.Deleted_At IS NULL AND c.Comment_ID IN(
+		SELECT cm.Comment_ID FROM Comment cm, Post p
+		WHERE cm.Post_ID = p.Post_ID AND p.Deleted_At IS NULL AND cm.Post_ID = ?
 	)
-	GROUP BY c.Comment_ID
+	ORDER BY c.Comment_ID
 	`
 	rows, err := db.Query(query, postID)
 	if err != nil {
@@ -167,8 +367,7 @@ func (repo sqliteCommentRepository) GetByPost(postID uint) ([]domain.Comment, er
 	}
 
 	for rows.Next() {
-		var comment domain.Comment
-		err = rows.Scan(&comment.ID, &comment.PostID, &comment.UserID, &comment.Content, &comment.Likes)
+		comment, err := scanComment(rows)
 		if err != nil {
 			logging.LogUnexpectedRepositoryError(err)
 			return nil, ErrUnknown
@@ -186,28 +385,27 @@ func (repo sqliteCommentRepository) GetByPost(postID uint) ([]domain.Comment, er
 }
 
 // Returns an slice of valid comments, can return ErrEmptySelection
-func (repo sqliteCommentRepository) GetByUser(userID uint) ([]domain.Comment, error) {
+// Only returns comments on posts the viewer can see, viewerID is 0 for anonymous viewers
+func (repo sqliteCommentRepository) GetByUser(viewerID, userID uint) ([]domain.Comment, error) {
 	db := repo.db
 
 	var comments []domain.Comment
 	query := `
-	SELECT c.Comment_ID, c.Post_ID, c.User_ID, c.Content, COUNT(l.Liker_ID) AS Like_Count
+	SELECT ` + commentColumns + `
 	FROM Comment c
-	LEFT JOIN Comment_Likings l ON c.Comment_ID = l.Comment_ID
-	WHERE c.Comment_ID IN(
+	JOIN Post p ON p.Post_ID = c.Post_ID
+	WHERE c.Deleted_At IS NULL AND c.Comment_ID IN(
 		SELECT Comment_ID FROM Comment WHERE User_ID = ?
-	)
-	GROUP BY c.Comment_ID
+	) AND ` + visiblePostCondition + `
 	`
-	rows, err := db.Query(query, userID)
+	rows, err := db.Query(query, userID, viewerID, viewerID)
 	if err != nil {
 		logging.LogUnexpectedRepositoryError(err)
 		return nil, ErrUnknown
 	}
 
 	for rows.Next() {
-		var comment domain.Comment
-		err = rows.Scan(&comment.ID, &comment.PostID, &comment.UserID, &comment.Content, &comment.Likes)
+		comment, err := scanComment(rows)
 		if err != nil {
 			logging.LogUnexpectedRepositoryError(err)
 			return nil, ErrUnknown
@@ -224,16 +422,151 @@ func (repo sqliteCommentRepository) GetByUser(userID uint) ([]domain.Comment, er
 	return comments, nil
 }
 
-// Can return ErrNoRowsAffected
+// Returns an slice of valid comments replying directly to the comment, can return ErrEmptySelection
+func (repo sqliteCommentRepository) GetReplies(commentID uint) ([]domain.Comment, error) {
+	db := repo.db
+
+	var comments []domain.Comment
+	query := `
+	SELECT ` + commentColumns + `
+	FROM Comment c
+	WHERE c.Deleted_At IS NULL AND c.Parent_ID = ?
+	ORDER BY c.Comment_ID
+	`
+	rows, err := db.Query(query, commentID)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return nil, ErrUnknown
+	}
+
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			logging.LogUnexpectedRepositoryError(err)
+			return nil, ErrUnknown
+		}
+
+		comments = append(comments, comment)
+	}
+
+	if len(comments) == 0 {
+		logging.LogRepositoryError(ErrEmptySelection)
+		return nil, ErrEmptySelection
+	}
+
+	return comments, nil
+}
+
+// Keeps the previous content as a revision, can return ErrNoRowsAffected
 func (repo sqliteCommentRepository) UpdateContent(id uint, newContent string) error {
 	db := repo.db
 
+	tx, err := db.Begin()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+	defer tx.Rollback()
+
+	now := time.Now().Unix()
+	query := `
+	INSERT INTO Comment_Revision(Comment_ID, Content, Revision_Date)
+	SELECT Comment_ID, Content, ?
+	FROM Comment
+	WHERE Comment_ID = ? AND Deleted_At IS NULL
+	`
+	_, err = tx.Exec(query, now, id)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	query = `
+	UPDATE Comment
+	SET Content = ?, Edit_Date = ?
+	WHERE Comment_ID = ? AND Deleted_At IS NULL
+	`
+	res, err := tx.Exec(query, newContent, now, id)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	amountAffected, err := res.RowsAffected()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	if amountAffected == 0 {
+		logging.LogRepositoryError(ErrNoRowsAffected)
+		return ErrNoRowsAffected
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	return nil
+}
+
+// Returns an slice of revisions from oldest to newest, can return ErrEmptySelection
+func (repo sqliteCommentRepository) GetRevisions(commentID uint) ([]domain.CommentRevision, error) {
+	db := repo.db
+
+	var revisions []domain.CommentRevision
+	query := `
+	SELECT r.Revision_ID, r.Comment_ID, r.Content, r.Revision_Date
+	FROM Comment_Revision r, Comment c
+	WHERE r.Comment_ID = c.Comment_ID AND c.Deleted_At IS NULL AND r.Comment_ID = ?
+	ORDER BY r.Revision_ID
+	`
+	rows, err := db.Query(query, commentID)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return nil, ErrUnknown
+	}
+
+	for rows.Next() {
+		var revision domain.CommentRevision
+		var revisionDate int64
+		err = rows.Scan(&revision.ID, &revision.CommentID, &revision.Content, &revisionDate)
+		if err != nil {
+			logging.LogUnexpectedRepositoryError(err)
+			return nil, ErrUnknown
+		}
+
+		revision.RevisionDate = time.Unix(revisionDate, 0)
+		revisions = append(revisions, revision)
+	}
+
+	if len(revisions) == 0 {
+		logging.LogRepositoryError(ErrEmptySelection)
+		return nil, ErrEmptySelection
+	}
+
+	return revisions, nil
+}
+
+// Counts the comment again in its post and parent and clears the moderation action that removed it, can return ErrNoRowsAffected
+func (repo sqliteCommentRepository) Restore(id uint) error {
+	db := repo.db
+
+	tx, err := db.Begin()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+	defer tx.Rollback()
+
 	query := `
 	UPDATE Comment
-	SET Content = ?
-	WHERE Comment_ID = ?
+	SET Deleted_At = NULL, Moderation_Action = NULL
+	WHERE Comment_ID = ? AND Deleted_At IS NOT NULL
 	`
-	res, err := db.Exec(query, newContent, id)
+	res, err := tx.Exec(query, id)
 	if err != nil {
 		logging.LogUnexpectedRepositoryError(err)
 		return ErrUnknown
@@ -250,9 +583,207 @@ func (repo sqliteCommentRepository) UpdateContent(id uint, newContent string) er
 		return ErrNoRowsAffected
 	}
 
+	err = adjustCommentCounters(tx, id, 1)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	return nil
+}
+
+// Returns a valid soft deleted comment along with the moderation action that removed it if any, can return ErrEmptySelection
+func (repo sqliteCommentRepository) GetDeletedByID(id uint) (domain.Comment, error) {
+	db := repo.db
+
+	query := `
+	SELECT ` + commentColumns + `, COALESCE(c.Moderation_Action, '')
+	FROM Comment c
+	WHERE c.Comment_ID = ? AND c.Deleted_At IS NOT NULL
+	`
+	row := db.QueryRow(query, id)
+	var moderation domain.ModerationAction
+	comment, err := scanComment(row, &moderation)
+	if err == sql.ErrNoRows {
+		logging.LogRepositoryError(ErrEmptySelection)
+		return domain.Comment{}, ErrEmptySelection
+	}
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return domain.Comment{}, ErrUnknown
+	}
+
+	comment.Moderation = moderation
+
+	return comment, nil
+}
+
+// Permanently removes comments deleted before moment along with their votes, comments hidden by moderators are kept, returns the amount of purged comments
+func (repo sqliteCommentRepository) PurgeDeletedBefore(moment time.Time) (uint, error) {
+	db := repo.db
+
+	tx, err := db.Begin()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return 0, ErrUnknown
+	}
+	defer tx.Rollback()
+
+	momentInteger := moment.Unix()
+	purgedComments := `
+	SELECT Comment_ID FROM Comment WHERE Deleted_At IS NOT NULL AND Deleted_At < ? AND ` + notHiddenCondition + `
+	`
+	queries := []string{
+		`DELETE FROM Comment_Votes WHERE Comment_ID IN (` + purgedComments + `)`,
+		`DELETE FROM Comment_Revision WHERE Comment_ID IN (` + purgedComments + `)`,
+		`DELETE FROM Comment_Mentions WHERE Comment_ID IN (` + purgedComments + `)`,
+		`DELETE FROM Comment_Hashtags WHERE Comment_ID IN (` + purgedComments + `)`,
+	}
+	for _, query := range queries {
+		_, err = tx.Exec(query, momentInteger)
+		if err != nil {
+			logging.LogUnexpectedRepositoryError(err)
+			return 0, ErrUnknown
+		}
+	}
+
+	query := `
+	DELETE FROM Comment WHERE Deleted_At IS NOT NULL AND Deleted_At < ? AND ` + notHiddenCondition + `
+	`
+	res, err := tx.Exec(query, momentInteger)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return 0, ErrUnknown
+	}
+
+	amountAffected, err := res.RowsAffected()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return 0, ErrUnknown
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return 0, ErrUnknown
+	}
+
+	return uint(amountAffected), nil
+}
+
+// Marks the comment as deleted by the action even if its author already deleted it, stops counting it if it was valid, can return ErrNoRowsAffected
+func (repo sqliteCommentRepository) Moderate(id uint, action domain.ModerationAction) error {
+	db := repo.db
+
+	tx, err := db.Begin()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+	defer tx.Rollback()
+
+	var valid bool
+	err = tx.QueryRow(`SELECT Deleted_At IS NULL FROM Comment WHERE Comment_ID = ?`, id).Scan(&valid)
+	if err == sql.ErrNoRows {
+		logging.LogRepositoryError(ErrNoRowsAffected)
+		return ErrNoRowsAffected
+	}
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	query := `
+	UPDATE Comment
+	SET Deleted_At = COALESCE(Deleted_At, ?), Moderation_Action = ?
+	WHERE Comment_ID = ?
+	`
+	_, err = tx.Exec(query, time.Now().Unix(), action, id)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	if valid {
+		err = adjustCommentCounters(tx, id, -1)
+		if err != nil {
+			logging.LogUnexpectedRepositoryError(err)
+			return ErrUnknown
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
 	return nil
 }
 
+// Adds delta to the comment count of the post of the comment and the reply count of its parent, refreshes the last activity of the post
+func adjustCommentCounters(tx *sql.Tx, commentID uint, delta int) error {
+	query := `
+	UPDATE Post
+	SET Comment_Count = Comment_Count + ?, Last_Activity = ` + postLastActivity + `
+	WHERE Post_ID = (SELECT Post_ID FROM Comment WHERE Comment_ID = ?)
+	`
+	_, err := tx.Exec(query, delta, commentID)
+	if err != nil {
+		return err
+	}
+
+	query = `
+	UPDATE Comment
+	SET Reply_Count = Reply_Count + ?
+	WHERE Comment_ID = (SELECT Parent_ID FROM Comment WHERE Comment_ID = ?)
+	`
+	_, err = tx.Exec(query, delta, commentID)
+	return err
+}
+
+// Selects every field of a comment c in the order scanComment reads them
+const commentColumns = `c.Comment_ID, c.Post_ID, c.User_ID, COALESCE(c.Parent_ID, 0), c.Depth, c.Content, c.Content_HTML, c.Upvotes - c.Downvotes, c.Upvotes, c.Downvotes,
+		c.Reply_Count, c.Creation_Date, COALESCE(c.Edit_Date, 0), c.Entities`
+
+// Scans a row selected with commentColumns, extra receives any columns selected after them
+func scanComment(row rowScanner, extra ...interface{}) (domain.Comment, error) {
+	var comment domain.Comment
+	var creationDate, editDate int64
+	var entities string
+	dest := []interface{}{&comment.ID, &comment.PostID, &comment.UserID, &comment.ParentID, &comment.Depth, &comment.Content, &comment.ContentHTML, &comment.Score, &comment.Upvotes, &comment.Downvotes, &comment.ReplyCount, &creationDate, &editDate, &entities}
+	err := row.Scan(append(dest, extra...)...)
+	if err != nil {
+		return domain.Comment{}, err
+	}
+
+	comment.CreatedAt = time.Unix(creationDate, 0)
+	comment.Entities = decodeContentEntities(entities)
+	comment.EditedAt = commentEditDate(editDate)
+	return comment, nil
+}
+
+// Edit_Date is selected as 0 for comments never edited, which are left without an edit date
+func commentEditDate(editDate int64) *time.Time {
+	if editDate == 0 {
+		return nil
+	}
+
+	editedAt := time.Unix(editDate, 0)
+	return &editedAt
+}
+
+// The creation of the latest valid comment of the post, or of the post itself without comments
+const postLastActivity = `COALESCE(
+		(SELECT MAX(c.Creation_Date) FROM Comment c WHERE c.Post_ID = Post.Post_ID AND c.Deleted_At IS NULL), Post.Creation_Date
+	)`
+
 func NewSQLiteCommentRepository(db *sql.DB) domain.CommentRepository {
 	return sqliteCommentRepository{db: db}
 }
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"strings"
+
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/logging"
+)
+
+// Entities are kept as JSON next to the content, undecodable ones are logged and left out
+func decodeContentEntities(raw string) []domain.ContentEntity {
+	entities := []domain.ContentEntity{}
+	err := json.Unmarshal([]byte(raw), &entities)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return []domain.ContentEntity{}
+	}
+
+	return entities
+}
+
+// Stores the rendered HTML and entities of a post or comment and replaces its mention and hashtag relations, returns ErrNoRowsAffected when it doesn't exist
+func setRenderedContent(tx *sql.Tx, table, idColumn string, id uint, contentHTML string, entities []domain.ContentEntity) error {
+	encoded, err := json.Marshal(entities)
+	if err != nil {
+		return err
+	}
+	if entities == nil {
+		encoded = []byte("[]")
+	}
+
+	res, err := tx.Exec(`UPDATE `+table+` SET Content_HTML = ?, Entities = ? WHERE `+idColumn+` = ?`, contentHTML, string(encoded), id)
+	if err != nil {
+		return err
+	}
+
+	amountAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if amountAffected == 0 {
+		return ErrNoRowsAffected
+	}
+
+	for _, relation := range []string{table + "_Mentions", table + "_Hashtags"} {
+		_, err = tx.Exec(`DELETE FROM `+relation+` WHERE `+idColumn+` = ?`, id)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, entity := range entities {
+		var err error
+		switch entity.Type {
+		case domain.ContentEntityMention:
+			_, err = tx.Exec(`INSERT OR IGNORE INTO `+table+`_Mentions(`+idColumn+`, User_ID) VALUES (?,?)`, id, entity.UserID)
+		case domain.ContentEntityHashtag:
+			_, err = tx.Exec(`INSERT OR IGNORE INTO `+table+`_Hashtags(`+idColumn+`, Hashtag) VALUES (?,?)`, id, strings.ToLower(strings.TrimPrefix(entity.Text, "#")))
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Excludes posts and comments hidden by moderators, they're kept for review until restored
+const notHiddenCondition = `(Moderation_Action IS NULL OR Moderation_Action <> '` + string(domain.ModerationHide) + `')`
@@ -0,0 +1,337 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/logging"
+	"github.com/mattn/go-sqlite3"
+)
+
+type sqliteConversationRepository struct {
+	db *sql.DB
+}
+
+// Returns the id of the conversation, a conversation between two profiles is reused instead of created again, can return ErrNoMatchingDependency
+func (repo sqliteConversationRepository) Create(creatorID uint, memberIDs []uint) (uint, error) {
+	db := repo.db
+
+	tx, err := db.Begin()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return 0, ErrUnknown
+	}
+	defer tx.Rollback()
+
+	isGroup := len(memberIDs) > 1
+	if !isGroup {
+		query := `
+		SELECT c.Conversation_ID
+		FROM Conversation c
+		WHERE c.Is_Group = 0
+			AND EXISTS (SELECT 1 FROM Conversation_Members WHERE Conversation_ID = c.Conversation_ID AND User_ID = ?)
+			AND EXISTS (SELECT 1 FROM Conversation_Members WHERE Conversation_ID = c.Conversation_ID AND User_ID = ?)
+		`
+		var existingID uint
+		err = tx.QueryRow(query, creatorID, memberIDs[0]).Scan(&existingID)
+		if err == nil {
+			return existingID, nil
+		}
+		if err != sql.ErrNoRows {
+			logging.LogUnexpectedRepositoryError(err)
+			return 0, ErrUnknown
+		}
+	}
+
+	now := time.Now().Unix()
+	res, err := tx.Exec(`INSERT INTO Conversation(Creator_ID, Is_Group, Creation_Date, Last_Message_At) VALUES (?,?,?,?)`, creatorID, isGroup, now, now)
+	if sqliteErr, ok := err.(sqlite3.Error); ok {
+		if sqliteErr.ExtendedCode == sqlite3.ErrConstraintForeignKey {
+			logging.LogRepositoryError(ErrNoMatchingDependency)
+			return 0, ErrNoMatchingDependency
+		}
+	}
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return 0, ErrUnknown
+	}
+
+	newId, err := res.LastInsertId()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return 0, ErrUnknown
+	}
+
+	for _, userID := range append([]uint{creatorID}, memberIDs...) {
+		_, err = tx.Exec(`INSERT OR IGNORE INTO Conversation_Members(Conversation_ID, User_ID) VALUES (?,?)`, newId, userID)
+		if sqliteErr, ok := err.(sqlite3.Error); ok {
+			if sqliteErr.ExtendedCode == sqlite3.ErrConstraintForeignKey {
+				logging.LogRepositoryError(ErrNoMatchingDependency)
+				return 0, ErrNoMatchingDependency
+			}
+		}
+		if err != nil {
+			logging.LogUnexpectedRepositoryError(err)
+			return 0, ErrUnknown
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return 0, ErrUnknown
+	}
+
+	return uint(newId), nil
+}
+
+// Returns a valid conversation with its members, Unread is left empty, can return ErrEmptySelection
+func (repo sqliteConversationRepository) GetByID(id uint) (domain.Conversation, error) {
+	db := repo.db
+
+	var conversation domain.Conversation
+	var creationDate, lastMessageAt int64
+	query := `
+	SELECT Conversation_ID, Creator_ID, Is_Group, Creation_Date, Last_Message_At
+	FROM Conversation
+	WHERE Conversation_ID = ?
+	`
+	err := db.QueryRow(query, id).Scan(&conversation.ID, &conversation.CreatorID, &conversation.IsGroup, &creationDate, &lastMessageAt)
+	if err == sql.ErrNoRows {
+		logging.LogRepositoryError(ErrEmptySelection)
+		return domain.Conversation{}, ErrEmptySelection
+	}
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return domain.Conversation{}, ErrUnknown
+	}
+
+	conversation.CreatedAt = time.Unix(creationDate, 0)
+	conversation.LastMessageAt = time.Unix(lastMessageAt, 0)
+	conversation.Members, err = repo.getMembers(id)
+	if err != nil {
+		return domain.Conversation{}, err
+	}
+
+	return conversation, nil
+}
+
+// Returns an slice of the user's conversations by latest message with their unread counts, can return ErrEmptySelection
+func (repo sqliteConversationRepository) GetByUser(userID uint, limit, offset uint) ([]domain.Conversation, error) {
+	db := repo.db
+
+	var conversations []domain.Conversation
+	query := `
+	SELECT c.Conversation_ID, c.Creator_ID, c.Is_Group, c.Creation_Date, c.Last_Message_At, (
+		SELECT COUNT(*) FROM Message m
+		WHERE m.Conversation_ID = c.Conversation_ID AND m.Message_ID > cm.Last_Read_Message_ID AND m.Sender_ID != cm.User_ID
+	)
+	FROM Conversation c
+	JOIN Conversation_Members cm ON cm.Conversation_ID = c.Conversation_ID
+	WHERE cm.User_ID = ?
+	ORDER BY c.Last_Message_At DESC, c.Conversation_ID DESC
+	LIMIT ? OFFSET ?
+	`
+	rows, err := db.Query(query, userID, limit, offset)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return nil, ErrUnknown
+	}
+
+	for rows.Next() {
+		var conversation domain.Conversation
+		var creationDate, lastMessageAt int64
+		err = rows.Scan(&conversation.ID, &conversation.CreatorID, &conversation.IsGroup, &creationDate, &lastMessageAt, &conversation.Unread)
+		if err != nil {
+			rows.Close()
+			logging.LogUnexpectedRepositoryError(err)
+			return nil, ErrUnknown
+		}
+
+		conversation.CreatedAt = time.Unix(creationDate, 0)
+		conversation.LastMessageAt = time.Unix(lastMessageAt, 0)
+		conversations = append(conversations, conversation)
+	}
+	rows.Close()
+
+	if len(conversations) == 0 {
+		logging.LogRepositoryError(ErrEmptySelection)
+		return nil, ErrEmptySelection
+	}
+
+	for i := range conversations {
+		conversations[i].Members, err = repo.getMembers(conversations[i].ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return conversations, nil
+}
+
+func (repo sqliteConversationRepository) getMembers(conversationID uint) ([]domain.ConversationMember, error) {
+	db := repo.db
+
+	var members []domain.ConversationMember
+	query := `
+	SELECT User_ID, Last_Read_Message_ID, Last_Read_At
+	FROM Conversation_Members
+	WHERE Conversation_ID = ?
+	ORDER BY User_ID
+	`
+	rows, err := db.Query(query, conversationID)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return nil, ErrUnknown
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var member domain.ConversationMember
+		var lastReadAt int64
+		err = rows.Scan(&member.UserID, &member.LastReadMessageID, &lastReadAt)
+		if err != nil {
+			logging.LogUnexpectedRepositoryError(err)
+			return nil, ErrUnknown
+		}
+
+		if lastReadAt != 0 {
+			member.LastReadAt = time.Unix(lastReadAt, 0)
+		}
+		members = append(members, member)
+	}
+
+	return members, nil
+}
+
+// Returns the id of the created message, the sender reads it, can return ErrNoMatchingDependency
+func (repo sqliteConversationRepository) CreateMessage(conversationID, senderID uint, content string) (uint, error) {
+	db := repo.db
+
+	tx, err := db.Begin()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return 0, ErrUnknown
+	}
+	defer tx.Rollback()
+
+	now := time.Now().Unix()
+	res, err := tx.Exec(`INSERT INTO Message(Conversation_ID, Sender_ID, Content, Creation_Date) VALUES (?,?,?,?)`, conversationID, senderID, content, now)
+	if sqliteErr, ok := err.(sqlite3.Error); ok {
+		if sqliteErr.ExtendedCode == sqlite3.ErrConstraintForeignKey {
+			logging.LogRepositoryError(ErrNoMatchingDependency)
+			return 0, ErrNoMatchingDependency
+		}
+	}
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return 0, ErrUnknown
+	}
+
+	newId, err := res.LastInsertId()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return 0, ErrUnknown
+	}
+
+	queries := []struct {
+		query string
+		args  []interface{}
+	}{
+		{`UPDATE Conversation SET Last_Message_At = ? WHERE Conversation_ID = ?`, []interface{}{now, conversationID}},
+		{`UPDATE Conversation_Members SET Last_Read_Message_ID = ?, Last_Read_At = ? WHERE Conversation_ID = ? AND User_ID = ?`, []interface{}{newId, now, conversationID, senderID}},
+	}
+	for _, q := range queries {
+		_, err = tx.Exec(q.query, q.args...)
+		if err != nil {
+			logging.LogUnexpectedRepositoryError(err)
+			return 0, ErrUnknown
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return 0, ErrUnknown
+	}
+
+	return uint(newId), nil
+}
+
+// Returns an slice of messages from newest to oldest, can return ErrEmptySelection
+func (repo sqliteConversationRepository) GetMessages(conversationID uint, limit, offset uint) ([]domain.Message, error) {
+	db := repo.db
+
+	var messages []domain.Message
+	query := `
+	SELECT Message_ID, Conversation_ID, Sender_ID, Content, Creation_Date
+	FROM Message
+	WHERE Conversation_ID = ?
+	ORDER BY Message_ID DESC
+	LIMIT ? OFFSET ?
+	`
+	rows, err := db.Query(query, conversationID, limit, offset)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return nil, ErrUnknown
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var message domain.Message
+		var creationDate int64
+		err = rows.Scan(&message.ID, &message.ConversationID, &message.SenderID, &message.Content, &creationDate)
+		if err != nil {
+			logging.LogUnexpectedRepositoryError(err)
+			return nil, ErrUnknown
+		}
+
+		message.CreatedAt = time.Unix(creationDate, 0)
+		messages = append(messages, message)
+	}
+
+	if len(messages) == 0 {
+		logging.LogRepositoryError(ErrEmptySelection)
+		return nil, ErrEmptySelection
+	}
+
+	return messages, nil
+}
+
+// Marks every message up to messageID as read, 0 reads up to the latest one, can return ErrNoRowsAffected
+func (repo sqliteConversationRepository) MarkRead(conversationID, userID, messageID uint) error {
+	db := repo.db
+
+	query := `
+	UPDATE Conversation_Members
+	SET Last_Read_Message_ID = MAX(Last_Read_Message_ID, r.Message_ID), Last_Read_At = ?
+	FROM (
+		SELECT MAX(Message_ID) AS Message_ID FROM Message
+		WHERE Conversation_ID = ? AND (? = 0 OR Message_ID = ?)
+	) AS r
+	WHERE Conversation_ID = ? AND User_ID = ? AND r.Message_ID IS NOT NULL
+	`
+	res, err := db.Exec(query, time.Now().Unix(), conversationID, messageID, messageID, conversationID, userID)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	amountAffected, err := res.RowsAffected()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	if amountAffected == 0 {
+		logging.LogRepositoryError(ErrNoRowsAffected)
+		return ErrNoRowsAffected
+	}
+
+	return nil
+}
+
+func NewSQLiteConversationRepository(db *sql.DB) domain.ConversationRepository {
+	return sqliteConversationRepository{db: db}
+}
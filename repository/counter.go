@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/logging"
+)
+
+type sqliteCounterRepository struct {
+	db *sql.DB
+}
+
+// Recomputes the vote, comment, reply and follow counters and the last activity of posts and repairs the ones that drifted, returns how many rows were repaired
+func (repo sqliteCounterRepository) Reconcile() (domain.CounterDrift, error) {
+	db := repo.db
+
+	tx, err := db.Begin()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return domain.CounterDrift{}, ErrUnknown
+	}
+	defer tx.Rollback()
+
+	postsQuery := `
+	WITH Counted AS (
+		SELECT p.Post_ID,
+			(SELECT COUNT(*) FROM Post_Votes v WHERE v.Post_ID = p.Post_ID AND v.Value > 0) AS Upvotes,
+			(SELECT COUNT(*) FROM Post_Votes v WHERE v.Post_ID = p.Post_ID AND v.Value < 0) AS Downvotes,
+			(SELECT COUNT(*) FROM Comment c WHERE c.Post_ID = p.Post_ID AND c.Deleted_At IS NULL) AS Comment_Count,
+			CAST(COALESCE(
+				(SELECT MAX(c.Creation_Date) FROM Comment c WHERE c.Post_ID = p.Post_ID AND c.Deleted_At IS NULL), p.Creation_Date
+			) AS INTEGER) AS Last_Activity
+		FROM Post p
+	)
+	UPDATE Post
+	SET Upvotes = Counted.Upvotes, Downvotes = Counted.Downvotes, Comment_Count = Counted.Comment_Count, Last_Activity = Counted.Last_Activity
+	FROM Counted
+	WHERE Post.Post_ID = Counted.Post_ID AND (
+		Post.Upvotes != Counted.Upvotes OR Post.Downvotes != Counted.Downvotes OR Post.Comment_Count != Counted.Comment_Count
+		OR Post.Last_Activity != Counted.Last_Activity
+	)
+	`
+	commentsQuery := `
+	WITH Counted AS (
+		SELECT c.Comment_ID,
+			(SELECT COUNT(*) FROM Comment_Votes v WHERE v.Comment_ID = c.Comment_ID AND v.Value > 0) AS Upvotes,
+			(SELECT COUNT(*) FROM Comment_Votes v WHERE v.Comment_ID = c.Comment_ID AND v.Value < 0) AS Downvotes,
+			(SELECT COUNT(*) FROM Comment r WHERE r.Parent_ID = c.Comment_ID AND r.Deleted_At IS NULL) AS Reply_Count
+		FROM Comment c
+	)
+	UPDATE Comment
+	SET Upvotes = Counted.Upvotes, Downvotes = Counted.Downvotes, Reply_Count = Counted.Reply_Count
+	FROM Counted
+	WHERE Comment.Comment_ID = Counted.Comment_ID AND (
+		Comment.Upvotes != Counted.Upvotes OR Comment.Downvotes != Counted.Downvotes OR Comment.Reply_Count != Counted.Reply_Count
+	)
+	`
+	profilesQuery := `
+	WITH Counted AS (
+		SELECT p.User_ID,
+			(SELECT COUNT(*) FROM Following f WHERE f.Followed_ID = p.User_ID) AS Follower_Count,
+			(SELECT COUNT(*) FROM Following f WHERE f.Follower_ID = p.User_ID) AS Follow_Count
+		FROM Profile p
+	)
+	UPDATE Profile
+	SET Follower_Count = Counted.Follower_Count, Follow_Count = Counted.Follow_Count
+	FROM Counted
+	WHERE Profile.User_ID = Counted.User_ID AND (
+		Profile.Follower_Count != Counted.Follower_Count OR Profile.Follow_Count != Counted.Follow_Count
+	)
+	`
+
+	var drift domain.CounterDrift
+	repairs := []struct {
+		query    string
+		repaired *uint
+	}{
+		{postsQuery, &drift.Posts},
+		{commentsQuery, &drift.Comments},
+		{profilesQuery, &drift.Profiles},
+	}
+	for _, repair := range repairs {
+		res, err := tx.Exec(repair.query)
+		if err != nil {
+			logging.LogUnexpectedRepositoryError(err)
+			return domain.CounterDrift{}, ErrUnknown
+		}
+
+		amountAffected, err := res.RowsAffected()
+		if err != nil {
+			logging.LogUnexpectedRepositoryError(err)
+			return domain.CounterDrift{}, ErrUnknown
+		}
+		*repair.repaired = uint(amountAffected)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return domain.CounterDrift{}, ErrUnknown
+	}
+
+	return drift, nil
+}
+
+func NewSQLiteCounterRepository(db *sql.DB) domain.CounterRepository {
+	return sqliteCounterRepository{db: db}
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/logging"
+	"github.com/mattn/go-sqlite3"
+)
+
+type sqliteMediaRepository struct {
+	db *sql.DB
+}
+
+// Returns the id of the created media, can return ErrRepeatedEntity, ErrNoMatchingDependency
+func (repo sqliteMediaRepository) Create(ownerID uint, key, thumbnailKey, contentType string, size, width, height uint) (uint, error) {
+	db := repo.db
+
+	query := `
+	INSERT INTO Media(Owner_ID, Blob_Key, Thumbnail_Key, Content_Type, Size, Width, Height, Creation_Date)
+	VALUES (?,?,?,?,?,?,?,?)
+	`
+	res, err := db.Exec(query, ownerID, key, thumbnailKey, contentType, size, width, height, time.Now().Unix())
+	if sqliteErr, ok := err.(sqlite3.Error); ok {
+		if sqliteErr.ExtendedCode == sqlite3.ErrConstraintForeignKey {
+			logging.LogRepositoryError(ErrNoMatchingDependency)
+			return 0, ErrNoMatchingDependency
+		}
+		if sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			logging.LogRepositoryError(ErrRepeatedEntity)
+			return 0, ErrRepeatedEntity
+		}
+	}
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return 0, ErrUnknown
+	}
+
+	newId, err := res.LastInsertId()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return 0, ErrUnknown
+	}
+
+	return uint(newId), nil
+}
+
+// Returns a valid media and can return ErrEmptySelection
+func (repo sqliteMediaRepository) GetByID(id uint) (domain.Media, error) {
+	db := repo.db
+
+	query := `
+	SELECT Media_ID, Owner_ID, Blob_Key, Thumbnail_Key, Content_Type, Size, Width, Height, Creation_Date
+	FROM Media
+	WHERE Media_ID = ?
+	`
+	return scanMedia(db.QueryRow(query, id))
+}
+
+// Returns the media of the owner stored under the key or with it as thumbnail, can return ErrEmptySelection
+func (repo sqliteMediaRepository) GetByKey(ownerID uint, key string) (domain.Media, error) {
+	db := repo.db
+
+	query := `
+	SELECT Media_ID, Owner_ID, Blob_Key, Thumbnail_Key, Content_Type, Size, Width, Height, Creation_Date
+	FROM Media
+	WHERE Owner_ID = ? AND (Blob_Key = ? OR Thumbnail_Key = ?)
+	ORDER BY Media_ID
+	LIMIT 1
+	`
+	return scanMedia(db.QueryRow(query, ownerID, key, key))
+}
+
+func scanMedia(row *sql.Row) (domain.Media, error) {
+	var media domain.Media
+	var key, thumbnailKey string
+	var creationDate int64
+	err := row.Scan(&media.ID, &media.OwnerID, &key, &thumbnailKey, &media.ContentType, &media.Size, &media.Width, &media.Height, &creationDate)
+	if err == sql.ErrNoRows {
+		logging.LogRepositoryError(ErrEmptySelection)
+		return domain.Media{}, ErrEmptySelection
+	}
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return domain.Media{}, ErrUnknown
+	}
+
+	media.URL = domain.MediaURL(key)
+	media.ThumbnailURL = domain.MediaURL(thumbnailKey)
+	media.CreatedAt = time.Unix(creationDate, 0)
+	return media, nil
+}
+
+func NewSQLiteMediaRepository(db *sql.DB) domain.MediaRepository {
+	return sqliteMediaRepository{db: db}
+}
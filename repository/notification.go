@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/logging"
+	"github.com/mattn/go-sqlite3"
+)
+
+type sqliteNotificationRepository struct {
+	db *sql.DB
+}
+
+// Returns the id of the created notification, can return ErrRepeatedEntity, ErrNoMatchingDependency
+func (repo sqliteNotificationRepository) Create(notification domain.Notification) (uint, error) {
+	db := repo.db
+
+	query := `
+	INSERT INTO Notification(User_ID, Actor_ID, Type, Post_ID, Comment_ID, Creation_Date)
+	VALUES (?,?,?,?,?,?)
+	`
+	res, err := db.Exec(query, notification.UserID, notification.ActorID, notification.Type, notification.PostID, notification.CommentID, time.Now().Unix())
+	if sqliteErr, ok := err.(sqlite3.Error); ok {
+		if sqliteErr.ExtendedCode == sqlite3.ErrConstraintForeignKey {
+			logging.LogRepositoryError(ErrNoMatchingDependency)
+			return 0, ErrNoMatchingDependency
+		}
+		if sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			logging.LogRepositoryError(ErrRepeatedEntity)
+			return 0, ErrRepeatedEntity
+		}
+	}
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return 0, ErrUnknown
+	}
+
+	newId, err := res.LastInsertId()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return 0, ErrUnknown
+	}
+
+	return uint(newId), nil
+}
+
+// Returns an slice of notifications from newest to oldest, can return ErrEmptySelection
+func (repo sqliteNotificationRepository) GetByUser(userID uint, limit, offset uint) ([]domain.Notification, error) {
+	db := repo.db
+
+	var notifications []domain.Notification
+	query := `
+	SELECT Notification_ID, User_ID, Actor_ID, Type, Post_ID, Comment_ID, Is_Read, Creation_Date
+	FROM Notification
+	WHERE User_ID = ?
+	ORDER BY Creation_Date DESC, Notification_ID DESC
+	LIMIT ? OFFSET ?
+	`
+	rows, err := db.Query(query, userID, limit, offset)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return nil, ErrUnknown
+	}
+
+	for rows.Next() {
+		var notification domain.Notification
+		var creationDate int64
+		err = rows.Scan(&notification.ID, &notification.UserID, &notification.ActorID, &notification.Type,
+			&notification.PostID, &notification.CommentID, &notification.Read, &creationDate)
+		if err != nil {
+			logging.LogUnexpectedRepositoryError(err)
+			return nil, ErrUnknown
+		}
+
+		notification.CreatedAt = time.Unix(creationDate, 0)
+		notifications = append(notifications, notification)
+	}
+
+	if len(notifications) == 0 {
+		logging.LogRepositoryError(ErrEmptySelection)
+		return nil, ErrEmptySelection
+	}
+
+	return notifications, nil
+}
+
+// Returns how many notifications of the user are unread
+func (repo sqliteNotificationRepository) CountUnread(userID uint) (uint, error) {
+	db := repo.db
+
+	var unread uint
+	err := db.QueryRow(`SELECT COUNT(*) FROM Notification WHERE User_ID = ? AND Is_Read = 0`, userID).Scan(&unread)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return 0, ErrUnknown
+	}
+
+	return unread, nil
+}
+
+// Can return ErrNoRowsAffected
+func (repo sqliteNotificationRepository) MarkRead(userID, id uint) error {
+	db := repo.db
+
+	query := `
+	UPDATE Notification
+	SET Is_Read = 1
+	WHERE Notification_ID = ? AND User_ID = ?
+	`
+	res, err := db.Exec(query, id, userID)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	amountAffected, err := res.RowsAffected()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	if amountAffected == 0 {
+		logging.LogRepositoryError(ErrNoRowsAffected)
+		return ErrNoRowsAffected
+	}
+
+	return nil
+}
+
+// Returns how many notifications were marked as read
+func (repo sqliteNotificationRepository) MarkAllRead(userID uint) (uint, error) {
+	db := repo.db
+
+	res, err := db.Exec(`UPDATE Notification SET Is_Read = 1 WHERE User_ID = ? AND Is_Read = 0`, userID)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return 0, ErrUnknown
+	}
+
+	amountAffected, err := res.RowsAffected()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return 0, ErrUnknown
+	}
+
+	return uint(amountAffected), nil
+}
+
+// Returns the defaults when the user never changed them
+func (repo sqliteNotificationRepository) GetPreferences(userID uint) (domain.NotificationPreferences, error) {
+	db := repo.db
+
+	preferences := domain.NotificationPreferences{Reply: true, Like: true, Follow: true, Mention: true}
+	query := `
+	SELECT Reply_Enabled, Like_Enabled, Follow_Enabled, Mention_Enabled
+	FROM Notification_Preferences
+	WHERE User_ID = ?
+	`
+	err := db.QueryRow(query, userID).Scan(&preferences.Reply, &preferences.Like, &preferences.Follow, &preferences.Mention)
+	if err != nil && err != sql.ErrNoRows {
+		logging.LogUnexpectedRepositoryError(err)
+		return domain.NotificationPreferences{}, ErrUnknown
+	}
+
+	return preferences, nil
+}
+
+// Can return ErrNoMatchingDependency
+func (repo sqliteNotificationRepository) UpdatePreferences(userID uint, preferences domain.NotificationPreferences) error {
+	db := repo.db
+
+	query := `
+	INSERT OR REPLACE INTO Notification_Preferences(User_ID, Reply_Enabled, Like_Enabled, Follow_Enabled, Mention_Enabled)
+	VALUES (?,?,?,?,?)
+	`
+	_, err := db.Exec(query, userID, preferences.Reply, preferences.Like, preferences.Follow, preferences.Mention)
+	if sqliteErr, ok := err.(sqlite3.Error); ok {
+		if sqliteErr.ExtendedCode == sqlite3.ErrConstraintForeignKey {
+			logging.LogRepositoryError(ErrNoMatchingDependency)
+			return ErrNoMatchingDependency
+		}
+	}
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	return nil
+}
+
+func NewSQLiteNotificationRepository(db *sql.DB) domain.NotificationRepository {
+	return sqliteNotificationRepository{db: db}
+}
@@ -2,10 +2,15 @@ package repository
 
 import (
 	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/AlejandroJorge/forum-rest-api/domain"
 	"github.com/AlejandroJorge/forum-rest-api/logging"
+	"github.com/AlejandroJorge/forum-rest-api/util"
 	"github.com/mattn/go-sqlite3"
 )
 
@@ -13,79 +18,245 @@ type sqlitePostRepository struct {
 	db *sql.DB
 }
 
-// Can return ErrRepeatedEntity, ErrNoMatchingDependency
-func (repo sqlitePostRepository) AddLike(userId uint, postId uint) error {
+// A value of 0 removes the vote, keeps the vote counters in sync, can return ErrNoMatchingDependency
+func (repo sqlitePostRepository) Vote(userId uint, postId uint, value int) error {
 	db := repo.db
 
+	tx, err := db.Begin()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+	defer tx.Rollback()
+
+	var previous int
 	query := `
-	INSERT INTO Post_Likings(Liker_ID, Post_ID)
-	VALUES (?,?)
+	SELECT Value FROM Post_Votes
+	WHERE Voter_ID = ? AND Post_ID = ?
 	`
-	_, err := db.Exec(query, userId, postId)
+	err = tx.QueryRow(query, userId, postId).Scan(&previous)
+	if err != nil && err != sql.ErrNoRows {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	if value == 0 {
+		query = `
+		DELETE FROM Post_Votes
+		WHERE Voter_ID = ? AND Post_ID = ?
+		`
+		_, err = tx.Exec(query, userId, postId)
+	} else {
+		query = `
+		INSERT INTO Post_Votes(Voter_ID, Post_ID, Value)
+		VALUES (?,?,?)
+		ON CONFLICT(Post_ID, Voter_ID) DO UPDATE SET Value = excluded.Value
+		`
+		_, err = tx.Exec(query, userId, postId, value)
+	}
 	if sqliteErr, ok := err.(sqlite3.Error); ok {
 		if sqliteErr.ExtendedCode == sqlite3.ErrConstraintForeignKey {
 			logging.LogRepositoryError(ErrNoMatchingDependency)
 			return ErrNoMatchingDependency
 		}
-		if sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey {
-			logging.LogRepositoryError(ErrRepeatedEntity)
-			return ErrRepeatedEntity
-		}
 	}
 	if err != nil {
 		logging.LogUnexpectedRepositoryError(err)
 		return ErrUnknown
 	}
 
+	upvotes, downvotes := voteCountDeltas(previous, value)
+	query = `
+	UPDATE Post
+	SET Upvotes = Upvotes + ?, Downvotes = Downvotes + ?
+	WHERE Post_ID = ?
+	`
+	_, err = tx.Exec(query, upvotes, downvotes, postId)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
 	return nil
 }
 
-// Can return ErrNoRowsAffected
-func (repo sqlitePostRepository) DeleteLike(userId uint, postId uint) error {
+// Returns the viewer fields of the posts for the user by post ID
+func (repo sqlitePostRepository) GetViewerStates(viewerID uint, postIDs []uint) (map[uint]domain.Viewer, error) {
 	db := repo.db
 
 	query := `
-	DELETE FROM Post_Likings
-	WHERE Liker_ID = ? AND Post_ID = ?
+	SELECT p.Post_ID, COALESCE(v.Value, 0), p.Owner_ID = ?,
+		EXISTS (SELECT 1 FROM Following f WHERE f.Follower_ID = ? AND f.Followed_ID = p.Owner_ID)
+	FROM Post p
+	LEFT JOIN Post_Votes v ON p.Post_ID = v.Post_ID AND v.Voter_ID = ?
+	WHERE p.Post_ID IN (` + sqlPlaceholders(len(postIDs)) + `)
 	`
-	res, err := db.Exec(query, userId, postId)
+	args := []any{viewerID, viewerID, viewerID}
+	for _, postID := range postIDs {
+		args = append(args, postID)
+	}
+	rows, err := db.Query(query, args...)
 	if err != nil {
 		logging.LogUnexpectedRepositoryError(err)
-		return ErrUnknown
+		return nil, ErrUnknown
 	}
 
-	amountAffected, err := res.RowsAffected()
+	viewers := make(map[uint]domain.Viewer)
+	for rows.Next() {
+		var postID uint
+		var viewer domain.Viewer
+		err = rows.Scan(&postID, &viewer.MyVote, &viewer.IsOwner, &viewer.FollowsAuthor)
+		if err != nil {
+			logging.LogUnexpectedRepositoryError(err)
+			return nil, ErrUnknown
+		}
+
+		viewer.LikedByMe = viewer.MyVote > 0
+		viewers[postID] = viewer
+	}
+
+	return viewers, nil
+}
+
+// Returns an slice of valid posts the user upvoted from newest to oldest, can return ErrEmptySelection
+// Only returns posts the viewer can see, viewerID is 0 for anonymous viewers
+func (repo sqlitePostRepository) GetLikedBy(viewerID, userID uint) ([]domain.Post, error) {
+	db := repo.db
+
+	var posts []domain.Post
+	query := `
+	SELECT ` + postColumns + `
+	FROM Post p
+	WHERE p.Deleted_At IS NULL AND p.Post_ID IN (
+		SELECT Post_ID FROM Post_Votes WHERE Voter_ID = ? AND Value > 0
+	) AND ` + visiblePostCondition + `
+	ORDER BY p.Creation_Date DESC, p.Post_ID DESC
+	`
+	rows, err := db.Query(query, userID, viewerID, viewerID)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return nil, ErrUnknown
+	}
+
+	for rows.Next() {
+		post, err := scanPost(rows)
+		if err != nil {
+			logging.LogUnexpectedRepositoryError(err)
+			return nil, ErrUnknown
+		}
+
+		posts = append(posts, post)
+	}
+
+	if len(posts) == 0 {
+		logging.LogRepositoryError(ErrEmptySelection)
+		return nil, ErrEmptySelection
+	}
+
+	return posts, nil
+}
+
+// Stores the rendered HTML and the entities of the post, replaces its mentions and hashtags, can return ErrNoRowsAffected
+func (repo sqlitePostRepository) SetRendered(id uint, contentHTML string, entities []domain.ContentEntity) error {
+	db := repo.db
+
+	tx, err := db.Begin()
 	if err != nil {
 		logging.LogUnexpectedRepositoryError(err)
 		return ErrUnknown
 	}
+	defer tx.Rollback()
 
-	if amountAffected == 0 {
+	err = setRenderedContent(tx, "Post", "Post_ID", id, contentHTML, entities)
+	if err == ErrNoRowsAffected {
 		logging.LogRepositoryError(ErrNoRowsAffected)
 		return ErrNoRowsAffected
 	}
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
 
 	return nil
 }
 
-// Returns the id of the created post, can return ErrNoMatchingDependency, ErrRepeatedEntity
-func (repo sqlitePostRepository) Create(ownerID uint, title, description, content string) (uint, error) {
+// Returns an slice of valid posts mentioning the user from newest to oldest, can return ErrEmptySelection
+// Only returns posts the viewer can see, viewerID is 0 for anonymous viewers
+func (repo sqlitePostRepository) GetByMention(viewerID, userID uint) ([]domain.Post, error) {
+	db := repo.db
+
+	var posts []domain.Post
+	query := `
+	SELECT ` + postColumns + `
+	FROM Post p
+	WHERE p.Deleted_At IS NULL AND p.Post_ID IN (
+		SELECT Post_ID FROM Post_Mentions WHERE User_ID = ?
+	) AND ` + visiblePostCondition + `
+	ORDER BY p.Creation_Date DESC, p.Post_ID DESC
+	`
+	rows, err := db.Query(query, userID, viewerID, viewerID)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return nil, ErrUnknown
+	}
+
+	for rows.Next() {
+		post, err := scanPost(rows)
+		if err != nil {
+			logging.LogUnexpectedRepositoryError(err)
+			return nil, ErrUnknown
+		}
+
+		posts = append(posts, post)
+	}
+
+	if len(posts) == 0 {
+		logging.LogRepositoryError(ErrEmptySelection)
+		return nil, ErrEmptySelection
+	}
+
+	return posts, nil
+}
+
+// Returns the id of the created post, generates its slug, records its first revision and attaches the media in order, can return ErrNoMatchingDependency
+func (repo sqlitePostRepository) Create(ownerID, categoryID uint, title, description, content string, tags []string, mediaIDs []uint) (uint, error) {
 	db := repo.db
 
+	tx, err := db.Begin()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return 0, ErrUnknown
+	}
+	defer tx.Rollback()
+
+	slug, err := uniquePostSlug(tx, title, 0)
+	if err != nil {
+		return 0, err
+	}
+
 	query := `
-  INSERT INTO Post(Title, Description, Content, Creation_Date, Owner_ID)
-  VALUES (?,?,?,?,?)
+  INSERT INTO Post(Title, Slug, Description, Content, Creation_Date, Last_Activity, Owner_ID, Category_ID)
+  VALUES (?,?,?,?,?,?,?,?)
   `
-	res, err := db.Exec(query, title, description, content, time.Now().Unix(), ownerID)
+	now := time.Now().Unix()
+	res, err := tx.Exec(query, title, slug, description, content, now, now, ownerID, categoryID)
 	if sqliteErr, ok := err.(sqlite3.Error); ok {
 		if sqliteErr.ExtendedCode == sqlite3.ErrConstraintForeignKey {
 			logging.LogRepositoryError(ErrNoMatchingDependency)
 			return 0, ErrNoMatchingDependency
 		}
-		if sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
-			logging.LogRepositoryError(ErrRepeatedEntity)
-			return 0, ErrRepeatedEntity
-		}
 	}
 	if err != nil {
 		logging.LogUnexpectedRepositoryError(err)
@@ -98,6 +269,36 @@ func (repo sqlitePostRepository) Create(ownerID uint, title, description, conten
 		return 0, ErrUnknown
 	}
 
+	err = recordPostRevision(tx, uint(newId), ownerID, []string{"Title", "Description", "Content"})
+	if err != nil {
+		return 0, err
+	}
+
+	err = setPostTags(tx, uint(newId), tags)
+	if err != nil {
+		return 0, err
+	}
+
+	for position, mediaID := range mediaIDs {
+		_, err = tx.Exec(`INSERT INTO Post_Attachments(Post_ID, Media_ID, Position) VALUES (?,?,?)`, newId, mediaID, position)
+		if sqliteErr, ok := err.(sqlite3.Error); ok {
+			if sqliteErr.ExtendedCode == sqlite3.ErrConstraintForeignKey {
+				logging.LogRepositoryError(ErrNoMatchingDependency)
+				return 0, ErrNoMatchingDependency
+			}
+		}
+		if err != nil {
+			logging.LogUnexpectedRepositoryError(err)
+			return 0, ErrUnknown
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return 0, ErrUnknown
+	}
+
 	return uint(newId), nil
 }
 
@@ -106,10 +307,11 @@ func (repo sqlitePostRepository) Delete(id uint) error {
 	db := repo.db
 
 	query := `
-	DELETE FROM Post
-	WHERE Post_ID = ?
+	UPDATE Post
+	SET Deleted_At = ?
+	WHERE Post_ID = ? AND Deleted_At IS NULL
 	`
-	res, err := db.Exec(query, id)
+	res, err := db.Exec(query, time.Now().Unix(), id)
 	if err != nil {
 		logging.LogUnexpectedRepositoryError(err)
 		return ErrUnknown
@@ -133,17 +335,13 @@ func (repo sqlitePostRepository) Delete(id uint) error {
 func (repo sqlitePostRepository) GetByID(id uint) (domain.Post, error) {
 	db := repo.db
 
-	var post domain.Post
-	var creationDate int64
 	query := `
-	SELECT p.Post_ID, p.Owner_ID, p.Title, p.Description, p.Content, p.Creation_Date, COUNT(l.Liker_ID)
+	SELECT ` + postColumns + `
 	FROM Post p
-	LEFT JOIN Post_Likings l ON p.Post_ID = l.Post_ID
-	WHERE p.Post_ID = ?
-	GROUP BY p.Post_ID
+	WHERE p.Post_ID = ? AND p.Deleted_At IS NULL
 	`
 	row := db.QueryRow(query, id)
-	err := row.Scan(&post.PostID, &post.OwnerID, &post.Title, &post.Description, &post.Content, &creationDate, &post.Likes)
+	post, err := scanPost(row)
 	if err == sql.ErrNoRows {
 		logging.LogRepositoryError(ErrEmptySelection)
 		return domain.Post{}, ErrEmptySelection
@@ -156,35 +354,32 @@ func (repo sqlitePostRepository) GetByID(id uint) (domain.Post, error) {
 	return post, nil
 }
 
-// Returns an slice of valid posts, can return ErrEmptySelection
-func (repo sqlitePostRepository) GetByUser(userId uint) ([]domain.Post, error) {
+// Returns an slice of valid posts with the pinned ones first, can return ErrEmptySelection
+// Only selects posts the viewer can see, viewerID is 0 for anonymous viewers
+func (repo sqlitePostRepository) GetByUser(viewerID, userId uint) ([]domain.Post, error) {
 	db := repo.db
 
 	var posts []domain.Post
 	query := `
-	SELECT p.Post_ID, p.Owner_ID, p.Title, p.Description, p.Content, p.Creation_Date, COUNT(l.Liker_ID)
+	SELECT ` + postColumns + `
 	FROM Post p
-	LEFT JOIN Post_Likings l ON p.Post_ID = l.Post_ID
-	WHERE p.Owner_ID = ?
-	GROUP BY p.Post_ID
+	WHERE p.Owner_ID = ? AND p.Deleted_At IS NULL
+		AND ` + visiblePostCondition + ` AND ` + unmutedPostCondition + `
+	ORDER BY p.Is_Pinned DESC, p.Post_ID
 	`
-	rows, err := db.Query(query, userId)
+	rows, err := db.Query(query, userId, viewerID, viewerID, viewerID)
 	if err != nil {
 		logging.LogUnexpectedRepositoryError(err)
 		return nil, ErrUnknown
 	}
 
 	for rows.Next() {
-		var post domain.Post
-		var creationDate int64
-
-		err = rows.Scan(&post.PostID, &post.OwnerID, &post.Title, &post.Description, &post.Content, &creationDate, &post.Likes)
+		post, err := scanPost(rows)
 		if err != nil {
 			logging.LogUnexpectedRepositoryError(err)
 			return nil, ErrUnknown
 		}
 
-		post.CreationDate = time.Unix(creationDate, 0)
 		posts = append(posts, post)
 	}
 
@@ -196,37 +391,60 @@ func (repo sqlitePostRepository) GetByUser(userId uint) ([]domain.Post, error) {
 	return posts, nil
 }
 
-// Returns an slice of valid posts, can return ErrEmptySelection
-func (repo sqlitePostRepository) GetPopularAfter(moment time.Time, amount uint) ([]domain.Post, error) {
+// Matches current and previous slugs, returns a valid post and can return ErrEmptySelection
+func (repo sqlitePostRepository) GetBySlug(slug string) (domain.Post, error) {
+	db := repo.db
+
+	query := `
+	SELECT ` + postColumns + `
+	FROM Post p
+	WHERE p.Deleted_At IS NULL AND (
+		p.Slug = ? OR p.Post_ID IN (SELECT Post_ID FROM Post_Slug_History WHERE Slug = ?)
+	)
+	`
+	row := db.QueryRow(query, slug, slug)
+	post, err := scanPost(row)
+	if err == sql.ErrNoRows {
+		logging.LogRepositoryError(ErrEmptySelection)
+		return domain.Post{}, ErrEmptySelection
+	}
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return domain.Post{}, ErrUnknown
+	}
+
+	return post, nil
+}
+
+// Orders by the precomputed scores, returns an slice of valid posts, can return ErrEmptySelection
+// Only selects posts the viewer can see, viewerID is 0 for anonymous viewers
+func (repo sqlitePostRepository) GetPopularAfter(viewerID uint, moment time.Time, order domain.PostSort, amount uint) ([]domain.Post, error) {
 	db := repo.db
 
 	var posts []domain.Post
 	momentInteger := moment.Unix()
 	query := `
-	SELECT p.Post_ID, p.Owner_ID, p.Title, p.Description, p.Content, p.Creation_Date, COUNT(l.Liker_ID) AS Like_Count
+	SELECT ` + postColumns + `
 	FROM Post p
-	LEFT JOIN Post_Likings l ON p.Post_ID = l.Post_ID
-	WHERE p.Creation_Date >= ?
-	GROUP BY p.Post_ID
-	ORDER BY Like_Count DESC
+	LEFT JOIN Post_Score s ON p.Post_ID = s.Post_ID
+	WHERE p.Creation_Date >= ? AND p.Deleted_At IS NULL
+		AND ` + visiblePostCondition + ` AND ` + unmutedPostCondition + `
+	ORDER BY ` + postSortClause(order) + `
 	LIMIT ?
 	`
-	rows, err := db.Query(query, momentInteger, amount)
+	rows, err := db.Query(query, momentInteger, viewerID, viewerID, viewerID, amount)
 	if err != nil {
 		logging.LogUnexpectedRepositoryError(err)
 		return nil, ErrUnknown
 	}
 
 	for rows.Next() {
-		var post domain.Post
-		var creationDate int64
-		err = rows.Scan(&post.PostID, &post.OwnerID, &post.Title, &post.Description, &post.Content, &creationDate, &post.Likes)
+		post, err := scanPost(rows)
 		if err != nil {
 			logging.LogUnexpectedRepositoryError(err)
 			return nil, ErrUnknown
 		}
 
-		post.CreationDate = time.Unix(creationDate, 0)
 		posts = append(posts, post)
 	}
 
@@ -238,97 +456,947 @@ func (repo sqlitePostRepository) GetPopularAfter(moment time.Time, amount uint)
 	return posts, nil
 }
 
-// Can return ErrNoRowsAffected
-func (repo sqlitePostRepository) UpdateContent(id uint, newContent string) error {
+// Returns an slice of valid posts, can return ErrEmptySelection
+// Includes posts in subcategories, pinned posts come first even if they're older than moment
+// Only selects posts the viewer can see, viewerID is 0 for anonymous viewers
+func (repo sqlitePostRepository) GetPopularInCategoryAfter(viewerID, categoryID uint, moment time.Time, order domain.PostSort, amount uint) ([]domain.Post, error) {
 	db := repo.db
 
+	var posts []domain.Post
+	momentInteger := moment.Unix()
 	query := `
-	UPDATE Post
-	SET	Content = ?
-	WHERE Post_ID = ?
+	WITH RECURSIVE Subcategory(Category_ID) AS (
+		SELECT ?
+		UNION
+		SELECT c.Category_ID FROM Category c JOIN Subcategory s ON c.Parent_ID = s.Category_ID
+	)
+	SELECT ` + postColumns + `
+	FROM Post p
+	LEFT JOIN Post_Score s ON p.Post_ID = s.Post_ID
+	WHERE p.Category_ID IN (SELECT Category_ID FROM Subcategory) AND (p.Creation_Date >= ? OR p.Is_Pinned = 1) AND p.Deleted_At IS NULL
+		AND ` + visiblePostCondition + ` AND ` + unmutedPostCondition + `
+	ORDER BY p.Is_Pinned DESC, ` + postSortClause(order) + `
+	LIMIT ?
 	`
-	res, err := db.Exec(query, newContent, id)
+	rows, err := db.Query(query, categoryID, momentInteger, viewerID, viewerID, viewerID, amount)
 	if err != nil {
 		logging.LogUnexpectedRepositoryError(err)
-		return ErrUnknown
+		return nil, ErrUnknown
 	}
 
-	amountAffected, err := res.RowsAffected()
-	if err != nil {
-		logging.LogUnexpectedRepositoryError(err)
-		return ErrUnknown
+	for rows.Next() {
+		post, err := scanPost(rows)
+		if err != nil {
+			logging.LogUnexpectedRepositoryError(err)
+			return nil, ErrUnknown
+		}
+
+		posts = append(posts, post)
 	}
 
-	if amountAffected == 0 {
-		logging.LogRepositoryError(ErrNoRowsAffected)
-		return ErrNoRowsAffected
+	if len(posts) == 0 {
+		logging.LogRepositoryError(ErrEmptySelection)
+		return nil, ErrEmptySelection
 	}
 
-	return nil
+	return posts, nil
 }
 
-// Can return ErrNoRowsAffected
-func (repo sqlitePostRepository) UpdateDescription(id uint, newDescription string) error {
+// Returns an slice of valid posts by profiles the follower follows from newest to oldest, can return ErrEmptySelection
+// Starts after the given post, or from the newest when beforeID is 0
+// Skips profiles the follower muted
+func (repo sqlitePostRepository) GetByFollowerBefore(followerID uint, beforeDate time.Time, beforeID uint, amount uint) ([]domain.Post, error) {
 	db := repo.db
 
+	var posts []domain.Post
+	beforeInteger := beforeDate.Unix()
 	query := `
-	UPDATE Post
-	SET	Description = ?
-	WHERE Post_ID = ?
+	SELECT ` + postColumns + `
+	FROM Post p
+	WHERE p.Deleted_At IS NULL
+		AND p.Owner_ID IN (SELECT Followed_ID FROM Following WHERE Follower_ID = ?)
+		AND (? = 0 OR p.Creation_Date < ? OR (p.Creation_Date = ? AND p.Post_ID < ?))
+		AND ` + unmutedPostCondition + `
+	ORDER BY p.Creation_Date DESC, p.Post_ID DESC
+	LIMIT ?
 	`
-	res, err := db.Exec(query, newDescription, id)
+	rows, err := db.Query(query, followerID, beforeID, beforeInteger, beforeInteger, beforeID, followerID, amount)
 	if err != nil {
 		logging.LogUnexpectedRepositoryError(err)
-		return ErrUnknown
+		return nil, ErrUnknown
 	}
 
-	amountAffected, err := res.RowsAffected()
-	if err != nil {
-		logging.LogUnexpectedRepositoryError(err)
-		return ErrUnknown
+	for rows.Next() {
+		post, err := scanPost(rows)
+		if err != nil {
+			logging.LogUnexpectedRepositoryError(err)
+			return nil, ErrUnknown
+		}
+
+		posts = append(posts, post)
 	}
 
-	if amountAffected == 0 {
-		logging.LogRepositoryError(ErrNoRowsAffected)
-		return ErrNoRowsAffected
+	if len(posts) == 0 {
+		logging.LogRepositoryError(ErrEmptySelection)
+		return nil, ErrEmptySelection
 	}
 
-	return nil
+	return posts, nil
 }
 
-// Can return ErrNoRowsAffected, ErrRepeatedEntity
-func (repo sqlitePostRepository) UpdateTitle(id uint, newTitle string) error {
+// Returns an slice of valid posts by profiles the follower follows created after moment, can return ErrEmptySelection
+// Skips profiles the follower muted
+func (repo sqlitePostRepository) GetByFollowerAfter(followerID uint, moment time.Time, amount uint) ([]domain.Post, error) {
 	db := repo.db
 
+	var posts []domain.Post
+	momentInteger := moment.Unix()
 	query := `
-	UPDATE Post
-	SET	Title = ?
-	WHERE Post_ID = ?
+	SELECT ` + postColumns + `
+	FROM Post p
+	WHERE p.Deleted_At IS NULL
+		AND p.Owner_ID IN (SELECT Followed_ID FROM Following WHERE Follower_ID = ?)
+		AND p.Creation_Date >= ?
+		AND ` + unmutedPostCondition + `
+	ORDER BY p.Creation_Date DESC, p.Post_ID DESC
+	LIMIT ?
 	`
-	res, err := db.Exec(query, newTitle, id)
-	if sqliteErr, ok := err.(sqlite3.Error); ok {
-		if sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
-			logging.LogRepositoryError(ErrRepeatedEntity)
-			return ErrRepeatedEntity
-		}
-	}
+	rows, err := db.Query(query, followerID, momentInteger, followerID, amount)
 	if err != nil {
 		logging.LogUnexpectedRepositoryError(err)
-		return ErrUnknown
+		return nil, ErrUnknown
 	}
 
-	amountAffected, err := res.RowsAffected()
-	if err != nil {
-		logging.LogUnexpectedRepositoryError(err)
-		return ErrUnknown
+	for rows.Next() {
+		post, err := scanPost(rows)
+		if err != nil {
+			logging.LogUnexpectedRepositoryError(err)
+			return nil, ErrUnknown
+		}
+
+		posts = append(posts, post)
 	}
 
-	if amountAffected == 0 {
-		logging.LogRepositoryError(ErrNoRowsAffected)
-		return ErrNoRowsAffected
+	if len(posts) == 0 {
+		logging.LogRepositoryError(ErrEmptySelection)
+		return nil, ErrEmptySelection
 	}
 
-	return nil
+	return posts, nil
+}
+
+// Returns an slice of valid posts from newest to oldest, can return ErrEmptySelection
+// Only selects posts the viewer can see, viewerID is 0 for anonymous viewers
+func (repo sqlitePostRepository) GetByTag(viewerID uint, tag string, limit, offset uint) ([]domain.Post, error) {
+	db := repo.db
+
+	var posts []domain.Post
+	query := `
+	SELECT ` + postColumns + `
+	FROM Post p
+	WHERE p.Deleted_At IS NULL AND p.Post_ID IN (
+		SELECT pt.Post_ID FROM Post_Tags pt JOIN Tag t ON t.Tag_ID = pt.Tag_ID WHERE t.Name = ?
+	)
+		AND ` + visiblePostCondition + ` AND ` + unmutedPostCondition + `
+	ORDER BY p.Creation_Date DESC, p.Post_ID DESC
+	LIMIT ? OFFSET ?
+	`
+	rows, err := db.Query(query, tag, viewerID, viewerID, viewerID, limit, offset)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return nil, ErrUnknown
+	}
+
+	for rows.Next() {
+		post, err := scanPost(rows)
+		if err != nil {
+			logging.LogUnexpectedRepositoryError(err)
+			return nil, ErrUnknown
+		}
+
+		posts = append(posts, post)
+	}
+
+	if len(posts) == 0 {
+		logging.LogRepositoryError(ErrEmptySelection)
+		return nil, ErrEmptySelection
+	}
+
+	return posts, nil
+}
+
+// Replaces every tag of the post, can return ErrNoRowsAffected
+func (repo sqlitePostRepository) UpdateTags(id uint, tags []string) error {
+	db := repo.db
+
+	tx, err := db.Begin()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	err = tx.QueryRow(`SELECT 1 FROM Post WHERE Post_ID = ? AND Deleted_At IS NULL`, id).Scan(&exists)
+	if err == sql.ErrNoRows {
+		logging.LogRepositoryError(ErrNoRowsAffected)
+		return ErrNoRowsAffected
+	}
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	err = setPostTags(tx, id, tags)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	return nil
+}
+
+// Records a revision, can return ErrNoRowsAffected
+func (repo sqlitePostRepository) UpdateContent(id, editorID uint, newContent string) error {
+	return repo.updatePostField(id, editorID, "Content", newContent)
+}
+
+// Records a revision, can return ErrNoRowsAffected
+func (repo sqlitePostRepository) UpdateDescription(id, editorID uint, newDescription string) error {
+	return repo.updatePostField(id, editorID, "Description", newDescription)
+}
+
+// Regenerates the slug keeping the previous one for redirects and records a revision, can return ErrNoRowsAffected
+func (repo sqlitePostRepository) UpdateTitle(id, editorID uint, newTitle string) error {
+	return repo.updatePostField(id, editorID, "Title", newTitle)
+}
+
+// Sets the fields of the post back to the ones of the revision and records a new revision, can return ErrNoRowsAffected
+func (repo sqlitePostRepository) Rollback(id, editorID, revision uint) error {
+	db := repo.db
+
+	tx, err := db.Begin()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+	defer tx.Rollback()
+
+	var current, target domain.PostRevision
+	query := `
+	SELECT Title, Description, Content
+	FROM Post
+	WHERE Post_ID = ? AND Deleted_At IS NULL
+	`
+	err = tx.QueryRow(query, id).Scan(&current.Title, &current.Description, &current.Content)
+	if err == sql.ErrNoRows {
+		logging.LogRepositoryError(ErrNoRowsAffected)
+		return ErrNoRowsAffected
+	}
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	query = `
+	SELECT Title, Description, Content
+	FROM Post_Revision
+	WHERE Post_ID = ? AND Revision_Number = ?
+	`
+	err = tx.QueryRow(query, id, revision).Scan(&target.Title, &target.Description, &target.Content)
+	if err == sql.ErrNoRows {
+		logging.LogRepositoryError(ErrNoRowsAffected)
+		return ErrNoRowsAffected
+	}
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	var changedFields []string
+	if current.Title != target.Title {
+		changedFields = append(changedFields, "Title")
+	}
+	if current.Description != target.Description {
+		changedFields = append(changedFields, "Description")
+	}
+	if current.Content != target.Content {
+		changedFields = append(changedFields, "Content")
+	}
+	if len(changedFields) == 0 {
+		return nil
+	}
+
+	query = `
+	UPDATE Post
+	SET Title = ?, Description = ?, Content = ?
+	WHERE Post_ID = ?
+	`
+	_, err = tx.Exec(query, target.Title, target.Description, target.Content, id)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	if current.Title != target.Title {
+		err = updatePostSlug(tx, id, target.Title)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = recordPostRevision(tx, id, editorID, changedFields)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	return nil
+}
+
+// Returns an slice of revisions from oldest to newest, can return ErrEmptySelection
+func (repo sqlitePostRepository) GetRevisions(postID uint) ([]domain.PostRevision, error) {
+	db := repo.db
+
+	var revisions []domain.PostRevision
+	query := `
+	SELECT r.Post_ID, r.Revision_Number, r.Editor_ID, r.Title, r.Description, r.Content, r.Changed_Fields, r.Revision_Date
+	FROM Post_Revision r, Post p
+	WHERE r.Post_ID = p.Post_ID AND p.Deleted_At IS NULL AND r.Post_ID = ?
+	ORDER BY r.Revision_Number
+	`
+	rows, err := db.Query(query, postID)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return nil, ErrUnknown
+	}
+
+	for rows.Next() {
+		var revision domain.PostRevision
+		var changedFields string
+		var revisionDate int64
+		err = rows.Scan(&revision.PostID, &revision.Number, &revision.EditorID, &revision.Title, &revision.Description, &revision.Content, &changedFields, &revisionDate)
+		if err != nil {
+			logging.LogUnexpectedRepositoryError(err)
+			return nil, ErrUnknown
+		}
+
+		revision.ChangedFields = strings.Split(changedFields, ",")
+		revision.RevisionDate = time.Unix(revisionDate, 0)
+		revisions = append(revisions, revision)
+	}
+
+	if len(revisions) == 0 {
+		logging.LogRepositoryError(ErrEmptySelection)
+		return nil, ErrEmptySelection
+	}
+
+	return revisions, nil
+}
+
+// Returns a revision and can return ErrEmptySelection
+func (repo sqlitePostRepository) GetRevision(postID, revisionNumber uint) (domain.PostRevision, error) {
+	db := repo.db
+
+	var revision domain.PostRevision
+	var changedFields string
+	var revisionDate int64
+	query := `
+	SELECT r.Post_ID, r.Revision_Number, r.Editor_ID, r.Title, r.Description, r.Content, r.Changed_Fields, r.Revision_Date
+	FROM Post_Revision r, Post p
+	WHERE r.Post_ID = p.Post_ID AND p.Deleted_At IS NULL AND r.Post_ID = ? AND r.Revision_Number = ?
+	`
+	row := db.QueryRow(query, postID, revisionNumber)
+	err := row.Scan(&revision.PostID, &revision.Number, &revision.EditorID, &revision.Title, &revision.Description, &revision.Content, &changedFields, &revisionDate)
+	if err == sql.ErrNoRows {
+		logging.LogRepositoryError(ErrEmptySelection)
+		return domain.PostRevision{}, ErrEmptySelection
+	}
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return domain.PostRevision{}, ErrUnknown
+	}
+
+	revision.ChangedFields = strings.Split(changedFields, ",")
+	revision.RevisionDate = time.Unix(revisionDate, 0)
+
+	return revision, nil
+}
+
+// Updates a single column of a post and records a revision, can return ErrNoRowsAffected
+func (repo sqlitePostRepository) updatePostField(id, editorID uint, column string, value string) error {
+	db := repo.db
+
+	tx, err := db.Begin()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+	defer tx.Rollback()
+
+	query := `
+	UPDATE Post
+	SET	` + column + ` = ?
+	WHERE Post_ID = ? AND Deleted_At IS NULL
+	`
+	res, err := tx.Exec(query, value, id)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	amountAffected, err := res.RowsAffected()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	if amountAffected == 0 {
+		logging.LogRepositoryError(ErrNoRowsAffected)
+		return ErrNoRowsAffected
+	}
+
+	if column == "Title" {
+		err = updatePostSlug(tx, id, value)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = recordPostRevision(tx, id, editorID, []string{column})
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	return nil
+}
+
+// Returns the slug for title, adding a numeric suffix when it's taken by a post other than postID
+func uniquePostSlug(tx *sql.Tx, title string, postID uint) (string, error) {
+	base := util.Slugify(title)
+	suffixed := base + "-[0-9]*"
+	query := `
+	SELECT Slug FROM Post WHERE (Slug = ? OR Slug GLOB ?) AND Post_ID != ?
+	UNION
+	SELECT Slug FROM Post_Slug_History WHERE (Slug = ? OR Slug GLOB ?) AND Post_ID != ?
+	`
+	rows, err := tx.Query(query, base, suffixed, postID, base, suffixed, postID)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return "", ErrUnknown
+	}
+	defer rows.Close()
+
+	taken := make(map[string]bool)
+	for rows.Next() {
+		var slug string
+		err = rows.Scan(&slug)
+		if err != nil {
+			logging.LogUnexpectedRepositoryError(err)
+			return "", ErrUnknown
+		}
+
+		taken[slug] = true
+	}
+
+	slug := base
+	for suffix := 2; taken[slug]; suffix++ {
+		slug = fmt.Sprintf("%s-%d", base, suffix)
+	}
+
+	return slug, nil
+}
+
+// Gives the post a slug for its new title, the previous slug keeps pointing to the post
+func updatePostSlug(tx *sql.Tx, postID uint, title string) error {
+	var oldSlug string
+	err := tx.QueryRow(`SELECT Slug FROM Post WHERE Post_ID = ?`, postID).Scan(&oldSlug)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	newSlug, err := uniquePostSlug(tx, title, postID)
+	if err != nil {
+		return err
+	}
+	if newSlug == oldSlug {
+		return nil
+	}
+
+	queries := []struct {
+		query string
+		args  []interface{}
+	}{
+		{`INSERT OR IGNORE INTO Post_Slug_History(Slug, Post_ID) VALUES (?,?)`, []interface{}{oldSlug, postID}},
+		{`DELETE FROM Post_Slug_History WHERE Slug = ?`, []interface{}{newSlug}},
+		{`UPDATE Post SET Slug = ? WHERE Post_ID = ?`, []interface{}{newSlug, postID}},
+	}
+	for _, q := range queries {
+		_, err = tx.Exec(q.query, q.args...)
+		if err != nil {
+			logging.LogUnexpectedRepositoryError(err)
+			return ErrUnknown
+		}
+	}
+
+	return nil
+}
+
+// Stores the current state of the post as its next revision, can return ErrNoMatchingDependency
+func recordPostRevision(tx *sql.Tx, postID, editorID uint, changedFields []string) error {
+	query := `
+	INSERT INTO Post_Revision(Post_ID, Revision_Number, Editor_ID, Title, Description, Content, Changed_Fields, Revision_Date)
+	SELECT Post_ID, COALESCE((SELECT MAX(Revision_Number) FROM Post_Revision WHERE Post_ID = ?), 0) + 1, ?, Title, Description, Content, ?, ?
+	FROM Post
+	WHERE Post_ID = ?
+	`
+	_, err := tx.Exec(query, postID, editorID, strings.Join(changedFields, ","), time.Now().Unix(), postID)
+	if sqliteErr, ok := err.(sqlite3.Error); ok {
+		if sqliteErr.ExtendedCode == sqlite3.ErrConstraintForeignKey {
+			logging.LogRepositoryError(ErrNoMatchingDependency)
+			return ErrNoMatchingDependency
+		}
+	}
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	return nil
+}
+
+// Returns what the ranking of every valid post is computed from
+func (repo sqlitePostRepository) GetEngagement() ([]domain.PostEngagement, error) {
+	db := repo.db
+
+	var engagements []domain.PostEngagement
+	query := `
+	SELECT p.Post_ID, p.Creation_Date, p.Upvotes, p.Downvotes, p.Comment_Count
+	FROM Post p
+	WHERE p.Deleted_At IS NULL
+	`
+	rows, err := db.Query(query)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return nil, ErrUnknown
+	}
+
+	for rows.Next() {
+		var engagement domain.PostEngagement
+		var creationDate int64
+		err = rows.Scan(&engagement.PostID, &creationDate, &engagement.Upvotes, &engagement.Downvotes, &engagement.Comments)
+		if err != nil {
+			logging.LogUnexpectedRepositoryError(err)
+			return nil, ErrUnknown
+		}
+
+		engagement.CreationDate = time.Unix(creationDate, 0)
+		engagements = append(engagements, engagement)
+	}
+
+	return engagements, nil
+}
+
+// Replaces the precomputed scores of the posts
+func (repo sqlitePostRepository) SaveScores(scores []domain.PostScore, refreshedAt time.Time) error {
+	db := repo.db
+
+	tx, err := db.Begin()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+	defer tx.Rollback()
+
+	query := `
+	INSERT OR REPLACE INTO Post_Score(Post_ID, Hot_Score, Top_Score, Rising_Score, Refreshed_At)
+	VALUES (?,?,?,?,?)
+	`
+	refreshedAtInteger := refreshedAt.Unix()
+	for _, score := range scores {
+		_, err = tx.Exec(query, score.PostID, score.Hot, score.Top, score.Rising, refreshedAtInteger)
+		if err != nil {
+			logging.LogUnexpectedRepositoryError(err)
+			return ErrUnknown
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	return nil
+}
+
+// Posts that haven't been scored yet rank as if they had no engagement
+func postSortClause(order domain.PostSort) string {
+	switch order {
+	case domain.PostSortTop:
+		return "COALESCE(s.Top_Score, 0) DESC, p.Post_ID DESC"
+	case domain.PostSortRising:
+		return "COALESCE(s.Rising_Score, 0) DESC, p.Post_ID DESC"
+	case domain.PostSortNew:
+		return "p.Creation_Date DESC, p.Post_ID DESC"
+	case domain.PostSortActive:
+		return "p.Last_Activity DESC, p.Post_ID DESC"
+	}
+
+	return "COALESCE(s.Hot_Score, 0) DESC, p.Post_ID DESC"
+}
+
+// Lists the tag names of a post separated by commas
+const postTagsColumn = `(
+		SELECT GROUP_CONCAT(t.Name) FROM Post_Tags pt JOIN Tag t ON t.Tag_ID = pt.Tag_ID WHERE pt.Post_ID = p.Post_ID
+	)`
+
+// Attachments are concatenated as MediaID:Key:ThumbnailKey in the order they were attached
+const postAttachmentsColumn = `(
+		SELECT GROUP_CONCAT(Attachment) FROM (
+			SELECT m.Media_ID || ':' || m.Blob_Key || ':' || m.Thumbnail_Key AS Attachment
+			FROM Post_Attachments pa JOIN Media m ON m.Media_ID = pa.Media_ID
+			WHERE pa.Post_ID = p.Post_ID
+			ORDER BY pa.Position
+		)
+	)`
+
+// Matches posts p of public profiles, of the viewer and of private profiles the viewer follows, takes the viewer ID twice
+const visiblePostCondition = `(p.Owner_ID = ?
+		OR NOT EXISTS (SELECT 1 FROM Profile pr WHERE pr.User_ID = p.Owner_ID AND pr.Is_Private = 1)
+		OR EXISTS (SELECT 1 FROM Following f WHERE f.Follower_ID = ? AND f.Followed_ID = p.Owner_ID))`
+
+// Matches posts p by profiles the viewer didn't mute, takes the viewer ID once
+const unmutedPostCondition = `NOT EXISTS (SELECT 1 FROM Profile_Mutes m WHERE m.Muter_ID = ? AND m.Muted_ID = p.Owner_ID)`
+
+// Selects every field of a post p in the order scanPost reads them
+const postColumns = `p.Post_ID, p.Owner_ID, p.Category_ID, p.Title, p.Slug, p.Description, p.Content, p.Content_HTML, p.Creation_Date, p.Upvotes - p.Downvotes, p.Upvotes, p.Downvotes, p.Comment_Count, p.Last_Activity, p.Entities, ` + postTagsColumn + `, ` + postAttachmentsColumn + `, p.Is_Locked, p.Is_Pinned, p.Is_Archived`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// Scans a row selected with postColumns, extra receives any columns selected after them
+func scanPost(row rowScanner, extra ...interface{}) (domain.Post, error) {
+	var post domain.Post
+	var creationDate int64
+	var lastActivity int64
+	var entities string
+	var tags, attachments sql.NullString
+	dest := []interface{}{&post.PostID, &post.OwnerID, &post.CategoryID, &post.Title, &post.Slug, &post.Description, &post.Content, &post.ContentHTML, &creationDate, &post.Score, &post.Upvotes, &post.Downvotes, &post.CommentCount, &lastActivity, &entities, &tags, &attachments, &post.Locked, &post.Pinned, &post.Archived}
+	err := row.Scan(append(dest, extra...)...)
+	if err != nil {
+		return domain.Post{}, err
+	}
+
+	post.CreationDate = time.Unix(creationDate, 0)
+	post.LastActivityAt = time.Unix(lastActivity, 0)
+	post.Tags = splitPostTags(tags)
+	post.Attachments = splitPostAttachments(attachments)
+	post.Entities = decodeContentEntities(entities)
+	return post, nil
+}
+
+func splitPostAttachments(attachments sql.NullString) []domain.Attachment {
+	if !attachments.Valid || attachments.String == "" {
+		return []domain.Attachment{}
+	}
+
+	var result []domain.Attachment
+	for _, attachment := range strings.Split(attachments.String, ",") {
+		fields := strings.Split(attachment, ":")
+		if len(fields) != 3 {
+			continue
+		}
+
+		mediaID, err := strconv.ParseUint(fields[0], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		result = append(result, domain.Attachment{MediaID: uint(mediaID), URL: domain.MediaURL(fields[1]), ThumbnailURL: domain.MediaURL(fields[2])})
+	}
+
+	return result
+}
+
+func splitPostTags(tags sql.NullString) []string {
+	if !tags.Valid || tags.String == "" {
+		return []string{}
+	}
+
+	names := strings.Split(tags.String, ",")
+	sort.Strings(names)
+	return names
+}
+
+// Replaces the tags of a post creating the ones that don't exist yet
+func setPostTags(tx *sql.Tx, postID uint, tags []string) error {
+	_, err := tx.Exec(`DELETE FROM Post_Tags WHERE Post_ID = ?`, postID)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	for _, tag := range tags {
+		_, err = tx.Exec(`INSERT OR IGNORE INTO Tag(Name) VALUES (?)`, tag)
+		if err != nil {
+			logging.LogUnexpectedRepositoryError(err)
+			return ErrUnknown
+		}
+
+		query := `
+		INSERT OR IGNORE INTO Post_Tags(Post_ID, Tag_ID)
+		SELECT ?, Tag_ID FROM Tag WHERE Name = ?
+		`
+		_, err = tx.Exec(query, postID, tag)
+		if err != nil {
+			logging.LogUnexpectedRepositoryError(err)
+			return ErrUnknown
+		}
+	}
+
+	return nil
+}
+
+// Clears the moderation action that removed it, can return ErrNoRowsAffected
+func (repo sqlitePostRepository) Restore(id uint) error {
+	db := repo.db
+
+	query := `
+	UPDATE Post
+	SET Deleted_At = NULL, Moderation_Action = NULL
+	WHERE Post_ID = ? AND Deleted_At IS NOT NULL
+	`
+	res, err := db.Exec(query, id)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	amountAffected, err := res.RowsAffected()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	if amountAffected == 0 {
+		logging.LogRepositoryError(ErrNoRowsAffected)
+		return ErrNoRowsAffected
+	}
+
+	return nil
+}
+
+// Returns a valid soft deleted post along with the moderation action that removed it if any, can return ErrEmptySelection
+func (repo sqlitePostRepository) GetDeletedByID(id uint) (domain.Post, error) {
+	db := repo.db
+
+	query := `
+	SELECT ` + postColumns + `, COALESCE(p.Moderation_Action, '')
+	FROM Post p
+	WHERE p.Post_ID = ? AND p.Deleted_At IS NOT NULL
+	`
+	row := db.QueryRow(query, id)
+	var moderation domain.ModerationAction
+	post, err := scanPost(row, &moderation)
+	if err == sql.ErrNoRows {
+		logging.LogRepositoryError(ErrEmptySelection)
+		return domain.Post{}, ErrEmptySelection
+	}
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return domain.Post{}, ErrUnknown
+	}
+
+	post.Moderation = moderation
+
+	return post, nil
+}
+
+// Permanently removes posts deleted before moment along with their comments and votes, posts hidden by moderators are kept, returns the amount of purged posts
+func (repo sqlitePostRepository) PurgeDeletedBefore(moment time.Time) (uint, error) {
+	db := repo.db
+
+	tx, err := db.Begin()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return 0, ErrUnknown
+	}
+	defer tx.Rollback()
+
+	purgedPosts := `
+	SELECT Post_ID FROM Post WHERE Deleted_At IS NOT NULL AND Deleted_At < ? AND ` + notHiddenCondition + `
+	`
+	queries := []string{
+		`DELETE FROM Comment_Votes WHERE Comment_ID IN (
+			SELECT Comment_ID FROM Comment WHERE Post_ID IN (` + purgedPosts + `)
+		)`,
+		`DELETE FROM Comment_Revision WHERE Comment_ID IN (
+			SELECT Comment_ID FROM Comment WHERE Post_ID IN (` + purgedPosts + `)
+		)`,
+		`DELETE FROM Comment_Mentions WHERE Comment_ID IN (
+			SELECT Comment_ID FROM Comment WHERE Post_ID IN (` + purgedPosts + `)
+		)`,
+		`DELETE FROM Comment_Hashtags WHERE Comment_ID IN (
+			SELECT Comment_ID FROM Comment WHERE Post_ID IN (` + purgedPosts + `)
+		)`,
+		`DELETE FROM Comment WHERE Post_ID IN (` + purgedPosts + `)`,
+		`DELETE FROM Post_Votes WHERE Post_ID IN (` + purgedPosts + `)`,
+		`DELETE FROM Post_Mentions WHERE Post_ID IN (` + purgedPosts + `)`,
+		`DELETE FROM Post_Hashtags WHERE Post_ID IN (` + purgedPosts + `)`,
+		`DELETE FROM Post_Attachments WHERE Post_ID IN (` + purgedPosts + `)`,
+		`DELETE FROM Post_Revision WHERE Post_ID IN (` + purgedPosts + `)`,
+		`DELETE FROM Post_Slug_History WHERE Post_ID IN (` + purgedPosts + `)`,
+		`DELETE FROM Post_Tags WHERE Post_ID IN (` + purgedPosts + `)`,
+		`DELETE FROM Post_Score WHERE Post_ID IN (` + purgedPosts + `)`,
+	}
+	momentInteger := moment.Unix()
+	for _, query := range queries {
+		_, err = tx.Exec(query, momentInteger)
+		if err != nil {
+			logging.LogUnexpectedRepositoryError(err)
+			return 0, ErrUnknown
+		}
+	}
+
+	res, err := tx.Exec(`DELETE FROM Post WHERE Deleted_At IS NOT NULL AND Deleted_At < ? AND `+notHiddenCondition, momentInteger)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return 0, ErrUnknown
+	}
+
+	amountAffected, err := res.RowsAffected()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return 0, ErrUnknown
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return 0, ErrUnknown
+	}
+
+	return uint(amountAffected), nil
+}
+
+// Marks the post as deleted by the action even if its owner already deleted it, can return ErrNoRowsAffected
+func (repo sqlitePostRepository) Moderate(id uint, action domain.ModerationAction) error {
+	db := repo.db
+
+	query := `
+	UPDATE Post
+	SET Deleted_At = COALESCE(Deleted_At, ?), Moderation_Action = ?
+	WHERE Post_ID = ?
+	`
+	res, err := db.Exec(query, time.Now().Unix(), action, id)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	amountAffected, err := res.RowsAffected()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	if amountAffected == 0 {
+		logging.LogRepositoryError(ErrNoRowsAffected)
+		return ErrNoRowsAffected
+	}
+
+	return nil
+}
+
+// Can return ErrNoRowsAffected
+func (repo sqlitePostRepository) UpdateLocked(id uint, locked bool) error {
+	return repo.updatePostState(id, `Is_Locked = ?`, locked)
+}
+
+// Can return ErrNoRowsAffected
+func (repo sqlitePostRepository) UpdatePinned(id uint, pinned bool) error {
+	return repo.updatePostState(id, `Is_Pinned = ?`, pinned)
+}
+
+// Unarchiving restarts the inactivity of the post, can return ErrNoRowsAffected
+func (repo sqlitePostRepository) UpdateArchived(id uint, archived bool) error {
+	if archived {
+		return repo.updatePostState(id, `Is_Archived = ?`, true)
+	}
+
+	return repo.updatePostState(id, `Is_Archived = ?, Unarchived_At = ?`, false, time.Now().Unix())
+}
+
+func (repo sqlitePostRepository) updatePostState(id uint, assignments string, values ...interface{}) error {
+	db := repo.db
+
+	query := `
+	UPDATE Post
+	SET ` + assignments + `
+	WHERE Post_ID = ? AND Deleted_At IS NULL
+	`
+	res, err := db.Exec(query, append(values, id)...)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	amountAffected, err := res.RowsAffected()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	if amountAffected == 0 {
+		logging.LogRepositoryError(ErrNoRowsAffected)
+		return ErrNoRowsAffected
+	}
+
+	return nil
+}
+
+// Archives the valid posts without activity since moment that aren't pinned, returns the amount of archived posts
+func (repo sqlitePostRepository) ArchiveInactiveBefore(moment time.Time) (uint, error) {
+	db := repo.db
+
+	query := `
+	UPDATE Post
+	SET Is_Archived = 1
+	WHERE Is_Archived = 0 AND Is_Pinned = 0 AND Deleted_At IS NULL AND Last_Activity < ? AND COALESCE(Unarchived_At, 0) < ?
+	`
+	momentInteger := moment.Unix()
+	res, err := db.Exec(query, momentInteger, momentInteger)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return 0, ErrUnknown
+	}
+
+	amountAffected, err := res.RowsAffected()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return 0, ErrUnknown
+	}
+
+	return uint(amountAffected), nil
 }
 
 func NewSQLitePostRepository(db *sql.DB) domain.PostRepository {
@@ -13,15 +13,22 @@ type sqliteProfileRepository struct {
 	db *sql.DB
 }
 
-// Can return ErrRepeatedEntity, ErrNoMatchingDependency
+// Keeps the follow counters in sync, can return ErrRepeatedEntity, ErrNoMatchingDependency
 func (repo sqliteProfileRepository) AddFollow(followerId uint, followedId uint) error {
 	db := repo.db
 
+	tx, err := db.Begin()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+	defer tx.Rollback()
+
 	query := `
 	INSERT INTO Following(Follower_ID,Followed_ID,Following_Date)
 	VALUES (?,?,?)
 	`
-	_, err := db.Exec(query, followerId, followedId, time.Now().Unix())
+	_, err = tx.Exec(query, followerId, followedId, time.Now().Unix())
 	if sqliteErr, ok := err.(sqlite3.Error); ok {
 		if sqliteErr.ExtendedCode == sqlite3.ErrConstraintForeignKey {
 			logging.LogRepositoryError(ErrNoMatchingDependency)
@@ -37,18 +44,37 @@ func (repo sqliteProfileRepository) AddFollow(followerId uint, followedId uint)
 		return ErrUnknown
 	}
 
+	err = adjustFollowCounters(tx, followerId, followedId, 1)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
 	return nil
 }
 
-// Can return ErrNoRowsAffected
+// Keeps the follow counters in sync, can return ErrNoRowsAffected
 func (repo sqliteProfileRepository) DeleteFollow(followerId uint, followedId uint) error {
 	db := repo.db
 
+	tx, err := db.Begin()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+	defer tx.Rollback()
+
 	query := `
 	DELETE FROM Following
 	WHERE Follower_ID = ? AND Followed_ID = ?
 	`
-	res, err := db.Exec(query, followerId, followedId)
+	res, err := tx.Exec(query, followerId, followedId)
 	if err != nil {
 		logging.LogUnexpectedRepositoryError(err)
 		return ErrUnknown
@@ -65,6 +91,18 @@ func (repo sqliteProfileRepository) DeleteFollow(followerId uint, followedId uin
 		return ErrNoRowsAffected
 	}
 
+	err = adjustFollowCounters(tx, followerId, followedId, -1)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
 	return nil
 }
 
@@ -74,14 +112,11 @@ func (repo sqliteProfileRepository) GetFollowersByID(userId uint) ([]domain.Prof
 
 	var profiles []domain.Profile
 	query := `
-  SELECT p.User_ID, p.Display_Name, p.Tag_Name, p.Picture_Path, p.Background_Path, COUNT(f1.Follower_ID), COUNT(f2.Followed_ID)
+  SELECT p.User_ID, p.Display_Name, p.Tag_Name, p.Picture_Path, p.Background_Path, p.Follower_Count, p.Follow_Count, p.Is_Private
   FROM Profile p
-	LEFT JOIN Following f1 ON p.User_ID = f1.Followed_ID
-	LEFT JOIN Following f2 ON p.User_ID = f2.Follower_ID
   WHERE p.User_ID IN (
 		SELECT Follower_ID FROM Following WHERE Followed_ID = ?
 	)
-	GROUP BY p.User_ID
 	`
 	rows, err := db.Query(query, userId)
 	if err != nil {
@@ -91,7 +126,7 @@ func (repo sqliteProfileRepository) GetFollowersByID(userId uint) ([]domain.Prof
 
 	for rows.Next() {
 		var p domain.Profile
-		err = rows.Scan(&p.UserID, &p.DisplayName, &p.TagName, &p.PicturePath, &p.BackgroundPath, &p.Follows, &p.Followers)
+		err = rows.Scan(&p.UserID, &p.DisplayName, &p.TagName, &p.PicturePath, &p.BackgroundPath, &p.Followers, &p.Follows, &p.IsPrivate)
 		if err != nil {
 			logging.LogUnexpectedRepositoryError(err)
 			return nil, ErrUnknown
@@ -114,15 +149,12 @@ func (repo sqliteProfileRepository) GetFollowersByTagName(tagName string) ([]dom
 
 	var profiles []domain.Profile
 	query := `
-  SELECT p.User_ID, p.Display_Name, p.Tag_Name, p.Picture_Path, p.Background_Path, COUNT(f1.Follower_ID), COUNT(f2.Followed_ID)
+  SELECT p.User_ID, p.Display_Name, p.Tag_Name, p.Picture_Path, p.Background_Path, p.Follower_Count, p.Follow_Count, p.Is_Private
   FROM Profile p
-	LEFT JOIN Following f1 ON p.User_ID = f1.Followed_ID
-	LEFT JOIN Following f2 ON p.User_ID = f2.Follower_ID
   WHERE p.User_ID IN (
 		SELECT f.Follower_ID FROM Following f, Profile p 
 		WHERE f.Followed_ID = p.User_ID AND p.Tag_Name = ?
 	)
-	GROUP BY p.User_ID
 	`
 	rows, err := db.Query(query, tagName)
 	if err != nil {
@@ -132,7 +164,7 @@ func (repo sqliteProfileRepository) GetFollowersByTagName(tagName string) ([]dom
 
 	for rows.Next() {
 		var p domain.Profile
-		err = rows.Scan(&p.UserID, &p.DisplayName, &p.TagName, &p.PicturePath, &p.BackgroundPath, &p.Follows, &p.Followers)
+		err = rows.Scan(&p.UserID, &p.DisplayName, &p.TagName, &p.PicturePath, &p.BackgroundPath, &p.Followers, &p.Follows, &p.IsPrivate)
 		if err != nil {
 			logging.LogUnexpectedRepositoryError(err)
 			return nil, ErrUnknown
@@ -155,14 +187,11 @@ func (repo sqliteProfileRepository) GetFollowsByID(userId uint) ([]domain.Profil
 
 	var profiles []domain.Profile
 	query := `
-  SELECT p.User_ID, p.Display_Name, p.Tag_Name, p.Picture_Path, p.Background_Path, COUNT(f1.Follower_ID), COUNT(f2.Followed_ID)
+  SELECT p.User_ID, p.Display_Name, p.Tag_Name, p.Picture_Path, p.Background_Path, p.Follower_Count, p.Follow_Count, p.Is_Private
   FROM Profile p
-	LEFT JOIN Following f1 ON p.User_ID = f1.Followed_ID
-	LEFT JOIN Following f2 ON p.User_ID = f2.Follower_ID
   WHERE p.User_ID IN (
 		SELECT Followed_ID FROM Following WHERE Follower_ID = ?
 	)
-	GROUP BY p.User_ID
 	`
 	rows, err := db.Query(query, userId)
 	if err != nil {
@@ -172,7 +201,7 @@ func (repo sqliteProfileRepository) GetFollowsByID(userId uint) ([]domain.Profil
 
 	for rows.Next() {
 		var p domain.Profile
-		err = rows.Scan(&p.UserID, &p.DisplayName, &p.TagName, &p.PicturePath, &p.BackgroundPath, &p.Follows, &p.Followers)
+		err = rows.Scan(&p.UserID, &p.DisplayName, &p.TagName, &p.PicturePath, &p.BackgroundPath, &p.Followers, &p.Follows, &p.IsPrivate)
 		if err != nil {
 			logging.LogUnexpectedRepositoryError(err)
 			return nil, ErrUnknown
@@ -195,15 +224,12 @@ func (repo sqliteProfileRepository) GetFollowsByTagName(tagName string) ([]domai
 
 	var profiles []domain.Profile
 	query := `
-  SELECT p.User_ID, p.Display_Name, p.Tag_Name, p.Picture_Path, p.Background_Path, COUNT(f1.Follower_ID), COUNT(f2.Followed_ID)
+  SELECT p.User_ID, p.Display_Name, p.Tag_Name, p.Picture_Path, p.Background_Path, p.Follower_Count, p.Follow_Count, p.Is_Private
   FROM Profile p
-	LEFT JOIN Following f1 ON p.User_ID = f1.Followed_ID
-	LEFT JOIN Following f2 ON p.User_ID = f2.Follower_ID
   WHERE p.User_ID IN (
 		SELECT f.Followed_ID FROM Following f, Profile p 
 		WHERE f.Follower_ID = p.User_ID AND p.Tag_Name = ?
 	)
-	GROUP BY p.User_ID
 	`
 	rows, err := db.Query(query, tagName)
 	if err != nil {
@@ -213,7 +239,7 @@ func (repo sqliteProfileRepository) GetFollowsByTagName(tagName string) ([]domai
 
 	for rows.Next() {
 		var p domain.Profile
-		err = rows.Scan(&p.UserID, &p.DisplayName, &p.TagName, &p.PicturePath, &p.BackgroundPath, &p.Follows, &p.Followers)
+		err = rows.Scan(&p.UserID, &p.DisplayName, &p.TagName, &p.PicturePath, &p.BackgroundPath, &p.Followers, &p.Follows, &p.IsPrivate)
 		if err != nil {
 			logging.LogUnexpectedRepositoryError(err)
 			return nil, ErrUnknown
@@ -297,15 +323,12 @@ func (repo sqliteProfileRepository) GetByTagName(tagName string) (domain.Profile
 
 	var profile domain.Profile
 	query := `
-  SELECT p.User_ID, p.Display_Name, p.Tag_Name, p.Picture_Path, p.Background_Path, COUNT(f1.Follower_ID), COUNT(f2.Followed_ID)
+  SELECT p.User_ID, p.Display_Name, p.Tag_Name, p.Picture_Path, p.Background_Path, p.Follower_Count, p.Follow_Count, p.Is_Private
   FROM Profile p
-	LEFT JOIN Following f1 ON p.User_ID = f1.Followed_ID
-	LEFT JOIN Following f2 ON p.User_ID = f2.Follower_ID
   WHERE p.Tag_Name = ?
-	GROUP BY p.User_ID
   `
 	row := db.QueryRow(query, tagName)
-	err := row.Scan(&profile.UserID, &profile.DisplayName, &profile.TagName, &profile.PicturePath, &profile.BackgroundPath, &profile.Followers, &profile.Follows)
+	err := row.Scan(&profile.UserID, &profile.DisplayName, &profile.TagName, &profile.PicturePath, &profile.BackgroundPath, &profile.Followers, &profile.Follows, &profile.IsPrivate)
 	if err == sql.ErrNoRows {
 		logging.LogRepositoryError(ErrEmptySelection)
 		return domain.Profile{}, ErrEmptySelection
@@ -324,15 +347,12 @@ func (repo sqliteProfileRepository) GetByUserID(userId uint) (domain.Profile, er
 
 	var profile domain.Profile
 	query := `
-  SELECT p.User_ID, p.Display_Name, p.Tag_Name, p.Picture_Path, p.Background_Path, COUNT(f1.Follower_ID), COUNT(f2.Followed_ID)
+  SELECT p.User_ID, p.Display_Name, p.Tag_Name, p.Picture_Path, p.Background_Path, p.Follower_Count, p.Follow_Count, p.Is_Private
   FROM Profile p
-	LEFT JOIN Following f1 ON p.User_ID = f1.Followed_ID
-	LEFT JOIN Following f2 ON p.User_ID = f2.Follower_ID
   WHERE p.User_ID = ?
-	GROUP BY p.User_ID
   `
 	row := db.QueryRow(query, userId)
-	err := row.Scan(&profile.UserID, &profile.DisplayName, &profile.TagName, &profile.PicturePath, &profile.BackgroundPath, &profile.Followers, &profile.Follows)
+	err := row.Scan(&profile.UserID, &profile.DisplayName, &profile.TagName, &profile.PicturePath, &profile.BackgroundPath, &profile.Followers, &profile.Follows, &profile.IsPrivate)
 	if err == sql.ErrNoRows {
 		logging.LogRepositoryError(ErrEmptySelection)
 		return domain.Profile{}, ErrEmptySelection
@@ -467,6 +487,460 @@ func (repo sqliteProfileRepository) UpdateTagName(id uint, newTagName string) er
 	return nil
 }
 
+// Adds delta to the follows of the follower and the followers of the followed
+// Stops following each other and drops their follow requests, keeps the follow counters in sync, can return ErrRepeatedEntity, ErrNoMatchingDependency
+func (repo sqliteProfileRepository) AddBlock(blockerID, blockedID uint) error {
+	db := repo.db
+
+	tx, err := db.Begin()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+	defer tx.Rollback()
+
+	query := `
+	INSERT INTO Profile_Blocks(Blocker_ID, Blocked_ID, Block_Date)
+	VALUES (?,?,?)
+	`
+	_, err = tx.Exec(query, blockerID, blockedID, time.Now().Unix())
+	if sqliteErr, ok := err.(sqlite3.Error); ok {
+		if sqliteErr.ExtendedCode == sqlite3.ErrConstraintForeignKey {
+			logging.LogRepositoryError(ErrNoMatchingDependency)
+			return ErrNoMatchingDependency
+		}
+		if sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey {
+			logging.LogRepositoryError(ErrRepeatedEntity)
+			return ErrRepeatedEntity
+		}
+	}
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	follows := [][2]uint{{blockerID, blockedID}, {blockedID, blockerID}}
+	for _, follow := range follows {
+		res, err := tx.Exec(`DELETE FROM Following WHERE Follower_ID = ? AND Followed_ID = ?`, follow[0], follow[1])
+		if err != nil {
+			logging.LogUnexpectedRepositoryError(err)
+			return ErrUnknown
+		}
+
+		amountAffected, err := res.RowsAffected()
+		if err != nil {
+			logging.LogUnexpectedRepositoryError(err)
+			return ErrUnknown
+		}
+
+		if amountAffected == 0 {
+			continue
+		}
+
+		err = adjustFollowCounters(tx, follow[0], follow[1], -1)
+		if err != nil {
+			logging.LogUnexpectedRepositoryError(err)
+			return ErrUnknown
+		}
+	}
+
+	_, err = tx.Exec(`DELETE FROM Follow_Requests WHERE (Requester_ID = ? AND Target_ID = ?) OR (Requester_ID = ? AND Target_ID = ?)`, blockerID, blockedID, blockedID, blockerID)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	return nil
+}
+
+// Can return ErrNoRowsAffected
+func (repo sqliteProfileRepository) DeleteBlock(blockerID, blockedID uint) error {
+	return repo.deleteRelation(`DELETE FROM Profile_Blocks WHERE Blocker_ID = ? AND Blocked_ID = ?`, blockerID, blockedID)
+}
+
+func (repo sqliteProfileRepository) IsBlocked(blockerID, blockedID uint) (bool, error) {
+	db := repo.db
+
+	var blocked bool
+	query := `
+	SELECT EXISTS (SELECT 1 FROM Profile_Blocks WHERE Blocker_ID = ? AND Blocked_ID = ?)
+	`
+	err := db.QueryRow(query, blockerID, blockedID).Scan(&blocked)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return false, ErrUnknown
+	}
+
+	return blocked, nil
+}
+
+// Returns an slice of valid profiles, can return ErrEmptySelection
+func (repo sqliteProfileRepository) GetBlocked(blockerID uint) ([]domain.Profile, error) {
+	return repo.selectProfiles(`SELECT Blocked_ID FROM Profile_Blocks WHERE Blocker_ID = ?`, blockerID)
+}
+
+// Can return ErrRepeatedEntity, ErrNoMatchingDependency
+func (repo sqliteProfileRepository) AddMute(muterID, mutedID uint) error {
+	db := repo.db
+
+	query := `
+	INSERT INTO Profile_Mutes(Muter_ID, Muted_ID, Mute_Date)
+	VALUES (?,?,?)
+	`
+	_, err := db.Exec(query, muterID, mutedID, time.Now().Unix())
+	if sqliteErr, ok := err.(sqlite3.Error); ok {
+		if sqliteErr.ExtendedCode == sqlite3.ErrConstraintForeignKey {
+			logging.LogRepositoryError(ErrNoMatchingDependency)
+			return ErrNoMatchingDependency
+		}
+		if sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey {
+			logging.LogRepositoryError(ErrRepeatedEntity)
+			return ErrRepeatedEntity
+		}
+	}
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	return nil
+}
+
+// Can return ErrNoRowsAffected
+func (repo sqliteProfileRepository) DeleteMute(muterID, mutedID uint) error {
+	return repo.deleteRelation(`DELETE FROM Profile_Mutes WHERE Muter_ID = ? AND Muted_ID = ?`, muterID, mutedID)
+}
+
+// Returns an slice of valid profiles, can return ErrEmptySelection
+func (repo sqliteProfileRepository) GetMuted(muterID uint) ([]domain.Profile, error) {
+	return repo.selectProfiles(`SELECT Muted_ID FROM Profile_Mutes WHERE Muter_ID = ?`, muterID)
+}
+
+func (repo sqliteProfileRepository) GetMutedIDs(muterID uint) ([]uint, error) {
+	db := repo.db
+
+	var ids []uint
+	rows, err := db.Query(`SELECT Muted_ID FROM Profile_Mutes WHERE Muter_ID = ?`, muterID)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return nil, ErrUnknown
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id uint
+		err = rows.Scan(&id)
+		if err != nil {
+			logging.LogUnexpectedRepositoryError(err)
+			return nil, ErrUnknown
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+func (repo sqliteProfileRepository) deleteRelation(query string, fromID, toID uint) error {
+	db := repo.db
+
+	res, err := db.Exec(query, fromID, toID)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	amountAffected, err := res.RowsAffected()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	if amountAffected == 0 {
+		logging.LogRepositoryError(ErrNoRowsAffected)
+		return ErrNoRowsAffected
+	}
+
+	return nil
+}
+
+// Selects the profiles whose ID is returned by the subquery, can return ErrEmptySelection
+func (repo sqliteProfileRepository) selectProfiles(subquery string, args ...interface{}) ([]domain.Profile, error) {
+	db := repo.db
+
+	var profiles []domain.Profile
+	query := `
+	SELECT p.User_ID, p.Display_Name, p.Tag_Name, p.Picture_Path, p.Background_Path, p.Follower_Count, p.Follow_Count, p.Is_Private
+	FROM Profile p
+	WHERE p.User_ID IN (` + subquery + `)
+	`
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return nil, ErrUnknown
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p domain.Profile
+		err = rows.Scan(&p.UserID, &p.DisplayName, &p.TagName, &p.PicturePath, &p.BackgroundPath, &p.Followers, &p.Follows, &p.IsPrivate)
+		if err != nil {
+			logging.LogUnexpectedRepositoryError(err)
+			return nil, ErrUnknown
+		}
+
+		profiles = append(profiles, p)
+	}
+
+	if len(profiles) == 0 {
+		logging.LogRepositoryError(ErrEmptySelection)
+		return nil, ErrEmptySelection
+	}
+
+	return profiles, nil
+}
+
+// Making the profile public accepts its pending follow requests, keeps the follow counters in sync, can return ErrNoRowsAffected
+func (repo sqliteProfileRepository) UpdatePrivacy(id uint, isPrivate bool) error {
+	db := repo.db
+
+	tx, err := db.Begin()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`UPDATE Profile SET Is_Private = ? WHERE User_ID = ?`, isPrivate, id)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	amountAffected, err := res.RowsAffected()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	if amountAffected == 0 {
+		logging.LogRepositoryError(ErrNoRowsAffected)
+		return ErrNoRowsAffected
+	}
+
+	if !isPrivate {
+		rows, err := tx.Query(`SELECT Requester_ID FROM Follow_Requests WHERE Target_ID = ?`, id)
+		if err != nil {
+			logging.LogUnexpectedRepositoryError(err)
+			return ErrUnknown
+		}
+
+		var requesterIDs []uint
+		for rows.Next() {
+			var requesterID uint
+			err = rows.Scan(&requesterID)
+			if err != nil {
+				rows.Close()
+				logging.LogUnexpectedRepositoryError(err)
+				return ErrUnknown
+			}
+
+			requesterIDs = append(requesterIDs, requesterID)
+		}
+		rows.Close()
+
+		for _, requesterID := range requesterIDs {
+			err = acceptFollowRequest(tx, requesterID, id)
+			if err != nil {
+				logging.LogUnexpectedRepositoryError(err)
+				return ErrUnknown
+			}
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	return nil
+}
+
+// Can return ErrRepeatedEntity, ErrNoMatchingDependency
+func (repo sqliteProfileRepository) AddFollowRequest(requesterID, targetID uint) error {
+	db := repo.db
+
+	query := `
+	INSERT INTO Follow_Requests(Requester_ID, Target_ID, Request_Date)
+	VALUES (?,?,?)
+	`
+	_, err := db.Exec(query, requesterID, targetID, time.Now().Unix())
+	if sqliteErr, ok := err.(sqlite3.Error); ok {
+		if sqliteErr.ExtendedCode == sqlite3.ErrConstraintForeignKey {
+			logging.LogRepositoryError(ErrNoMatchingDependency)
+			return ErrNoMatchingDependency
+		}
+		if sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey {
+			logging.LogRepositoryError(ErrRepeatedEntity)
+			return ErrRepeatedEntity
+		}
+	}
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	return nil
+}
+
+// Can return ErrNoRowsAffected
+func (repo sqliteProfileRepository) DeleteFollowRequest(requesterID, targetID uint) error {
+	return repo.deleteRelation(`DELETE FROM Follow_Requests WHERE Requester_ID = ? AND Target_ID = ?`, requesterID, targetID)
+}
+
+// Turns the request into a follow, keeps the follow counters in sync, can return ErrNoRowsAffected
+func (repo sqliteProfileRepository) ApproveFollowRequest(requesterID, targetID uint) error {
+	db := repo.db
+
+	tx, err := db.Begin()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+	defer tx.Rollback()
+
+	var pending bool
+	query := `
+	SELECT EXISTS (SELECT 1 FROM Follow_Requests WHERE Requester_ID = ? AND Target_ID = ?)
+	`
+	err = tx.QueryRow(query, requesterID, targetID).Scan(&pending)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	if !pending {
+		logging.LogRepositoryError(ErrNoRowsAffected)
+		return ErrNoRowsAffected
+	}
+
+	err = acceptFollowRequest(tx, requesterID, targetID)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	return nil
+}
+
+// Returns an slice of valid profiles that requested to follow the target, can return ErrEmptySelection
+func (repo sqliteProfileRepository) GetFollowRequests(targetID uint) ([]domain.Profile, error) {
+	return repo.selectProfiles(`SELECT Requester_ID FROM Follow_Requests WHERE Target_ID = ?`, targetID)
+}
+
+// Returns which of the profiles are private and not followed by the viewer, the viewer itself is never restricted
+func (repo sqliteProfileRepository) GetRestrictedIDs(viewerID uint, userIDs []uint) ([]uint, error) {
+	db := repo.db
+
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	query := `
+	SELECT p.User_ID
+	FROM Profile p
+	WHERE p.Is_Private = 1 AND p.User_ID != ? AND p.User_ID IN (` + sqlPlaceholders(len(userIDs)) + `)
+		AND NOT EXISTS (SELECT 1 FROM Following f WHERE f.Follower_ID = ? AND f.Followed_ID = p.User_ID)
+	`
+	args := []interface{}{viewerID}
+	for _, userID := range userIDs {
+		args = append(args, userID)
+	}
+	args = append(args, viewerID)
+
+	var ids []uint
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return nil, ErrUnknown
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id uint
+		err = rows.Scan(&id)
+		if err != nil {
+			logging.LogUnexpectedRepositoryError(err)
+			return nil, ErrUnknown
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// Removes the request and creates the follow unless it already exists
+func acceptFollowRequest(tx *sql.Tx, requesterID, targetID uint) error {
+	_, err := tx.Exec(`DELETE FROM Follow_Requests WHERE Requester_ID = ? AND Target_ID = ?`, requesterID, targetID)
+	if err != nil {
+		return err
+	}
+
+	query := `
+	INSERT OR IGNORE INTO Following(Follower_ID,Followed_ID,Following_Date)
+	VALUES (?,?,?)
+	`
+	res, err := tx.Exec(query, requesterID, targetID, time.Now().Unix())
+	if err != nil {
+		return err
+	}
+
+	amountAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if amountAffected == 0 {
+		return nil
+	}
+
+	return adjustFollowCounters(tx, requesterID, targetID, 1)
+}
+
+func adjustFollowCounters(tx *sql.Tx, followerID, followedID uint, delta int) error {
+	query := `
+	UPDATE Profile
+	SET Follow_Count = Follow_Count + ?
+	WHERE User_ID = ?
+	`
+	_, err := tx.Exec(query, delta, followerID)
+	if err != nil {
+		return err
+	}
+
+	query = `
+	UPDATE Profile
+	SET Follower_Count = Follower_Count + ?
+	WHERE User_ID = ?
+	`
+	_, err = tx.Exec(query, delta, followedID)
+	return err
+}
+
 func NewSQLiteProfileRepository(db *sql.DB) domain.ProfileRepository {
 	return sqliteProfileRepository{db: db}
 }
@@ -0,0 +1,314 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/logging"
+	"github.com/mattn/go-sqlite3"
+)
+
+type sqliteReportRepository struct {
+	db *sql.DB
+}
+
+// Returns the id of the open report of the content, opening one if there isn't, can return ErrRepeatedEntity, ErrNoMatchingDependency
+func (repo sqliteReportRepository) Create(targetType domain.ReportTarget, targetID, authorID, reporterID uint, reason domain.ReportReason, details string) (uint, error) {
+	db := repo.db
+
+	tx, err := db.Begin()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return 0, ErrUnknown
+	}
+	defer tx.Rollback()
+
+	now := time.Now().Unix()
+	var reportID uint
+	query := `
+	SELECT Report_ID
+	FROM Report
+	WHERE Target_Type = ? AND Target_ID = ? AND Status = ?
+	`
+	err = tx.QueryRow(query, targetType, targetID, domain.ReportStatusOpen).Scan(&reportID)
+	if err == sql.ErrNoRows {
+		query = `
+		INSERT INTO Report(Target_Type, Target_ID, Author_ID, Status, Creation_Date)
+		VALUES (?,?,?,?,?)
+		`
+		res, err := tx.Exec(query, targetType, targetID, authorID, domain.ReportStatusOpen, now)
+		if sqliteErr, ok := err.(sqlite3.Error); ok {
+			if sqliteErr.ExtendedCode == sqlite3.ErrConstraintForeignKey {
+				logging.LogRepositoryError(ErrNoMatchingDependency)
+				return 0, ErrNoMatchingDependency
+			}
+		}
+		if err != nil {
+			logging.LogUnexpectedRepositoryError(err)
+			return 0, ErrUnknown
+		}
+
+		newId, err := res.LastInsertId()
+		if err != nil {
+			logging.LogUnexpectedRepositoryError(err)
+			return 0, ErrUnknown
+		}
+		reportID = uint(newId)
+	} else if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return 0, ErrUnknown
+	}
+
+	query = `
+	INSERT INTO Report_Reasons(Report_ID, Reporter_ID, Reason, Details, Report_Date)
+	VALUES (?,?,?,?,?)
+	`
+	_, err = tx.Exec(query, reportID, reporterID, reason, details, now)
+	if sqliteErr, ok := err.(sqlite3.Error); ok {
+		if sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey {
+			logging.LogRepositoryError(ErrRepeatedEntity)
+			return 0, ErrRepeatedEntity
+		}
+		if sqliteErr.ExtendedCode == sqlite3.ErrConstraintForeignKey {
+			logging.LogRepositoryError(ErrNoMatchingDependency)
+			return 0, ErrNoMatchingDependency
+		}
+	}
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return 0, ErrUnknown
+	}
+
+	_, err = tx.Exec(`UPDATE Report SET Reporter_Count = Reporter_Count + 1 WHERE Report_ID = ?`, reportID)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return 0, ErrUnknown
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return 0, ErrUnknown
+	}
+
+	return reportID, nil
+}
+
+// Returns a valid report with its reasons, can return ErrEmptySelection
+func (repo sqliteReportRepository) GetByID(id uint) (domain.Report, error) {
+	db := repo.db
+
+	var report domain.Report
+	var creationDate, resolutionDate int64
+	query := `
+	SELECT Report_ID, Target_Type, Target_ID, Author_ID, Status, Reporter_Count, Creation_Date, COALESCE(Resolution_Date, 0)
+	FROM Report
+	WHERE Report_ID = ?
+	`
+	err := db.QueryRow(query, id).Scan(&report.ID, &report.TargetType, &report.TargetID, &report.AuthorID, &report.Status, &report.ReporterCount, &creationDate, &resolutionDate)
+	if err == sql.ErrNoRows {
+		logging.LogRepositoryError(ErrEmptySelection)
+		return domain.Report{}, ErrEmptySelection
+	}
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return domain.Report{}, ErrUnknown
+	}
+
+	report.CreatedAt = time.Unix(creationDate, 0)
+	if resolutionDate != 0 {
+		report.ResolvedAt = time.Unix(resolutionDate, 0)
+	}
+	report.Reasons, err = repo.getReasons(id)
+	if err != nil {
+		return domain.Report{}, err
+	}
+
+	return report, nil
+}
+
+// Returns an slice of reports with their reasons, open ones by most reporters first and the rest by latest resolution, can return ErrEmptySelection
+func (repo sqliteReportRepository) GetByStatus(status domain.ReportStatus, limit, offset uint) ([]domain.Report, error) {
+	db := repo.db
+
+	order := `Resolution_Date DESC, Report_ID DESC`
+	if status == domain.ReportStatusOpen {
+		order = `Reporter_Count DESC, Creation_Date, Report_ID`
+	}
+
+	var reports []domain.Report
+	query := `
+	SELECT Report_ID, Target_Type, Target_ID, Author_ID, Status, Reporter_Count, Creation_Date, COALESCE(Resolution_Date, 0)
+	FROM Report
+	WHERE Status = ?
+	ORDER BY ` + order + `
+	LIMIT ? OFFSET ?
+	`
+	rows, err := db.Query(query, status, limit, offset)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return nil, ErrUnknown
+	}
+
+	for rows.Next() {
+		var report domain.Report
+		var creationDate, resolutionDate int64
+		err = rows.Scan(&report.ID, &report.TargetType, &report.TargetID, &report.AuthorID, &report.Status, &report.ReporterCount, &creationDate, &resolutionDate)
+		if err != nil {
+			rows.Close()
+			logging.LogUnexpectedRepositoryError(err)
+			return nil, ErrUnknown
+		}
+
+		report.CreatedAt = time.Unix(creationDate, 0)
+		if resolutionDate != 0 {
+			report.ResolvedAt = time.Unix(resolutionDate, 0)
+		}
+		reports = append(reports, report)
+	}
+	rows.Close()
+
+	if len(reports) == 0 {
+		logging.LogRepositoryError(ErrEmptySelection)
+		return nil, ErrEmptySelection
+	}
+
+	for i := range reports {
+		reports[i].Reasons, err = repo.getReasons(reports[i].ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return reports, nil
+}
+
+// Returns how many reporters gave each reason
+func (repo sqliteReportRepository) getReasons(reportID uint) (map[domain.ReportReason]uint, error) {
+	db := repo.db
+
+	reasons := make(map[domain.ReportReason]uint)
+	query := `
+	SELECT Reason, COUNT(*)
+	FROM Report_Reasons
+	WHERE Report_ID = ?
+	GROUP BY Reason
+	`
+	rows, err := db.Query(query, reportID)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return nil, ErrUnknown
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var reason domain.ReportReason
+		var count uint
+		err = rows.Scan(&reason, &count)
+		if err != nil {
+			logging.LogUnexpectedRepositoryError(err)
+			return nil, ErrUnknown
+		}
+
+		reasons[reason] = count
+	}
+
+	return reasons, nil
+}
+
+// Closes an open report with the status and records the decision, can return ErrNoRowsAffected
+func (repo sqliteReportRepository) Resolve(status domain.ReportStatus, entry domain.ModerationLogEntry) error {
+	db := repo.db
+
+	tx, err := db.Begin()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+	defer tx.Rollback()
+
+	now := time.Now().Unix()
+	query := `
+	UPDATE Report
+	SET Status = ?, Resolution_Date = ?
+	WHERE Report_ID = ? AND Status = ?
+	`
+	res, err := tx.Exec(query, status, now, entry.ReportID, domain.ReportStatusOpen)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	amountAffected, err := res.RowsAffected()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	if amountAffected == 0 {
+		logging.LogRepositoryError(ErrNoRowsAffected)
+		return ErrNoRowsAffected
+	}
+
+	query = `
+	INSERT INTO Moderation_Log(Moderator_ID, Report_ID, Action, Target_Type, Target_ID, Author_ID, Note, Action_Date)
+	VALUES (?,?,?,?,?,?,?,?)
+	`
+	_, err = tx.Exec(query, entry.ModeratorID, entry.ReportID, entry.Action, entry.TargetType, entry.TargetID, entry.AuthorID, entry.Note, now)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	return nil
+}
+
+// Returns an slice of decisions from newest to oldest, can return ErrEmptySelection
+func (repo sqliteReportRepository) GetLog(limit, offset uint) ([]domain.ModerationLogEntry, error) {
+	db := repo.db
+
+	var entries []domain.ModerationLogEntry
+	query := `
+	SELECT Log_ID, Moderator_ID, Report_ID, Action, Target_Type, Target_ID, Author_ID, Note, Action_Date
+	FROM Moderation_Log
+	ORDER BY Log_ID DESC
+	LIMIT ? OFFSET ?
+	`
+	rows, err := db.Query(query, limit, offset)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return nil, ErrUnknown
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry domain.ModerationLogEntry
+		var actionDate int64
+		err = rows.Scan(&entry.ID, &entry.ModeratorID, &entry.ReportID, &entry.Action, &entry.TargetType, &entry.TargetID, &entry.AuthorID, &entry.Note, &actionDate)
+		if err != nil {
+			logging.LogUnexpectedRepositoryError(err)
+			return nil, ErrUnknown
+		}
+
+		entry.CreatedAt = time.Unix(actionDate, 0)
+		entries = append(entries, entry)
+	}
+
+	if len(entries) == 0 {
+		logging.LogRepositoryError(ErrEmptySelection)
+		return nil, ErrEmptySelection
+	}
+
+	return entries, nil
+}
+
+func NewSQLiteReportRepository(db *sql.DB) domain.ReportRepository {
+	return sqliteReportRepository{db: db}
+}
@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"database/sql"
+	"html"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/logging"
+)
+
+type sqliteSearchRepository struct {
+	db *sql.DB
+}
+
+// Expects a valid FTS5 query, returns an slice of results from best to worst match, can return ErrEmptySelection
+func (repo sqliteSearchRepository) SearchPosts(query string, filter domain.SearchFilter, limit, offset uint) ([]domain.SearchResult, error) {
+	db := repo.db
+
+	from, to := searchDateRange(filter)
+	sqlQuery := `
+	SELECT p.Post_ID, p.Owner_ID,
+		highlight(Post_Search, 0, char(2), char(3)),
+		snippet(Post_Search, -1, char(2), char(3), '...', 24),
+		-bm25(Post_Search, 10.0, 4.0, 1.0),
+		p.Creation_Date
+	FROM Post_Search
+	JOIN Post p ON p.Post_ID = Post_Search.rowid
+	WHERE Post_Search MATCH ? AND p.Deleted_At IS NULL
+		AND (? = 0 OR p.Owner_ID = ?)
+		AND (? = 0 OR p.Category_ID = ?)
+		AND p.Creation_Date >= ? AND p.Creation_Date < ?
+		AND ` + visiblePostCondition + `
+	ORDER BY bm25(Post_Search, 10.0, 4.0, 1.0)
+	LIMIT ? OFFSET ?
+	`
+	rows, err := db.Query(sqlQuery, query, filter.AuthorID, filter.AuthorID, filter.CategoryID, filter.CategoryID, from, to, filter.ViewerID, filter.ViewerID, limit, offset)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return nil, ErrUnknown
+	}
+
+	var results []domain.SearchResult
+	for rows.Next() {
+		result := domain.SearchResult{Type: domain.SearchTypePosts}
+		var creationDate int64
+		err = rows.Scan(&result.ID, &result.AuthorID, &result.Title, &result.Snippet, &result.Rank, &creationDate)
+		if err != nil {
+			logging.LogUnexpectedRepositoryError(err)
+			return nil, ErrUnknown
+		}
+
+		result.PostID = result.ID
+		result.Title = markSearchHighlights(result.Title)
+		result.Snippet = markSearchHighlights(result.Snippet)
+		result.CreationDate = time.Unix(creationDate, 0)
+		results = append(results, result)
+	}
+
+	if len(results) == 0 {
+		logging.LogRepositoryError(ErrEmptySelection)
+		return nil, ErrEmptySelection
+	}
+
+	return results, nil
+}
+
+// Expects a valid FTS5 query, returns an slice of results from best to worst match, can return ErrEmptySelection
+func (repo sqliteSearchRepository) SearchComments(query string, filter domain.SearchFilter, limit, offset uint) ([]domain.SearchResult, error) {
+	db := repo.db
+
+	from, to := searchDateRange(filter)
+	sqlQuery := `
+	SELECT c.Comment_ID, c.Post_ID, c.User_ID,
+		snippet(Comment_Search, 0, char(2), char(3), '...', 24),
+		-bm25(Comment_Search),
+		c.Creation_Date
+	FROM Comment_Search
+	JOIN Comment c ON c.Comment_ID = Comment_Search.rowid
+	JOIN Post p ON p.Post_ID = c.Post_ID
+	WHERE Comment_Search MATCH ? AND c.Deleted_At IS NULL AND p.Deleted_At IS NULL
+		AND (? = 0 OR c.User_ID = ?)
+		AND (? = 0 OR p.Category_ID = ?)
+		AND c.Creation_Date >= ? AND c.Creation_Date < ?
+		AND ` + visiblePostCondition + `
+	ORDER BY bm25(Comment_Search)
+	LIMIT ? OFFSET ?
+	`
+	rows, err := db.Query(sqlQuery, query, filter.AuthorID, filter.AuthorID, filter.CategoryID, filter.CategoryID, from, to, filter.ViewerID, filter.ViewerID, limit, offset)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return nil, ErrUnknown
+	}
+
+	var results []domain.SearchResult
+	for rows.Next() {
+		result := domain.SearchResult{Type: domain.SearchTypeComments}
+		var creationDate int64
+		err = rows.Scan(&result.ID, &result.PostID, &result.AuthorID, &result.Snippet, &result.Rank, &creationDate)
+		if err != nil {
+			logging.LogUnexpectedRepositoryError(err)
+			return nil, ErrUnknown
+		}
+
+		result.Snippet = markSearchHighlights(result.Snippet)
+		result.CreationDate = time.Unix(creationDate, 0)
+		results = append(results, result)
+	}
+
+	if len(results) == 0 {
+		logging.LogRepositoryError(ErrEmptySelection)
+		return nil, ErrEmptySelection
+	}
+
+	return results, nil
+}
+
+// Expects a valid FTS5 query, returns an slice of results from best to worst match, can return ErrEmptySelection
+func (repo sqliteSearchRepository) SearchProfiles(query string, limit, offset uint) ([]domain.SearchResult, error) {
+	db := repo.db
+
+	sqlQuery := `
+	SELECT pr.User_ID,
+		highlight(Profile_Search, 0, char(2), char(3)),
+		highlight(Profile_Search, 1, char(2), char(3)),
+		-bm25(Profile_Search),
+		u.Registration_Date
+	FROM Profile_Search
+	JOIN Profile pr ON pr.User_ID = Profile_Search.rowid
+	JOIN User u ON u.User_ID = pr.User_ID
+	WHERE Profile_Search MATCH ?
+	ORDER BY bm25(Profile_Search)
+	LIMIT ? OFFSET ?
+	`
+	rows, err := db.Query(sqlQuery, query, limit, offset)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return nil, ErrUnknown
+	}
+
+	var results []domain.SearchResult
+	for rows.Next() {
+		result := domain.SearchResult{Type: domain.SearchTypeProfiles}
+		var registrationDate int64
+		err = rows.Scan(&result.ID, &result.Title, &result.Snippet, &result.Rank, &registrationDate)
+		if err != nil {
+			logging.LogUnexpectedRepositoryError(err)
+			return nil, ErrUnknown
+		}
+
+		result.AuthorID = result.ID
+		result.Title = markSearchHighlights(result.Title)
+		result.Snippet = markSearchHighlights(result.Snippet)
+		result.CreationDate = time.Unix(registrationDate, 0)
+		results = append(results, result)
+	}
+
+	if len(results) == 0 {
+		logging.LogRepositoryError(ErrEmptySelection)
+		return nil, ErrEmptySelection
+	}
+
+	return results, nil
+}
+
+// Returns the bounds of the filter as unix times, missing bounds don't restrict anything
+func searchDateRange(filter domain.SearchFilter) (int64, int64) {
+	var from, to int64 = 0, math.MaxInt64
+	if !filter.From.IsZero() {
+		from = filter.From.Unix()
+	}
+	if !filter.To.IsZero() {
+		to = filter.To.Unix()
+	}
+
+	return from, to
+}
+
+// Escapes the text and turns the highlight markers used in queries into <mark> tags
+func markSearchHighlights(text string) string {
+	replacer := strings.NewReplacer("\x02", "<mark>", "\x03", "</mark>")
+	return replacer.Replace(html.EscapeString(text))
+}
+
+func NewSQLiteSearchRepository(db *sql.DB) domain.SearchRepository {
+	return sqliteSearchRepository{db: db}
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/logging"
+)
+
+type sqliteTagRepository struct {
+	db *sql.DB
+}
+
+// Returns an slice of tags in use starting with prefix sorted by usage, can return ErrEmptySelection
+func (repo sqliteTagRepository) GetByPrefix(prefix string, amount uint) ([]domain.Tag, error) {
+	db := repo.db
+
+	var tags []domain.Tag
+	query := `
+	SELECT t.Name, COUNT(p.Post_ID) AS Post_Count
+	FROM Tag t
+	JOIN Post_Tags pt ON pt.Tag_ID = t.Tag_ID
+	JOIN Post p ON p.Post_ID = pt.Post_ID AND p.Deleted_At IS NULL
+	WHERE t.Name LIKE ? ESCAPE '\'
+	GROUP BY t.Tag_ID
+	ORDER BY Post_Count DESC, t.Name
+	LIMIT ?
+	`
+	escaper := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	rows, err := db.Query(query, escaper.Replace(prefix)+"%", amount)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return nil, ErrUnknown
+	}
+
+	for rows.Next() {
+		var tag domain.Tag
+		err = rows.Scan(&tag.Name, &tag.PostCount)
+		if err != nil {
+			logging.LogUnexpectedRepositoryError(err)
+			return nil, ErrUnknown
+		}
+
+		tags = append(tags, tag)
+	}
+
+	if len(tags) == 0 {
+		logging.LogRepositoryError(ErrEmptySelection)
+		return nil, ErrEmptySelection
+	}
+
+	return tags, nil
+}
+
+// Ranks tags by the posts and comments created on their posts after moment, returns an slice of tags, can return ErrEmptySelection
+func (repo sqliteTagRepository) GetTrendingAfter(moment time.Time, amount uint) ([]domain.Tag, error) {
+	db := repo.db
+
+	var tags []domain.Tag
+	momentInteger := moment.Unix()
+	query := `
+	SELECT Name, Post_Count, Recent_Posts + Recent_Comments AS Activity
+	FROM (
+		SELECT t.Name,
+			COUNT(p.Post_ID) AS Post_Count,
+			SUM(p.Creation_Date >= ?) AS Recent_Posts,
+			SUM((
+				SELECT COUNT(*) FROM Comment c
+				WHERE c.Post_ID = p.Post_ID AND c.Deleted_At IS NULL AND c.Creation_Date >= ?
+			)) AS Recent_Comments
+		FROM Tag t
+		JOIN Post_Tags pt ON pt.Tag_ID = t.Tag_ID
+		JOIN Post p ON p.Post_ID = pt.Post_ID AND p.Deleted_At IS NULL
+		GROUP BY t.Tag_ID
+	)
+	WHERE Activity > 0
+	ORDER BY Activity DESC, Name
+	LIMIT ?
+	`
+	rows, err := db.Query(query, momentInteger, momentInteger, amount)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return nil, ErrUnknown
+	}
+
+	for rows.Next() {
+		var tag domain.Tag
+		err = rows.Scan(&tag.Name, &tag.PostCount, &tag.Activity)
+		if err != nil {
+			logging.LogUnexpectedRepositoryError(err)
+			return nil, ErrUnknown
+		}
+
+		tags = append(tags, tag)
+	}
+
+	if len(tags) == 0 {
+		logging.LogRepositoryError(ErrEmptySelection)
+		return nil, ErrEmptySelection
+	}
+
+	return tags, nil
+}
+
+func NewSQLiteTagRepository(db *sql.DB) domain.TagRepository {
+	return sqliteTagRepository{db: db}
+}
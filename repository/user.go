@@ -76,14 +76,14 @@ func (repo sqliteUserRepository) GetByEmail(email string) (domain.User, error) {
 	db := repo.db
 
 	var user domain.User
-	var unixSeconds int64
+	var unixSeconds, suspendedUntil int64
 	query := `
-  SELECT User_ID, Email, Hashed_Password, Registration_Date
+  SELECT User_ID, Email, Hashed_Password, Registration_Date, Is_Moderator, COALESCE(Suspended_Until, 0)
   FROM User
   WHERE Email = ?
   `
 	row := db.QueryRow(query, email)
-	err := row.Scan(&user.ID, &user.Email, &user.HashedPassword, &unixSeconds)
+	err := row.Scan(&user.ID, &user.Email, &user.HashedPassword, &unixSeconds, &user.IsModerator, &suspendedUntil)
 	if err == sql.ErrNoRows {
 		logging.LogRepositoryError(ErrEmptySelection)
 		return domain.User{}, ErrEmptySelection
@@ -94,6 +94,9 @@ func (repo sqliteUserRepository) GetByEmail(email string) (domain.User, error) {
 	}
 
 	user.RegistrationDate = time.Unix(unixSeconds, 0)
+	if suspendedUntil != 0 {
+		user.SuspendedUntil = time.Unix(suspendedUntil, 0)
+	}
 
 	return user, nil
 }
@@ -103,14 +106,14 @@ func (repo sqliteUserRepository) GetByID(id uint) (domain.User, error) {
 	db := repo.db
 
 	var user domain.User
-	var unixSeconds int64
+	var unixSeconds, suspendedUntil int64
 	query := `
-  SELECT User_ID, Email, Hashed_Password, Registration_Date
+  SELECT User_ID, Email, Hashed_Password, Registration_Date, Is_Moderator, COALESCE(Suspended_Until, 0)
   FROM User
   WHERE User_ID = ?
   `
 	row := db.QueryRow(query, id)
-	err := row.Scan(&user.ID, &user.Email, &user.HashedPassword, &unixSeconds)
+	err := row.Scan(&user.ID, &user.Email, &user.HashedPassword, &unixSeconds, &user.IsModerator, &suspendedUntil)
 	if err == sql.ErrNoRows {
 		logging.LogRepositoryError(ErrEmptySelection)
 		return domain.User{}, ErrEmptySelection
@@ -121,6 +124,9 @@ func (repo sqliteUserRepository) GetByID(id uint) (domain.User, error) {
 	}
 
 	user.RegistrationDate = time.Unix(unixSeconds, 0)
+	if suspendedUntil != 0 {
+		user.SuspendedUntil = time.Unix(suspendedUntil, 0)
+	}
 
 	return user, nil
 }
@@ -183,6 +189,35 @@ func (repo sqliteUserRepository) UpdateHashedPassword(id uint, newHashedPassword
 	return nil
 }
 
+// Can return ErrNoRowsAffected
+func (repo sqliteUserRepository) Suspend(id uint, until time.Time) error {
+	db := repo.db
+
+	query := `
+  UPDATE User
+  SET Suspended_Until = ?
+  WHERE User_ID = ?
+  `
+	res, err := db.Exec(query, until.Unix(), id)
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	amountAffected, err := res.RowsAffected()
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return ErrUnknown
+	}
+
+	if amountAffected == 0 {
+		logging.LogRepositoryError(ErrNoRowsAffected)
+		return ErrNoRowsAffected
+	}
+
+	return nil
+}
+
 func NewSQLiteUserRepository(db *sql.DB) domain.UserRepository {
 	return sqliteUserRepository{
 		db: db,
@@ -0,0 +1,27 @@
+package repository
+
+import "strings"
+
+// Returns how the upvotes and downvotes of an entity change when a vote goes from previous to value
+func voteCountDeltas(previous, value int) (int, int) {
+	var upvotes, downvotes int
+	switch previous {
+	case 1:
+		upvotes--
+	case -1:
+		downvotes--
+	}
+	switch value {
+	case 1:
+		upvotes++
+	case -1:
+		downvotes++
+	}
+
+	return upvotes, downvotes
+}
+
+// Returns n comma separated placeholders for IN lists
+func sqlPlaceholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/logging"
+	"github.com/AlejandroJorge/forum-rest-api/repository"
+)
+
+type categoryServiceImpl struct {
+	repo     domain.CategoryRepository
+	userRepo domain.UserRepository
+}
+
+// Returns the ID of the created category, can return ErrIncorrectParameters, ErrNotAuthorized, ErrAlreadyExisting, ErrDependencyNotSatisfied
+// Only moderators can create categories
+func (serv categoryServiceImpl) Create(userId, parentId uint, name string) (uint, error) {
+	if userId == 0 || name == "" {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return 0, ErrIncorrectParameters
+	}
+
+	err := checkModerator(serv.userRepo, userId)
+	if err != nil {
+		return 0, err
+	}
+
+	if parentId != 0 {
+		_, err = serv.repo.GetByID(parentId)
+		if err == repository.ErrEmptySelection {
+			logging.LogDomainError(ErrDependencyNotSatisfied)
+			return 0, ErrDependencyNotSatisfied
+		}
+		if err != nil {
+			logging.LogUnexpectedDomainError(err)
+			return 0, ErrUnknown
+		}
+	}
+
+	id, err := serv.repo.Create(parentId, name)
+	if err == repository.ErrNoMatchingDependency {
+		logging.LogDomainError(ErrDependencyNotSatisfied)
+		return 0, ErrDependencyNotSatisfied
+	}
+	if err == repository.ErrRepeatedEntity {
+		logging.LogDomainError(ErrAlreadyExisting)
+		return 0, ErrAlreadyExisting
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return 0, ErrUnknown
+	}
+
+	return id, nil
+}
+
+// Returns a valid category, can return ErrIncorrectParameters, ErrNotExistingEntity
+func (serv categoryServiceImpl) GetByID(id uint) (domain.Category, error) {
+	if id == 0 {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return domain.Category{}, ErrIncorrectParameters
+	}
+
+	category, err := serv.repo.GetByID(id)
+	if err == repository.ErrEmptySelection {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return domain.Category{}, ErrNotExistingEntity
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return domain.Category{}, ErrUnknown
+	}
+
+	return category, nil
+}
+
+// Returns the trees of categories that aren't archived, can return ErrNotExistingEntity
+func (serv categoryServiceImpl) GetTree() ([]domain.CategoryTree, error) {
+	categories, err := serv.repo.GetAll()
+	if err == repository.ErrEmptySelection {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return nil, ErrNotExistingEntity
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return nil, ErrUnknown
+	}
+
+	var active []domain.Category
+	for _, category := range categories {
+		if !category.Archived {
+			active = append(active, category)
+		}
+	}
+
+	if len(active) == 0 {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return nil, ErrNotExistingEntity
+	}
+
+	return buildCategoryTrees(active), nil
+}
+
+// Only moderators can rename, can return ErrIncorrectParameters, ErrNotAuthorized, ErrNotExistingEntity, ErrAlreadyExisting
+func (serv categoryServiceImpl) UpdateName(userId, id uint, name string) error {
+	if userId == 0 || id == 0 || name == "" {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return ErrIncorrectParameters
+	}
+
+	err := checkModerator(serv.userRepo, userId)
+	if err != nil {
+		return err
+	}
+
+	err = serv.repo.UpdateName(id, name)
+	if err == repository.ErrNoRowsAffected {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return ErrNotExistingEntity
+	}
+	if err == repository.ErrRepeatedEntity {
+		logging.LogDomainError(ErrAlreadyExisting)
+		return ErrAlreadyExisting
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return ErrUnknown
+	}
+
+	return nil
+}
+
+// Only moderators can reorder, can return ErrIncorrectParameters, ErrNotAuthorized, ErrNotExistingEntity
+func (serv categoryServiceImpl) UpdatePosition(userId, id uint, position uint) error {
+	if userId == 0 || id == 0 || position == 0 {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return ErrIncorrectParameters
+	}
+
+	err := checkModerator(serv.userRepo, userId)
+	if err != nil {
+		return err
+	}
+
+	err = serv.repo.UpdatePosition(id, position)
+	if err == repository.ErrNoRowsAffected {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return ErrNotExistingEntity
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return ErrUnknown
+	}
+
+	return nil
+}
+
+// Only moderators can archive, can return ErrIncorrectParameters, ErrNotAuthorized, ErrNotExistingEntity
+func (serv categoryServiceImpl) Archive(userId, id uint) error {
+	return serv.updateArchived(userId, id, true)
+}
+
+// Only moderators can unarchive, can return ErrIncorrectParameters, ErrNotAuthorized, ErrNotExistingEntity
+func (serv categoryServiceImpl) Unarchive(userId, id uint) error {
+	return serv.updateArchived(userId, id, false)
+}
+
+func (serv categoryServiceImpl) updateArchived(userId, id uint, archived bool) error {
+	if userId == 0 || id == 0 {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return ErrIncorrectParameters
+	}
+
+	err := checkModerator(serv.userRepo, userId)
+	if err != nil {
+		return err
+	}
+
+	err = serv.repo.UpdateArchived(id, archived)
+	if err == repository.ErrNoRowsAffected {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return ErrNotExistingEntity
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return ErrUnknown
+	}
+
+	return nil
+}
+
+// Nests categories under their parents, subcategories of missing parents are left out
+func buildCategoryTrees(categories []domain.Category) []domain.CategoryTree {
+	children := make(map[uint][]domain.Category)
+	for _, c := range categories {
+		children[c.ParentID] = append(children[c.ParentID], c)
+	}
+
+	var build func(c domain.Category) domain.CategoryTree
+	build = func(c domain.Category) domain.CategoryTree {
+		tree := domain.CategoryTree{Category: c, Subcategories: []domain.CategoryTree{}}
+		for _, child := range children[c.CategoryID] {
+			tree.Subcategories = append(tree.Subcategories, build(child))
+		}
+		return tree
+	}
+
+	trees := make([]domain.CategoryTree, 0, len(children[0]))
+	for _, root := range children[0] {
+		trees = append(trees, build(root))
+	}
+
+	return trees
+}
+
+func NewCategoryService(repo domain.CategoryRepository, userRepo domain.UserRepository) domain.CategoryService {
+	return categoryServiceImpl{repo: repo, userRepo: userRepo}
+}
@@ -1,43 +1,100 @@
 package service
 
 import (
+	"sort"
+	"time"
+
+	"github.com/AlejandroJorge/forum-rest-api/config"
 	"github.com/AlejandroJorge/forum-rest-api/domain"
 	"github.com/AlejandroJorge/forum-rest-api/logging"
 	"github.com/AlejandroJorge/forum-rest-api/repository"
+	"github.com/AlejandroJorge/forum-rest-api/util"
 )
 
 type commentServiceImpl struct {
-	repo domain.CommentRepository
+	repo        domain.CommentRepository
+	userRepo    domain.UserRepository
+	postRepo    domain.PostRepository
+	profileRepo domain.ProfileRepository
+	notifier    notifier
+	events      domain.EventPublisher
 }
 
-// Can return ErrIncorrectParameters, ErrDependencyNotSatisfied
-func (serv commentServiceImpl) AddLike(userId uint, commentId uint) error {
-	if userId == 0 || commentId == 0 {
+// Returns the ID of the generated comment, parentID is optional, can return ErrIncorrectParameters, ErrDependencyNotSatisfied, ErrMaxDepthExceeded, ErrNotAuthorized, ErrSuspended, ErrLocked, ErrArchived
+// Fails when the post's owner blocked the user or is private and not followed by them
+// Notifies the author of the parent or the post and the mentioned profiles, publishes the comment to the post's topic
+func (serv commentServiceImpl) Create(userID, postID, parentID uint, content string) (uint, error) {
+	if userID == 0 || postID == 0 || content == "" {
 		logging.LogDomainError(ErrIncorrectParameters)
-		return ErrIncorrectParameters
+		return 0, ErrIncorrectParameters
 	}
 
-	err := serv.repo.AddLike(userId, commentId)
-	if err == repository.ErrNoMatchingDependency {
+	err := checkNotSuspended(serv.userRepo, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	var repliedID uint
+	if parentID != 0 {
+		parent, err := serv.repo.GetByID(parentID)
+		if err == repository.ErrEmptySelection {
+			logging.LogDomainError(ErrDependencyNotSatisfied)
+			return 0, ErrDependencyNotSatisfied
+		}
+		if err != nil {
+			logging.LogUnexpectedDomainError(err)
+			return 0, ErrUnknown
+		}
+
+		if parent.PostID != postID {
+			logging.LogDomainError(ErrIncorrectParameters)
+			return 0, ErrIncorrectParameters
+		}
+
+		if parent.Depth+1 > config.GetParams().MaxCommentDepth {
+			logging.LogDomainError(ErrMaxDepthExceeded)
+			return 0, ErrMaxDepthExceeded
+		}
+
+		repliedID = parent.UserID
+	}
+
+	post, err := serv.postRepo.GetByID(postID)
+	if err == repository.ErrEmptySelection {
 		logging.LogDomainError(ErrDependencyNotSatisfied)
-		return ErrDependencyNotSatisfied
+		return 0, ErrDependencyNotSatisfied
 	}
 	if err != nil {
 		logging.LogUnexpectedDomainError(err)
-		return ErrUnknown
+		return 0, ErrUnknown
 	}
 
-	return nil
-}
+	if post.Locked {
+		logging.LogDomainError(ErrLocked)
+		return 0, ErrLocked
+	}
 
-// Returns the ID of the generated comment, can return ErrIncorrectParameters, ErrDependencySatisfied
-func (serv commentServiceImpl) Create(userID, postID uint, content string) (uint, error) {
-	if userID == 0 || postID == 0 || content == "" {
-		logging.LogDomainError(ErrIncorrectParameters)
-		return 0, ErrIncorrectParameters
+	if post.Archived {
+		logging.LogDomainError(ErrArchived)
+		return 0, ErrArchived
 	}
 
-	id, err := serv.repo.Create(postID, userID, content)
+	blocked, err := serv.profileRepo.IsBlocked(post.OwnerID, userID)
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return 0, ErrUnknown
+	}
+	if blocked {
+		logging.LogDomainError(ErrNotAuthorized)
+		return 0, ErrNotAuthorized
+	}
+
+	err = checkVisible(serv.profileRepo, userID, post.OwnerID)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := serv.repo.Create(postID, userID, parentID, content)
 	if err == repository.ErrNoMatchingDependency {
 		logging.LogDomainError(ErrDependencyNotSatisfied)
 		return 0, ErrDependencyNotSatisfied
@@ -47,18 +104,31 @@ func (serv commentServiceImpl) Create(userID, postID uint, content string) (uint
 		return 0, ErrUnknown
 	}
 
+	if repliedID == 0 {
+		repliedID = post.OwnerID
+	}
+	serv.notifier.notify(domain.Notification{UserID: repliedID, ActorID: userID, Type: domain.NotificationReply, PostID: postID, CommentID: id})
+	serv.renderContent(userID, postID, id, content)
+
+	comment, err := serv.repo.GetByID(id)
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+	} else {
+		serv.events.Publish(domain.PostTopic(postID), domain.EventCommentCreated, comment)
+	}
+
 	return id, nil
 }
 
-// Can return ErrIncorrectParameters, ErrNotExistingEntity
-func (serv commentServiceImpl) Delete(id uint) error {
-	if id == 0 {
+// Only the author or a moderator can delete, can return ErrIncorrectParameters, ErrNotExistingEntity, ErrNotAuthorized
+func (serv commentServiceImpl) Delete(userId, id uint) error {
+	if userId == 0 || id == 0 {
 		logging.LogDomainError(ErrIncorrectParameters)
 		return ErrIncorrectParameters
 	}
 
-	err := serv.repo.Delete(id)
-	if err == repository.ErrNoRowsAffected {
+	comment, err := serv.repo.GetByID(id)
+	if err == repository.ErrEmptySelection {
 		logging.LogDomainError(ErrNotExistingEntity)
 		return ErrNotExistingEntity
 	}
@@ -67,17 +137,12 @@ func (serv commentServiceImpl) Delete(id uint) error {
 		return ErrUnknown
 	}
 
-	return nil
-}
-
-// Can return ErrIncorrectParameters, ErrNotExistingEntity
-func (serv commentServiceImpl) DeleteLike(userId uint, commentId uint) error {
-	if userId == 0 || commentId == 0 {
-		logging.LogDomainError(ErrIncorrectParameters)
-		return ErrIncorrectParameters
+	err = checkOwnerOrModerator(serv.userRepo, userId, comment.UserID)
+	if err != nil {
+		return err
 	}
 
-	err := serv.repo.DeleteLike(userId, commentId)
+	err = serv.repo.Delete(id)
 	if err == repository.ErrNoRowsAffected {
 		logging.LogDomainError(ErrNotExistingEntity)
 		return ErrNotExistingEntity
@@ -110,9 +175,22 @@ func (serv commentServiceImpl) GetByID(id uint) (domain.Comment, error) {
 	return comment, nil
 }
 
-// Returns a slice of valid comments, can return ErrIncorrectParameters, ErrNotExistingEntity
-func (serv commentServiceImpl) GetByPost(postID uint) ([]domain.Comment, error) {
-	if postID == 0 {
+// Returns a slice of valid comments in thread order, replies to the same comment are sorted by order, can return ErrIncorrectParameters, ErrNotExistingEntity
+func (serv commentServiceImpl) GetByPost(postID uint, order domain.CommentOrder) ([]domain.Comment, error) {
+	threads, err := serv.GetThreadsByPost(postID, order)
+	if err != nil {
+		return nil, err
+	}
+
+	return flattenCommentThreads(threads), nil
+}
+
+// Returns a slice of valid comment threads, replies to the same comment are sorted by order, can return ErrIncorrectParameters, ErrNotExistingEntity
+func (serv commentServiceImpl) GetThreadsByPost(postID uint, order domain.CommentOrder) ([]domain.CommentThread, error) {
+	if order == "" {
+		order = domain.CommentOrderOldest
+	}
+	if postID == 0 || !isValidCommentOrder(order) {
 		logging.LogDomainError(ErrIncorrectParameters)
 		return nil, ErrIncorrectParameters
 	}
@@ -127,17 +205,40 @@ func (serv commentServiceImpl) GetByPost(postID uint) ([]domain.Comment, error)
 		return nil, ErrUnknown
 	}
 
+	sortComments(comments, order)
+
+	return buildCommentThreads(comments), nil
+}
+
+// Returns a slice of valid comments replying directly to the comment, can return ErrIncorrectParameters, ErrNotExistingEntity
+func (serv commentServiceImpl) GetReplies(commentID uint) ([]domain.Comment, error) {
+	if commentID == 0 {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return nil, ErrIncorrectParameters
+	}
+
+	comments, err := serv.repo.GetReplies(commentID)
+	if err == repository.ErrEmptySelection {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return nil, ErrNotExistingEntity
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return nil, ErrUnknown
+	}
+
 	return comments, nil
 }
 
 // Returns a slice of valid comments, can return ErrIncorrectParameters, ErrNotExistingEntity
-func (serv commentServiceImpl) GetByUser(userID uint) ([]domain.Comment, error) {
-	if userID == 0 {
+// Only returns comments on posts the viewer can see, viewerId is 0 for anonymous viewers
+func (serv commentServiceImpl) GetByUser(viewerId, userId uint) ([]domain.Comment, error) {
+	if userId == 0 {
 		logging.LogDomainError(ErrIncorrectParameters)
 		return nil, ErrIncorrectParameters
 	}
 
-	comments, err := serv.repo.GetByUser(userID)
+	comments, err := serv.repo.GetByUser(viewerId, userId)
 	if err == repository.ErrEmptySelection {
 		logging.LogDomainError(ErrNotExistingEntity)
 		return nil, ErrNotExistingEntity
@@ -150,14 +251,94 @@ func (serv commentServiceImpl) GetByUser(userID uint) ([]domain.Comment, error)
 	return comments, nil
 }
 
-// Can return ErrIncorrectParameters, ErrNotExistingEntity
-func (serv commentServiceImpl) Update(id uint, updatedContent string) error {
-	if id == 0 || updatedContent == "" {
+// Only the author or a moderator can edit, can return ErrIncorrectParameters, ErrNotExistingEntity, ErrNotAuthorized, ErrArchived, ErrSuspended
+// Renders the content again and notifies newly mentioned profiles
+func (serv commentServiceImpl) Update(userId, id uint, updatedContent string) error {
+	if userId == 0 || id == 0 || updatedContent == "" {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return ErrIncorrectParameters
+	}
+
+	err := checkNotSuspended(serv.userRepo, userId)
+	if err != nil {
+		return err
+	}
+
+	comment, err := serv.repo.GetByID(id)
+	if err == repository.ErrEmptySelection {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return ErrNotExistingEntity
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return ErrUnknown
+	}
+
+	err = checkOwnerOrModerator(serv.userRepo, userId, comment.UserID)
+	if err != nil {
+		return err
+	}
+
+	err = checkPostNotArchived(serv.postRepo, comment.PostID)
+	if err != nil {
+		return err
+	}
+
+	err = serv.repo.UpdateContent(id, updatedContent)
+	if err == repository.ErrNoRowsAffected {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return ErrNotExistingEntity
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return ErrUnknown
+	}
+
+	serv.renderContent(comment.UserID, comment.PostID, id, updatedContent)
+
+	return nil
+}
+
+// Stores the content rendered as HTML and its mentions and hashtags, notifies the mentioned profiles, failing to store them doesn't fail the write
+func (serv commentServiceImpl) renderContent(actorID, postID, commentID uint, content string) {
+	contentHTML := util.RenderMarkdown(content)
+	entities := parseContentEntities(serv.profileRepo, content)
+	err := serv.repo.SetRendered(commentID, contentHTML, entities)
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+	}
+
+	serv.notifier.notifyMentions(actorID, postID, commentID, entities)
+}
+
+// Only the author or a moderator can restore, can return ErrIncorrectParameters, ErrNotExistingEntity, ErrNotAuthorized
+// Only moderators can restore comments removed by moderation
+func (serv commentServiceImpl) Restore(userId uint, commentId uint) error {
+	if userId == 0 || commentId == 0 {
 		logging.LogDomainError(ErrIncorrectParameters)
 		return ErrIncorrectParameters
 	}
 
-	err := serv.repo.UpdateContent(id, updatedContent)
+	comment, err := serv.repo.GetDeletedByID(commentId)
+	if err == repository.ErrEmptySelection {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return ErrNotExistingEntity
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return ErrUnknown
+	}
+
+	if comment.Moderation != "" {
+		err = checkModerator(serv.userRepo, userId)
+	} else {
+		err = checkOwnerOrModerator(serv.userRepo, userId, comment.UserID)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = serv.repo.Restore(commentId)
 	if err == repository.ErrNoRowsAffected {
 		logging.LogDomainError(ErrNotExistingEntity)
 		return ErrNotExistingEntity
@@ -170,6 +351,302 @@ func (serv commentServiceImpl) Update(id uint, updatedContent string) error {
 	return nil
 }
 
-func NewCommentService(repo domain.CommentRepository) domain.CommentService {
-	return commentServiceImpl{repo: repo}
+// Returns the amount of purged comments, can return ErrIncorrectParameters
+func (serv commentServiceImpl) PurgeDeleted(retention time.Duration) (uint, error) {
+	if retention < 0 {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return 0, ErrIncorrectParameters
+	}
+
+	amount, err := serv.repo.PurgeDeletedBefore(time.Now().Add(-retention))
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return 0, ErrUnknown
+	}
+
+	return amount, nil
+}
+
+// Returns a slice of revisions from oldest to newest, can return ErrIncorrectParameters, ErrNotExistingEntity
+func (serv commentServiceImpl) GetRevisions(commentID uint) ([]domain.CommentRevision, error) {
+	if commentID == 0 {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return nil, ErrIncorrectParameters
+	}
+
+	revisions, err := serv.repo.GetRevisions(commentID)
+	if err == repository.ErrEmptySelection {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return nil, ErrNotExistingEntity
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return nil, ErrUnknown
+	}
+
+	return revisions, nil
+}
+
+func isValidCommentOrder(order domain.CommentOrder) bool {
+	return util.MergeOR([]bool{
+		order == domain.CommentOrderOldest,
+		order == domain.CommentOrderNewest,
+		order == domain.CommentOrderTop,
+	})
+}
+
+// Sorts comments in place, ties are broken by creation order
+func sortComments(comments []domain.Comment, order domain.CommentOrder) {
+	sort.SliceStable(comments, func(i, j int) bool {
+		a, b := comments[i], comments[j]
+		switch order {
+		case domain.CommentOrderNewest:
+			if !a.CreatedAt.Equal(b.CreatedAt) {
+				return a.CreatedAt.After(b.CreatedAt)
+			}
+			return a.ID > b.ID
+		case domain.CommentOrderTop:
+			if a.Score != b.Score {
+				return a.Score > b.Score
+			}
+		}
+		return a.ID < b.ID
+	})
+}
+
+// Nests comments under their parents keeping their order, replies whose parent isn't present become roots
+func buildCommentThreads(comments []domain.Comment) []domain.CommentThread {
+	present := make(map[uint]bool, len(comments))
+	children := make(map[uint][]domain.Comment)
+	var roots []domain.Comment
+	for _, c := range comments {
+		present[c.ID] = true
+	}
+	for _, c := range comments {
+		if c.ParentID != 0 && present[c.ParentID] {
+			children[c.ParentID] = append(children[c.ParentID], c)
+		} else {
+			roots = append(roots, c)
+		}
+	}
+
+	var build func(c domain.Comment) domain.CommentThread
+	build = func(c domain.Comment) domain.CommentThread {
+		thread := domain.CommentThread{Comment: c, Replies: []domain.CommentThread{}}
+		for _, child := range children[c.ID] {
+			thread.Replies = append(thread.Replies, build(child))
+		}
+		return thread
+	}
+
+	threads := make([]domain.CommentThread, 0, len(roots))
+	for _, root := range roots {
+		threads = append(threads, build(root))
+	}
+
+	return threads
+}
+
+// Lists every comment right before its replies
+func flattenCommentThreads(threads []domain.CommentThread) []domain.Comment {
+	var comments []domain.Comment
+	for _, thread := range threads {
+		comments = append(comments, thread.Comment)
+		comments = append(comments, flattenCommentThreads(thread.Replies)...)
+	}
+
+	return comments
+}
+
+// Value is 1 or -1 and 0 removes the vote, can return ErrIncorrectParameters, ErrNotExistingEntity, ErrDependencyNotSatisfied, ErrNotAuthorized, ErrArchived, ErrSuspended
+// Upvotes notify the author, the new counts are published to the post's topic
+func (serv commentServiceImpl) Vote(userId uint, commentId uint, value int) error {
+	if userId == 0 || commentId == 0 || !isValidVote(value) {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return ErrIncorrectParameters
+	}
+
+	err := checkNotSuspended(serv.userRepo, userId)
+	if err != nil {
+		return err
+	}
+
+	comment, err := serv.repo.GetByID(commentId)
+	if err == repository.ErrEmptySelection {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return ErrNotExistingEntity
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return ErrUnknown
+	}
+
+	err = checkPostNotArchived(serv.postRepo, comment.PostID)
+	if err != nil {
+		return err
+	}
+
+	err = serv.CheckVisible(userId, comment.PostID)
+	if err != nil {
+		return err
+	}
+
+	err = serv.repo.Vote(userId, commentId, value)
+	if err == repository.ErrNoMatchingDependency {
+		logging.LogDomainError(ErrDependencyNotSatisfied)
+		return ErrDependencyNotSatisfied
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return ErrUnknown
+	}
+
+	if value > 0 {
+		serv.notifier.notify(domain.Notification{UserID: comment.UserID, ActorID: userId, Type: domain.NotificationLike, PostID: comment.PostID, CommentID: commentId})
+	}
+
+	comment, err = serv.repo.GetByID(commentId)
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+	} else {
+		counts := domain.VoteCounts{PostID: comment.PostID, CommentID: commentId, Score: comment.Score, Upvotes: comment.Upvotes, Downvotes: comment.Downvotes}
+		serv.events.Publish(domain.PostTopic(comment.PostID), domain.EventCommentVoted, counts)
+	}
+
+	return nil
+}
+
+// Fills the viewer fields of the comments for the user, can return ErrIncorrectParameters
+func (serv commentServiceImpl) AddViewer(viewerId uint, comments []domain.Comment) error {
+	if viewerId == 0 {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return ErrIncorrectParameters
+	}
+	if len(comments) == 0 {
+		return nil
+	}
+
+	commentIDs := make([]uint, len(comments))
+	for i, comment := range comments {
+		commentIDs[i] = comment.ID
+	}
+
+	viewers, err := serv.repo.GetViewerStates(viewerId, commentIDs)
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return ErrUnknown
+	}
+
+	for i := range comments {
+		viewer := viewers[comments[i].ID]
+		comments[i].Viewer = &viewer
+	}
+
+	return nil
+}
+
+// Fills the viewer fields of the comments and every nested reply for the user, can return ErrIncorrectParameters
+func (serv commentServiceImpl) AddViewerToThreads(viewerId uint, threads []domain.CommentThread) error {
+	comments := flattenCommentThreads(threads)
+	err := serv.AddViewer(viewerId, comments)
+	if err != nil {
+		return err
+	}
+
+	viewers := make(map[uint]*domain.Viewer, len(comments))
+	for _, comment := range comments {
+		viewers[comment.ID] = comment.Viewer
+	}
+
+	var apply func(threads []domain.CommentThread)
+	apply = func(threads []domain.CommentThread) {
+		for i := range threads {
+			threads[i].Viewer = viewers[threads[i].ID]
+			apply(threads[i].Replies)
+		}
+	}
+	apply(threads)
+
+	return nil
+}
+
+// Private profiles' posts and their comments are only visible to their approved followers, viewerId is 0 for anonymous viewers
+// Can return ErrIncorrectParameters, ErrNotExistingEntity, ErrNotAuthorized
+func (serv commentServiceImpl) CheckVisible(viewerId, postId uint) error {
+	if postId == 0 {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return ErrIncorrectParameters
+	}
+
+	post, err := serv.postRepo.GetByID(postId)
+	if err == repository.ErrEmptySelection {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return ErrNotExistingEntity
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return ErrUnknown
+	}
+
+	return checkVisible(serv.profileRepo, viewerId, post.OwnerID)
+}
+
+// Returns the comments without the ones by profiles the user muted, can return ErrIncorrectParameters
+func (serv commentServiceImpl) HideMuted(viewerId uint, comments []domain.Comment) ([]domain.Comment, error) {
+	if viewerId == 0 {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return nil, ErrIncorrectParameters
+	}
+
+	muted, err := mutedProfiles(serv.profileRepo, viewerId)
+	if err != nil {
+		return nil, err
+	}
+	if len(muted) == 0 {
+		return comments, nil
+	}
+
+	visible := make([]domain.Comment, 0, len(comments))
+	for _, comment := range comments {
+		if !muted[comment.UserID] {
+			visible = append(visible, comment)
+		}
+	}
+
+	return visible, nil
+}
+
+// Returns the threads without the comments by profiles the user muted along with their replies, can return ErrIncorrectParameters
+func (serv commentServiceImpl) HideMutedInThreads(viewerId uint, threads []domain.CommentThread) ([]domain.CommentThread, error) {
+	if viewerId == 0 {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return nil, ErrIncorrectParameters
+	}
+
+	muted, err := mutedProfiles(serv.profileRepo, viewerId)
+	if err != nil {
+		return nil, err
+	}
+	if len(muted) == 0 {
+		return threads, nil
+	}
+
+	var hide func(threads []domain.CommentThread) []domain.CommentThread
+	hide = func(threads []domain.CommentThread) []domain.CommentThread {
+		visible := make([]domain.CommentThread, 0, len(threads))
+		for _, thread := range threads {
+			if muted[thread.UserID] {
+				continue
+			}
+			thread.Replies = hide(thread.Replies)
+			visible = append(visible, thread)
+		}
+		return visible
+	}
+
+	return hide(threads), nil
+}
+
+func NewCommentService(repo domain.CommentRepository, userRepo domain.UserRepository, postRepo domain.PostRepository, profileRepo domain.ProfileRepository, notificationRepo domain.NotificationRepository, events domain.EventPublisher) domain.CommentService {
+	return commentServiceImpl{repo: repo, userRepo: userRepo, postRepo: postRepo, profileRepo: profileRepo, notifier: notifier{repo: notificationRepo, profileRepo: profileRepo, events: events}, events: events}
 }
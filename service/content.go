@@ -0,0 +1,44 @@
+package service
+
+import (
+	"sort"
+
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/logging"
+	"github.com/AlejandroJorge/forum-rest-api/repository"
+	"github.com/AlejandroJorge/forum-rest-api/util"
+)
+
+// Returns the mentions and hashtags of the content by offset, mentions of missing profiles are left out
+func parseContentEntities(profileRepo domain.ProfileRepository, content string) []domain.ContentEntity {
+	entities := []domain.ContentEntity{}
+
+	userIDs := make(map[string]uint)
+	for _, match := range util.FindMentions(content) {
+		tagName := match.Text[1:]
+		userID, ok := userIDs[tagName]
+		if !ok {
+			profile, err := profileRepo.GetByTagName(tagName)
+			if err != nil && err != repository.ErrEmptySelection {
+				logging.LogUnexpectedDomainError(err)
+			}
+			userID = profile.UserID
+			userIDs[tagName] = userID
+		}
+		if userID == 0 {
+			continue
+		}
+
+		entities = append(entities, domain.ContentEntity{Type: domain.ContentEntityMention, Text: match.Text, Offset: match.Offset, Length: match.Length, UserID: userID})
+	}
+
+	for _, match := range util.FindHashtags(content) {
+		entities = append(entities, domain.ContentEntity{Type: domain.ContentEntityHashtag, Text: match.Text, Offset: match.Offset, Length: match.Length})
+	}
+
+	sort.Slice(entities, func(i, j int) bool {
+		return entities[i].Offset < entities[j].Offset
+	})
+
+	return entities
+}
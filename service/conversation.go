@@ -0,0 +1,227 @@
+package service
+
+import (
+	"time"
+
+	"github.com/AlejandroJorge/forum-rest-api/config"
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/logging"
+	"github.com/AlejandroJorge/forum-rest-api/repository"
+)
+
+type conversationServiceImpl struct {
+	repo        domain.ConversationRepository
+	userRepo    domain.UserRepository
+	profileRepo domain.ProfileRepository
+	events      domain.EventPublisher
+}
+
+// Returns the ID of the conversation, can return ErrIncorrectParameters, ErrDependencyNotSatisfied, ErrNotAuthorized, ErrSuspended
+// Starting one with a single profile again returns the same one
+// Profiles that blocked the creator or were blocked by it can't be added
+func (serv conversationServiceImpl) Start(creatorID uint, memberIDs []uint) (uint, error) {
+	if creatorID == 0 || len(memberIDs) == 0 {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return 0, ErrIncorrectParameters
+	}
+
+	err := checkNotSuspended(serv.userRepo, creatorID)
+	if err != nil {
+		return 0, err
+	}
+
+	seen := map[uint]bool{creatorID: true}
+	var members []uint
+	for _, memberID := range memberIDs {
+		if memberID == 0 {
+			logging.LogDomainError(ErrIncorrectParameters)
+			return 0, ErrIncorrectParameters
+		}
+		if seen[memberID] {
+			continue
+		}
+		seen[memberID] = true
+		members = append(members, memberID)
+	}
+	if len(members) == 0 || uint(len(members)+1) > config.GetParams().MaxConversationMembers {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return 0, ErrIncorrectParameters
+	}
+
+	for _, memberID := range members {
+		blocked, err := isBlockedBetween(serv.profileRepo, memberID, creatorID)
+		if err != nil {
+			return 0, err
+		}
+		if blocked {
+			logging.LogDomainError(ErrNotAuthorized)
+			return 0, ErrNotAuthorized
+		}
+	}
+
+	id, err := serv.repo.Create(creatorID, members)
+	if err == repository.ErrNoMatchingDependency {
+		logging.LogDomainError(ErrDependencyNotSatisfied)
+		return 0, ErrDependencyNotSatisfied
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return 0, ErrUnknown
+	}
+
+	return id, nil
+}
+
+// Returns a valid conversation the user is a member of, can return ErrIncorrectParameters, ErrNotExistingEntity
+func (serv conversationServiceImpl) GetByID(userID, conversationID uint) (domain.Conversation, error) {
+	if userID == 0 || conversationID == 0 {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return domain.Conversation{}, ErrIncorrectParameters
+	}
+
+	return serv.getAsMember(userID, conversationID)
+}
+
+// Returns a page of the user's conversations by latest message, can return ErrIncorrectParameters
+func (serv conversationServiceImpl) GetByUser(userID uint, page uint) ([]domain.Conversation, error) {
+	if userID == 0 || page == 0 {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return nil, ErrIncorrectParameters
+	}
+
+	pageSize := config.GetParams().PageSize
+	conversations, err := serv.repo.GetByUser(userID, pageSize, (page-1)*pageSize)
+	if err != nil && err != repository.ErrEmptySelection {
+		logging.LogUnexpectedDomainError(err)
+		return nil, ErrUnknown
+	}
+	if conversations == nil {
+		conversations = []domain.Conversation{}
+	}
+
+	return conversations, nil
+}
+
+// Returns the ID of the sent message, can return ErrIncorrectParameters, ErrNotExistingEntity, ErrNotAuthorized, ErrSuspended
+// Publishes the message to the members, one-to-one messages fail when either profile blocked the other
+func (serv conversationServiceImpl) Send(senderID, conversationID uint, content string) (uint, error) {
+	if senderID == 0 || conversationID == 0 || content == "" {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return 0, ErrIncorrectParameters
+	}
+
+	err := checkNotSuspended(serv.userRepo, senderID)
+	if err != nil {
+		return 0, err
+	}
+
+	conversation, err := serv.getAsMember(senderID, conversationID)
+	if err != nil {
+		return 0, err
+	}
+
+	if !conversation.IsGroup {
+		for _, member := range conversation.Members {
+			if member.UserID == senderID {
+				continue
+			}
+
+			blocked, err := isBlockedBetween(serv.profileRepo, member.UserID, senderID)
+			if err != nil {
+				return 0, err
+			}
+			if blocked {
+				logging.LogDomainError(ErrNotAuthorized)
+				return 0, ErrNotAuthorized
+			}
+		}
+	}
+
+	id, err := serv.repo.CreateMessage(conversationID, senderID, content)
+	if err == repository.ErrNoMatchingDependency {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return 0, ErrNotExistingEntity
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return 0, ErrUnknown
+	}
+
+	message := domain.Message{ID: id, ConversationID: conversationID, SenderID: senderID, Content: content, CreatedAt: time.Now()}
+	for _, member := range conversation.Members {
+		serv.events.Publish(domain.NotificationsTopic(member.UserID), domain.EventMessageCreated, message)
+	}
+
+	return id, nil
+}
+
+// Returns a page of messages from newest to oldest, can return ErrIncorrectParameters, ErrNotExistingEntity
+func (serv conversationServiceImpl) GetMessages(userID, conversationID uint, page uint) ([]domain.Message, error) {
+	if userID == 0 || conversationID == 0 || page == 0 {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return nil, ErrIncorrectParameters
+	}
+
+	_, err := serv.getAsMember(userID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	pageSize := config.GetParams().PageSize
+	messages, err := serv.repo.GetMessages(conversationID, pageSize, (page-1)*pageSize)
+	if err != nil && err != repository.ErrEmptySelection {
+		logging.LogUnexpectedDomainError(err)
+		return nil, ErrUnknown
+	}
+	if messages == nil {
+		messages = []domain.Message{}
+	}
+
+	return messages, nil
+}
+
+// Marks every message up to messageID as read, 0 reads up to the latest one, can return ErrIncorrectParameters, ErrNotExistingEntity
+func (serv conversationServiceImpl) MarkRead(userID, conversationID, messageID uint) error {
+	if userID == 0 || conversationID == 0 {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return ErrIncorrectParameters
+	}
+
+	err := serv.repo.MarkRead(conversationID, userID, messageID)
+	if err == repository.ErrNoRowsAffected {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return ErrNotExistingEntity
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return ErrUnknown
+	}
+
+	return nil
+}
+
+// Conversations the user isn't a member of are reported as not existing
+func (serv conversationServiceImpl) getAsMember(userID, conversationID uint) (domain.Conversation, error) {
+	conversation, err := serv.repo.GetByID(conversationID)
+	if err == repository.ErrEmptySelection {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return domain.Conversation{}, ErrNotExistingEntity
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return domain.Conversation{}, ErrUnknown
+	}
+
+	for _, member := range conversation.Members {
+		if member.UserID == userID {
+			return conversation, nil
+		}
+	}
+
+	logging.LogDomainError(ErrNotExistingEntity)
+	return domain.Conversation{}, ErrNotExistingEntity
+}
+
+func NewConversationService(repo domain.ConversationRepository, userRepo domain.UserRepository, profileRepo domain.ProfileRepository, events domain.EventPublisher) domain.ConversationService {
+	return conversationServiceImpl{repo: repo, userRepo: userRepo, profileRepo: profileRepo, events: events}
+}
@@ -0,0 +1,25 @@
+package service
+
+import (
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/logging"
+)
+
+type counterServiceImpl struct {
+	repo domain.CounterRepository
+}
+
+// Repairs every counter that drifted, returns how many rows were repaired
+func (serv counterServiceImpl) Reconcile() (domain.CounterDrift, error) {
+	drift, err := serv.repo.Reconcile()
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return domain.CounterDrift{}, ErrUnknown
+	}
+
+	return drift, nil
+}
+
+func NewCounterService(repo domain.CounterRepository) domain.CounterService {
+	return counterServiceImpl{repo: repo}
+}
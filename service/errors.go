@@ -19,3 +19,17 @@ var ErrNotValidCredentials = errors.New("Credentials are invalid")
 var ErrDependencyNotSatisfied = errors.New("Dependency couldn't be satisfied")
 
 var ErrProfileExistsOrTagNameIsRepeated = errors.New("Profile for this user already exists or tagname is already registered")
+
+var ErrNotAuthorized = errors.New("The user isn't allowed to perform this action")
+
+var ErrMaxDepthExceeded = errors.New("The reply is nested deeper than allowed")
+
+var ErrArchived = errors.New("The entity is archived and doesn't accept changes")
+
+var ErrLocked = errors.New("The post is locked and doesn't accept comments")
+
+var ErrTooLarge = errors.New("The upload is larger than allowed")
+
+var ErrUnsupportedMedia = errors.New("The upload isn't a supported image")
+
+var ErrSuspended = errors.New("The user is suspended by a moderator")
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+)
+
+// How far back and how many posts the ranked feed considers
+const (
+	rankedFeedWindow     = 7 * 24 * time.Hour
+	rankedFeedCandidates = 500
+)
+
+// Cursors are opaque to clients, the latest feed points at the last post seen and the ranked feed at an offset
+func encodeLatestFeedCursor(post domain.Post) string {
+	raw := fmt.Sprintf("latest:%d:%d", post.CreationDate.Unix(), post.PostID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeLatestFeedCursor(cursor string) (time.Time, uint, bool) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, 0, false
+	}
+
+	var creationDate int64
+	var postID uint
+	_, err = fmt.Sscanf(string(raw), "latest:%d:%d", &creationDate, &postID)
+	if err != nil || postID == 0 {
+		return time.Time{}, 0, false
+	}
+
+	return time.Unix(creationDate, 0), postID, true
+}
+
+func encodeRankedFeedCursor(offset uint) string {
+	raw := fmt.Sprintf("ranked:%d", offset)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeRankedFeedCursor(cursor string) (uint, bool) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, false
+	}
+
+	var offset uint
+	_, err = fmt.Sscanf(string(raw), "ranked:%d", &offset)
+	if err != nil {
+		return 0, false
+	}
+
+	return offset, true
+}
+
+// Sorts posts by score decayed with age so fresh posts can outrank older popular ones
+func sortPostsByRecencyAndScore(posts []domain.Post, now time.Time) {
+	score := func(post domain.Post) float64 {
+		ageHours := now.Sub(post.CreationDate).Hours()
+		if ageHours < 0 {
+			ageHours = 0
+		}
+		return float64(post.Score+1) / math.Pow(ageHours+2, 1.5)
+	}
+
+	sort.SliceStable(posts, func(i, j int) bool {
+		return score(posts[i]) > score(posts[j])
+	})
+}
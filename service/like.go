@@ -0,0 +1,52 @@
+package service
+
+import (
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/logging"
+	"github.com/AlejandroJorge/forum-rest-api/repository"
+)
+
+type likeServiceImpl struct {
+	postRepo    domain.PostRepository
+	commentRepo domain.CommentRepository
+}
+
+// Returns what the user has upvoted, can return ErrIncorrectParameters, ErrNotExistingEntity
+// Only returns what the viewer can see, viewerId is 0 for anonymous viewers
+func (serv likeServiceImpl) GetByUser(viewerId, userId uint) (domain.UserLikes, error) {
+	if userId == 0 {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return domain.UserLikes{}, ErrIncorrectParameters
+	}
+
+	posts, err := serv.postRepo.GetLikedBy(viewerId, userId)
+	if err != nil && err != repository.ErrEmptySelection {
+		logging.LogUnexpectedDomainError(err)
+		return domain.UserLikes{}, ErrUnknown
+	}
+
+	comments, err := serv.commentRepo.GetLikedBy(viewerId, userId)
+	if err != nil && err != repository.ErrEmptySelection {
+		logging.LogUnexpectedDomainError(err)
+		return domain.UserLikes{}, ErrUnknown
+	}
+
+	if len(posts) == 0 && len(comments) == 0 {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return domain.UserLikes{}, ErrNotExistingEntity
+	}
+
+	likes := domain.UserLikes{Posts: posts, Comments: comments}
+	if likes.Posts == nil {
+		likes.Posts = []domain.Post{}
+	}
+	if likes.Comments == nil {
+		likes.Comments = []domain.Comment{}
+	}
+
+	return likes, nil
+}
+
+func NewLikeService(postRepo domain.PostRepository, commentRepo domain.CommentRepository) domain.LikeService {
+	return likeServiceImpl{postRepo: postRepo, commentRepo: commentRepo}
+}
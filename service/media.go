@@ -0,0 +1,194 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"path"
+
+	"github.com/AlejandroJorge/forum-rest-api/config"
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/logging"
+	"github.com/AlejandroJorge/forum-rest-api/repository"
+	"github.com/AlejandroJorge/forum-rest-api/storage"
+	"github.com/AlejandroJorge/forum-rest-api/util"
+)
+
+// Decoding allocates every pixel, larger images are refused before that
+const maxImagePixels = 40_000_000
+
+// Only these sniffed types are accepted, they have to decode as the same format
+var supportedImageFormats = map[string]string{
+	"image/jpeg": "jpeg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+}
+
+var mediaExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+}
+
+type mediaServiceImpl struct {
+	repo  domain.MediaRepository
+	store domain.BlobStore
+}
+
+// Returns the stored media, can return ErrIncorrectParameters, ErrTooLarge, ErrUnsupportedMedia
+// Re-encodes the image and stores it with a thumbnail under content-addressed keys
+// Uploading the same image again returns the same media
+func (serv mediaServiceImpl) Upload(ownerID uint, data io.Reader) (domain.Media, error) {
+	if ownerID == 0 || data == nil {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return domain.Media{}, ErrIncorrectParameters
+	}
+
+	maxBytes := int64(config.GetParams().MaxUploadBytes)
+	upload, err := io.ReadAll(io.LimitReader(data, maxBytes+1))
+	if err != nil {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return domain.Media{}, ErrIncorrectParameters
+	}
+	if int64(len(upload)) > maxBytes {
+		logging.LogDomainError(ErrTooLarge)
+		return domain.Media{}, ErrTooLarge
+	}
+	if len(upload) == 0 {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return domain.Media{}, ErrIncorrectParameters
+	}
+
+	sniffedFormat, ok := supportedImageFormats[http.DetectContentType(upload)]
+	if !ok {
+		logging.LogDomainError(ErrUnsupportedMedia)
+		return domain.Media{}, ErrUnsupportedMedia
+	}
+
+	img, format, err := util.DecodeImage(upload, maxImagePixels)
+	if err == util.ErrImageTooLarge {
+		logging.LogDomainError(ErrTooLarge)
+		return domain.Media{}, ErrTooLarge
+	}
+	if err != nil || format != sniffedFormat {
+		logging.LogDomainError(ErrUnsupportedMedia)
+		return domain.Media{}, ErrUnsupportedMedia
+	}
+
+	encoded, contentType, err := util.EncodeImage(img, format)
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return domain.Media{}, ErrUnknown
+	}
+
+	thumbnail, thumbnailType, err := util.EncodeImage(util.ResizeImage(img, config.GetParams().ThumbnailSize), format)
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return domain.Media{}, ErrUnknown
+	}
+
+	key, err := serv.put(encoded, contentType)
+	if err != nil {
+		return domain.Media{}, err
+	}
+
+	thumbnailKey, err := serv.put(thumbnail, thumbnailType)
+	if err != nil {
+		return domain.Media{}, err
+	}
+
+	bounds := img.Bounds()
+	id, err := serv.repo.Create(ownerID, key, thumbnailKey, contentType, uint(len(encoded)), uint(bounds.Dx()), uint(bounds.Dy()))
+	if err == repository.ErrRepeatedEntity {
+		media, err := serv.repo.GetByKey(ownerID, key)
+		if err != nil {
+			logging.LogUnexpectedDomainError(err)
+			return domain.Media{}, ErrUnknown
+		}
+		return media, nil
+	}
+	if err == repository.ErrNoMatchingDependency {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return domain.Media{}, ErrIncorrectParameters
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return domain.Media{}, ErrUnknown
+	}
+
+	media, err := serv.repo.GetByID(id)
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return domain.Media{}, ErrUnknown
+	}
+
+	return media, nil
+}
+
+// Returns a valid media, can return ErrIncorrectParameters, ErrNotExistingEntity
+func (serv mediaServiceImpl) GetByID(id uint) (domain.Media, error) {
+	if id == 0 {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return domain.Media{}, ErrIncorrectParameters
+	}
+
+	media, err := serv.repo.GetByID(id)
+	if err == repository.ErrEmptySelection {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return domain.Media{}, ErrNotExistingEntity
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return domain.Media{}, ErrUnknown
+	}
+
+	return media, nil
+}
+
+// Returns the stored data and its content type, the caller closes it, can return ErrIncorrectParameters, ErrNotExistingEntity
+func (serv mediaServiceImpl) Open(key string) (io.ReadCloser, string, error) {
+	var contentType string
+	for mediaType, extension := range mediaExtensions {
+		if path.Ext(key) == extension {
+			contentType = mediaType
+		}
+	}
+	if contentType == "" {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return nil, "", ErrIncorrectParameters
+	}
+
+	blob, err := serv.store.Get(key)
+	if err == storage.ErrBlobNotFound {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return nil, "", ErrNotExistingEntity
+	}
+	if err == storage.ErrInvalidKey {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return nil, "", ErrIncorrectParameters
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return nil, "", ErrUnknown
+	}
+
+	return blob, contentType, nil
+}
+
+// Returns the key of the data, the hash of its content, storing the same content twice is harmless
+func (serv mediaServiceImpl) put(data []byte, contentType string) (string, error) {
+	sum := sha256.Sum256(data)
+	key := hex.EncodeToString(sum[:]) + mediaExtensions[contentType]
+
+	err := serv.store.Put(key, contentType, data)
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return "", ErrUnknown
+	}
+
+	return key, nil
+}
+
+func NewMediaService(repo domain.MediaRepository, store domain.BlobStore) domain.MediaService {
+	return mediaServiceImpl{repo: repo, store: store}
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/logging"
+	"github.com/AlejandroJorge/forum-rest-api/repository"
+)
+
+type mentionServiceImpl struct {
+	profileRepo domain.ProfileRepository
+	postRepo    domain.PostRepository
+	commentRepo domain.CommentRepository
+}
+
+// Returns what mentions the profile from newest to oldest, can return ErrIncorrectParameters, ErrNotExistingEntity
+// Only returns what the viewer can see, viewerId is 0 for anonymous viewers
+func (serv mentionServiceImpl) GetByTagName(viewerId uint, tagName string) (domain.ProfileMentions, error) {
+	if tagName == "" {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return domain.ProfileMentions{}, ErrIncorrectParameters
+	}
+
+	profile, err := serv.profileRepo.GetByTagName(tagName)
+	if err == repository.ErrEmptySelection {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return domain.ProfileMentions{}, ErrNotExistingEntity
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return domain.ProfileMentions{}, ErrUnknown
+	}
+
+	posts, err := serv.postRepo.GetByMention(viewerId, profile.UserID)
+	if err != nil && err != repository.ErrEmptySelection {
+		logging.LogUnexpectedDomainError(err)
+		return domain.ProfileMentions{}, ErrUnknown
+	}
+
+	comments, err := serv.commentRepo.GetByMention(viewerId, profile.UserID)
+	if err != nil && err != repository.ErrEmptySelection {
+		logging.LogUnexpectedDomainError(err)
+		return domain.ProfileMentions{}, ErrUnknown
+	}
+
+	mentions := domain.ProfileMentions{Posts: posts, Comments: comments}
+	if mentions.Posts == nil {
+		mentions.Posts = []domain.Post{}
+	}
+	if mentions.Comments == nil {
+		mentions.Comments = []domain.Comment{}
+	}
+
+	return mentions, nil
+}
+
+func NewMentionService(profileRepo domain.ProfileRepository, postRepo domain.PostRepository, commentRepo domain.CommentRepository) domain.MentionService {
+	return mentionServiceImpl{profileRepo: profileRepo, postRepo: postRepo, commentRepo: commentRepo}
+}
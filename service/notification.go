@@ -0,0 +1,169 @@
+package service
+
+import (
+	"time"
+
+	"github.com/AlejandroJorge/forum-rest-api/config"
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/logging"
+	"github.com/AlejandroJorge/forum-rest-api/repository"
+)
+
+type notificationServiceImpl struct {
+	repo domain.NotificationRepository
+}
+
+// Returns a page of notifications from newest to oldest with the unread count, can return ErrIncorrectParameters
+func (serv notificationServiceImpl) GetByUser(userID uint, page uint) (domain.NotificationPage, error) {
+	if userID == 0 || page == 0 {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return domain.NotificationPage{}, ErrIncorrectParameters
+	}
+
+	pageSize := config.GetParams().PageSize
+	notifications, err := serv.repo.GetByUser(userID, pageSize, (page-1)*pageSize)
+	if err != nil && err != repository.ErrEmptySelection {
+		logging.LogUnexpectedDomainError(err)
+		return domain.NotificationPage{}, ErrUnknown
+	}
+	if notifications == nil {
+		notifications = []domain.Notification{}
+	}
+
+	unread, err := serv.repo.CountUnread(userID)
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return domain.NotificationPage{}, ErrUnknown
+	}
+
+	return domain.NotificationPage{Unread: unread, Notifications: notifications}, nil
+}
+
+// Can return ErrIncorrectParameters, ErrNotExistingEntity
+func (serv notificationServiceImpl) MarkRead(userID, id uint) error {
+	if userID == 0 || id == 0 {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return ErrIncorrectParameters
+	}
+
+	err := serv.repo.MarkRead(userID, id)
+	if err == repository.ErrNoRowsAffected {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return ErrNotExistingEntity
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return ErrUnknown
+	}
+
+	return nil
+}
+
+// Returns how many notifications were marked as read, can return ErrIncorrectParameters
+func (serv notificationServiceImpl) MarkAllRead(userID uint) (uint, error) {
+	if userID == 0 {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return 0, ErrIncorrectParameters
+	}
+
+	marked, err := serv.repo.MarkAllRead(userID)
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return 0, ErrUnknown
+	}
+
+	return marked, nil
+}
+
+// Can return ErrIncorrectParameters
+func (serv notificationServiceImpl) GetPreferences(userID uint) (domain.NotificationPreferences, error) {
+	if userID == 0 {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return domain.NotificationPreferences{}, ErrIncorrectParameters
+	}
+
+	preferences, err := serv.repo.GetPreferences(userID)
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return domain.NotificationPreferences{}, ErrUnknown
+	}
+
+	return preferences, nil
+}
+
+// Can return ErrIncorrectParameters, ErrDependencyNotSatisfied
+func (serv notificationServiceImpl) UpdatePreferences(userID uint, preferences domain.NotificationPreferences) error {
+	if userID == 0 {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return ErrIncorrectParameters
+	}
+
+	err := serv.repo.UpdatePreferences(userID, preferences)
+	if err == repository.ErrNoMatchingDependency {
+		logging.LogDomainError(ErrDependencyNotSatisfied)
+		return ErrDependencyNotSatisfied
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return ErrUnknown
+	}
+
+	return nil
+}
+
+// Sends notifications on behalf of other services, failing to notify never fails the action that caused it
+type notifier struct {
+	repo        domain.NotificationRepository
+	profileRepo domain.ProfileRepository
+	events      domain.EventPublisher
+}
+
+// Skips notifications to the actor themselves and the types the recipient turned off, publishes the ones created to the recipient
+func (n notifier) notify(notification domain.Notification) {
+	if notification.UserID == 0 || notification.UserID == notification.ActorID {
+		return
+	}
+
+	preferences, err := n.repo.GetPreferences(notification.UserID)
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return
+	}
+	if !preferences.Allows(notification.Type) {
+		return
+	}
+
+	id, err := n.repo.Create(notification)
+	if err == repository.ErrRepeatedEntity {
+		return
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return
+	}
+
+	notification.ID = id
+	notification.CreatedAt = time.Now().Truncate(time.Second)
+	n.events.Publish(domain.NotificationsTopic(notification.UserID), domain.EventNotificationCreated, notification)
+}
+
+// Notifies the profiles of the mention entities
+func (n notifier) notifyMentions(actorID, postID, commentID uint, entities []domain.ContentEntity) {
+	for _, entity := range entities {
+		if entity.Type != domain.ContentEntityMention {
+			continue
+		}
+
+		n.notify(domain.Notification{
+			UserID:    entity.UserID,
+			ActorID:   actorID,
+			Type:      domain.NotificationMention,
+			PostID:    postID,
+			CommentID: commentID,
+		})
+	}
+}
+
+func NewNotificationService(repo domain.NotificationRepository) domain.NotificationService {
+	return notificationServiceImpl{repo: repo}
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"time"
+
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/logging"
+	"github.com/AlejandroJorge/forum-rest-api/repository"
+)
+
+// Returns nil if the user is the owner or a moderator, can return ErrNotAuthorized, ErrNotExistingEntity
+func checkOwnerOrModerator(userRepo domain.UserRepository, userID, ownerID uint) error {
+	if userID == ownerID {
+		return nil
+	}
+
+	user, err := userRepo.GetByID(userID)
+	if err == repository.ErrEmptySelection {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return ErrNotExistingEntity
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return ErrUnknown
+	}
+
+	if !user.IsModerator {
+		logging.LogDomainError(ErrNotAuthorized)
+		return ErrNotAuthorized
+	}
+
+	return nil
+}
+
+// Returns nil if the user is a moderator, can return ErrNotAuthorized, ErrNotExistingEntity
+func checkModerator(userRepo domain.UserRepository, userID uint) error {
+	user, err := userRepo.GetByID(userID)
+	if err == repository.ErrEmptySelection {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return ErrNotExistingEntity
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return ErrUnknown
+	}
+
+	if !user.IsModerator {
+		logging.LogDomainError(ErrNotAuthorized)
+		return ErrNotAuthorized
+	}
+
+	return nil
+}
+
+// Returns nil if no moderator suspended the user, can return ErrSuspended, ErrDependencyNotSatisfied
+func checkNotSuspended(userRepo domain.UserRepository, userID uint) error {
+	user, err := userRepo.GetByID(userID)
+	if err == repository.ErrEmptySelection {
+		logging.LogDomainError(ErrDependencyNotSatisfied)
+		return ErrDependencyNotSatisfied
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return ErrUnknown
+	}
+
+	if user.IsSuspended(time.Now()) {
+		logging.LogDomainError(ErrSuspended)
+		return ErrSuspended
+	}
+
+	return nil
+}
+
+// Returns nil if the post exists and isn't archived, can return ErrNotExistingEntity, ErrArchived
+func checkPostNotArchived(postRepo domain.PostRepository, postID uint) error {
+	post, err := postRepo.GetByID(postID)
+	if err == repository.ErrEmptySelection {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return ErrNotExistingEntity
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return ErrUnknown
+	}
+
+	if post.Archived {
+		logging.LogDomainError(ErrArchived)
+		return ErrArchived
+	}
+
+	return nil
+}
+
+// Returns nil if the viewer can see the owner's posts, viewerID is 0 for anonymous viewers, can return ErrNotAuthorized
+func checkVisible(profileRepo domain.ProfileRepository, viewerID, ownerID uint) error {
+	restrictedIDs, err := profileRepo.GetRestrictedIDs(viewerID, []uint{ownerID})
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return ErrUnknown
+	}
+
+	if len(restrictedIDs) != 0 {
+		logging.LogDomainError(ErrNotAuthorized)
+		return ErrNotAuthorized
+	}
+
+	return nil
+}
+
+// Reports whether either profile blocked the other
+func isBlockedBetween(profileRepo domain.ProfileRepository, firstID, secondID uint) (bool, error) {
+	for _, pair := range [][2]uint{{firstID, secondID}, {secondID, firstID}} {
+		blocked, err := profileRepo.IsBlocked(pair[0], pair[1])
+		if err != nil {
+			logging.LogUnexpectedDomainError(err)
+			return false, ErrUnknown
+		}
+		if blocked {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// Returns the IDs of the profiles the viewer muted
+func mutedProfiles(profileRepo domain.ProfileRepository, viewerID uint) (map[uint]bool, error) {
+	ids, err := profileRepo.GetMutedIDs(viewerID)
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return nil, ErrUnknown
+	}
+
+	muted := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		muted[id] = true
+	}
+
+	return muted, nil
+}
@@ -3,72 +3,89 @@ package service
 import (
 	"time"
 
+	"github.com/AlejandroJorge/forum-rest-api/config"
 	"github.com/AlejandroJorge/forum-rest-api/domain"
 	"github.com/AlejandroJorge/forum-rest-api/logging"
 	"github.com/AlejandroJorge/forum-rest-api/repository"
+	"github.com/AlejandroJorge/forum-rest-api/util"
 )
 
 type postServiceImpl struct {
-	repo domain.PostRepository
+	repo         domain.PostRepository
+	userRepo     domain.UserRepository
+	categoryRepo domain.CategoryRepository
+	profileRepo  domain.ProfileRepository
+	mediaRepo    domain.MediaRepository
+	notifier     notifier
+	events       domain.EventPublisher
 }
 
-// Can return ErIncorrectParameters, ErrAlreadyExisting, ErrDependencyNotSatisfied
-func (serv postServiceImpl) AddLike(userId uint, postId uint) error {
-	if userId == 0 || postId == 0 {
+// Returns the ID of the created post, can return ErrIncorrectParameters, ErrDependencyNotSatisfied, ErrArchived, ErrSuspended
+// Tags are normalized and attachments must be media uploaded by the owner
+// Renders the content, notifies the mentioned profiles and publishes the post to its owner's topic
+func (serv postServiceImpl) Create(ownerID, categoryID uint, title, description, content string, tags []string, attachments []uint) (uint, error) {
+	tags, ok := normalizePostTags(tags)
+	if ownerID == 0 || categoryID == 0 || title == "" || description == "" || content == "" || !ok {
 		logging.LogDomainError(ErrIncorrectParameters)
-		return ErrIncorrectParameters
+		return 0, ErrIncorrectParameters
+	}
+
+	err := checkNotSuspended(serv.userRepo, ownerID)
+	if err != nil {
+		return 0, err
 	}
 
-	err := serv.repo.AddLike(userId, postId)
-	if err == repository.ErrRepeatedEntity {
-		logging.LogDomainError(ErrAlreadyExisting)
-		return ErrAlreadyExisting
+	attachments, err = serv.checkAttachments(ownerID, attachments)
+	if err != nil {
+		return 0, err
 	}
-	if err == repository.ErrNoMatchingDependency {
+
+	category, err := serv.categoryRepo.GetByID(categoryID)
+	if err == repository.ErrEmptySelection {
 		logging.LogDomainError(ErrDependencyNotSatisfied)
-		return ErrDependencyNotSatisfied
+		return 0, ErrDependencyNotSatisfied
 	}
 	if err != nil {
 		logging.LogUnexpectedDomainError(err)
-		return ErrUnknown
+		return 0, ErrUnknown
 	}
 
-	return nil
-}
-
-// Returns the ID of the created post, can return ErrIncorrectParameters, ErrDependencyNotSatisfied, ErrAlreadyExisting
-func (serv postServiceImpl) Create(ownerID uint, title, description, content string) (uint, error) {
-	if ownerID == 0 || title == "" || description == "" || content == "" {
-		logging.LogDomainError(ErrIncorrectParameters)
-		return 0, ErrIncorrectParameters
+	if category.Archived {
+		logging.LogDomainError(ErrArchived)
+		return 0, ErrArchived
 	}
 
-	id, err := serv.repo.Create(ownerID, title, description, content)
+	id, err := serv.repo.Create(ownerID, categoryID, title, description, content, tags, attachments)
 	if err == repository.ErrNoMatchingDependency {
 		logging.LogDomainError(ErrDependencyNotSatisfied)
 		return 0, ErrDependencyNotSatisfied
 	}
-	if err == repository.ErrRepeatedEntity {
-		logging.LogDomainError(ErrAlreadyExisting)
-		return 0, ErrAlreadyExisting
-	}
 	if err != nil {
 		logging.LogUnexpectedDomainError(err)
 		return 0, ErrUnknown
 	}
 
+	serv.renderContent(ownerID, id, content)
+
+	post, err := serv.repo.GetByID(id)
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+	} else {
+		serv.events.Publish(domain.ProfileTopic(ownerID), domain.EventPostCreated, post)
+	}
+
 	return id, nil
 }
 
-// Can return ErrIncorrectParameters, ErrNotExistingEntity
-func (serv postServiceImpl) Delete(id uint) error {
-	if id == 0 {
+// Only the owner or a moderator can delete, can return ErrIncorrectParameters, ErrNotExistingEntity, ErrNotAuthorized
+func (serv postServiceImpl) Delete(userId, id uint) error {
+	if userId == 0 || id == 0 {
 		logging.LogDomainError(ErrIncorrectParameters)
 		return ErrIncorrectParameters
 	}
 
-	err := serv.repo.Delete(id)
-	if err == repository.ErrNoRowsAffected {
+	post, err := serv.repo.GetByID(id)
+	if err == repository.ErrEmptySelection {
 		logging.LogDomainError(ErrNotExistingEntity)
 		return ErrNotExistingEntity
 	}
@@ -77,17 +94,12 @@ func (serv postServiceImpl) Delete(id uint) error {
 		return ErrUnknown
 	}
 
-	return nil
-}
-
-// Can return ErrIncorrectParameters, ErrNotExistingEntity
-func (serv postServiceImpl) DeleteLike(userId uint, postId uint) error {
-	if userId == 0 || postId == 0 {
-		logging.LogDomainError(ErrIncorrectParameters)
-		return ErrIncorrectParameters
+	err = checkOwnerOrModerator(serv.userRepo, userId, post.OwnerID)
+	if err != nil {
+		return err
 	}
 
-	err := serv.repo.DeleteLike(userId, postId)
+	err = serv.repo.Delete(id)
 	if err == repository.ErrNoRowsAffected {
 		logging.LogDomainError(ErrNotExistingEntity)
 		return ErrNotExistingEntity
@@ -120,14 +132,15 @@ func (serv postServiceImpl) GetByID(id uint) (domain.Post, error) {
 	return post, nil
 }
 
-// Returns a slice of valid posts, can return ErrIncorrectParameters, ErrNotExistingEntity
-func (serv postServiceImpl) GetByUser(userId uint) ([]domain.Post, error) {
+// Returns a slice of valid posts with the pinned ones first, can return ErrIncorrectParameters, ErrNotExistingEntity
+// Only returns posts the viewer can see, viewerId is 0 for anonymous viewers
+func (serv postServiceImpl) GetByUser(viewerId, userId uint) ([]domain.Post, error) {
 	if userId == 0 {
 		logging.LogDomainError(ErrIncorrectParameters)
 		return nil, ErrIncorrectParameters
 	}
 
-	posts, err := serv.repo.GetByUser(userId)
+	posts, err := serv.repo.GetByUser(viewerId, userId)
 	if err == repository.ErrEmptySelection {
 		logging.LogDomainError(ErrNotExistingEntity)
 		return nil, ErrNotExistingEntity
@@ -140,9 +153,35 @@ func (serv postServiceImpl) GetByUser(userId uint) ([]domain.Post, error) {
 	return posts, nil
 }
 
-// Returns a slice of valid posts, can return ErrNotExistingEntity
-func (serv postServiceImpl) GetPopularAllTime() ([]domain.Post, error) {
-	posts, err := serv.repo.GetPopularAfter(time.Time{}, 20)
+// Returns a valid post whose current or previous slug matches, can return ErrIncorrectParameters, ErrNotExistingEntity
+func (serv postServiceImpl) GetBySlug(slug string) (domain.Post, error) {
+	if slug == "" {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return domain.Post{}, ErrIncorrectParameters
+	}
+
+	post, err := serv.repo.GetBySlug(slug)
+	if err == repository.ErrEmptySelection {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return domain.Post{}, ErrNotExistingEntity
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return domain.Post{}, ErrUnknown
+	}
+
+	return post, nil
+}
+
+// Returns a slice of valid posts, can return ErrIncorrectParameters, ErrNotExistingEntity
+// Only returns posts the viewer can see, viewerId is 0 for anonymous viewers
+func (serv postServiceImpl) GetPopularAllTime(viewerId uint, order domain.PostSort) ([]domain.Post, error) {
+	if !isValidPostSort(order) {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return nil, ErrIncorrectParameters
+	}
+
+	posts, err := serv.repo.GetPopularAfter(viewerId, time.Time{}, order, 20)
 	if err == repository.ErrEmptySelection {
 		logging.LogDomainError(ErrNotExistingEntity)
 		return nil, ErrNotExistingEntity
@@ -155,9 +194,15 @@ func (serv postServiceImpl) GetPopularAllTime() ([]domain.Post, error) {
 	return posts, nil
 }
 
-// Returns a slice of valid posts, can return ErrNotExistingEntity
-func (serv postServiceImpl) GetPopularLastMonth() ([]domain.Post, error) {
-	posts, err := serv.repo.GetPopularAfter(time.Now().AddDate(0, -1, 0), 20)
+// Returns a slice of valid posts, can return ErrIncorrectParameters, ErrNotExistingEntity
+// Only returns posts the viewer can see, viewerId is 0 for anonymous viewers
+func (serv postServiceImpl) GetPopularLastMonth(viewerId uint, order domain.PostSort) ([]domain.Post, error) {
+	if !isValidPostSort(order) {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return nil, ErrIncorrectParameters
+	}
+
+	posts, err := serv.repo.GetPopularAfter(viewerId, time.Now().AddDate(0, -1, 0), order, 20)
 	if err == repository.ErrEmptySelection {
 		logging.LogDomainError(ErrNotExistingEntity)
 		return nil, ErrNotExistingEntity
@@ -170,9 +215,15 @@ func (serv postServiceImpl) GetPopularLastMonth() ([]domain.Post, error) {
 	return posts, nil
 }
 
-// Returns a slice of valid posts, can return ErrNotExistingEntity
-func (serv postServiceImpl) GetPopularLastWeek() ([]domain.Post, error) {
-	posts, err := serv.repo.GetPopularAfter(time.Now().AddDate(0, 0, -7), 20)
+// Returns a slice of valid posts, can return ErrIncorrectParameters, ErrNotExistingEntity
+// Only returns posts the viewer can see, viewerId is 0 for anonymous viewers
+func (serv postServiceImpl) GetPopularLastWeek(viewerId uint, order domain.PostSort) ([]domain.Post, error) {
+	if !isValidPostSort(order) {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return nil, ErrIncorrectParameters
+	}
+
+	posts, err := serv.repo.GetPopularAfter(viewerId, time.Now().AddDate(0, 0, -7), order, 20)
 	if err == repository.ErrEmptySelection {
 		logging.LogDomainError(ErrNotExistingEntity)
 		return nil, ErrNotExistingEntity
@@ -185,9 +236,15 @@ func (serv postServiceImpl) GetPopularLastWeek() ([]domain.Post, error) {
 	return posts, nil
 }
 
-// Returns a slice of valid posts, can return ErrNotExistingEntity
-func (serv postServiceImpl) GetPopularToday() ([]domain.Post, error) {
-	posts, err := serv.repo.GetPopularAfter(time.Now().AddDate(0, 0, -1), 20)
+// Returns a slice of valid posts, can return ErrIncorrectParameters, ErrNotExistingEntity
+// Only returns posts the viewer can see, viewerId is 0 for anonymous viewers
+func (serv postServiceImpl) GetPopularToday(viewerId uint, order domain.PostSort) ([]domain.Post, error) {
+	if !isValidPostSort(order) {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return nil, ErrIncorrectParameters
+	}
+
+	posts, err := serv.repo.GetPopularAfter(viewerId, time.Now().AddDate(0, 0, -1), order, 20)
 	if err == repository.ErrEmptySelection {
 		logging.LogDomainError(ErrNotExistingEntity)
 		return nil, ErrNotExistingEntity
@@ -200,38 +257,404 @@ func (serv postServiceImpl) GetPopularToday() ([]domain.Post, error) {
 	return posts, nil
 }
 
-// Can return ErrIncorrectParameters, ErrNotExistingEntity, ErrAlreadyExisting
-func (serv postServiceImpl) UpdateTitle(id uint, title string) error {
-	if id == 0 || title == "" {
+// Returns a page of valid posts by followed profiles, an empty cursor starts from the first page, can return ErrIncorrectParameters, ErrNotExistingEntity
+func (serv postServiceImpl) GetFeed(userId uint, mode domain.FeedMode, cursor string) (domain.FeedPage, error) {
+	if userId == 0 {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return domain.FeedPage{}, ErrIncorrectParameters
+	}
+
+	switch mode {
+	case domain.FeedModeLatest:
+		return serv.getLatestFeed(userId, cursor)
+	case domain.FeedModeRanked:
+		return serv.getRankedFeed(userId, cursor)
+	}
+
+	logging.LogDomainError(ErrIncorrectParameters)
+	return domain.FeedPage{}, ErrIncorrectParameters
+}
+
+func (serv postServiceImpl) getLatestFeed(userId uint, cursor string) (domain.FeedPage, error) {
+	var beforeDate time.Time
+	var beforeID uint
+	if cursor != "" {
+		var ok bool
+		beforeDate, beforeID, ok = decodeLatestFeedCursor(cursor)
+		if !ok {
+			logging.LogDomainError(ErrIncorrectParameters)
+			return domain.FeedPage{}, ErrIncorrectParameters
+		}
+	}
+
+	// One extra post tells whether there's a next page
+	pageSize := config.GetParams().PageSize
+	posts, err := serv.repo.GetByFollowerBefore(userId, beforeDate, beforeID, pageSize+1)
+	if err == repository.ErrEmptySelection {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return domain.FeedPage{}, ErrNotExistingEntity
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return domain.FeedPage{}, ErrUnknown
+	}
+
+	page := domain.FeedPage{Posts: posts}
+	if uint(len(posts)) > pageSize {
+		page.Posts = posts[:pageSize]
+		page.NextCursor = encodeLatestFeedCursor(page.Posts[pageSize-1])
+	}
+
+	return page, nil
+}
+
+func (serv postServiceImpl) getRankedFeed(userId uint, cursor string) (domain.FeedPage, error) {
+	var offset uint
+	if cursor != "" {
+		var ok bool
+		offset, ok = decodeRankedFeedCursor(cursor)
+		if !ok {
+			logging.LogDomainError(ErrIncorrectParameters)
+			return domain.FeedPage{}, ErrIncorrectParameters
+		}
+	}
+
+	now := time.Now()
+	posts, err := serv.repo.GetByFollowerAfter(userId, now.Add(-rankedFeedWindow), rankedFeedCandidates)
+	if err == repository.ErrEmptySelection {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return domain.FeedPage{}, ErrNotExistingEntity
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return domain.FeedPage{}, ErrUnknown
+	}
+
+	if offset >= uint(len(posts)) {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return domain.FeedPage{}, ErrNotExistingEntity
+	}
+
+	sortPostsByRecencyAndScore(posts, now)
+
+	pageSize := config.GetParams().PageSize
+	end := offset + pageSize
+	page := domain.FeedPage{}
+	if end < uint(len(posts)) {
+		page.NextCursor = encodeRankedFeedCursor(end)
+	} else {
+		end = uint(len(posts))
+	}
+	page.Posts = posts[offset:end]
+
+	return page, nil
+}
+
+// Returns a page of valid posts from newest to oldest, pages start at 1, can return ErrIncorrectParameters, ErrNotExistingEntity
+// Only returns posts the viewer can see, viewerId is 0 for anonymous viewers
+func (serv postServiceImpl) GetByTag(viewerId uint, tag string, page uint) ([]domain.Post, error) {
+	tag = util.NormalizeTag(tag)
+	if tag == "" || page == 0 {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return nil, ErrIncorrectParameters
+	}
+
+	pageSize := config.GetParams().PageSize
+	posts, err := serv.repo.GetByTag(viewerId, tag, pageSize, (page-1)*pageSize)
+	if err == repository.ErrEmptySelection {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return nil, ErrNotExistingEntity
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return nil, ErrUnknown
+	}
+
+	return posts, nil
+}
+
+// Includes posts in subcategories, returns a slice of valid posts with the pinned ones first, can return ErrIncorrectParameters, ErrNotExistingEntity
+// Only returns posts the viewer can see, viewerId is 0 for anonymous viewers
+func (serv postServiceImpl) GetPopularInCategory(viewerId, categoryId uint, period domain.PopularPeriod, order domain.PostSort) ([]domain.Post, error) {
+	start, ok := popularPeriodStart(period)
+	if categoryId == 0 || !ok || !isValidPostSort(order) {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return nil, ErrIncorrectParameters
+	}
+
+	posts, err := serv.repo.GetPopularInCategoryAfter(viewerId, categoryId, start, order, 20)
+	if err == repository.ErrEmptySelection {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return nil, ErrNotExistingEntity
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return nil, ErrUnknown
+	}
+
+	return posts, nil
+}
+
+// Recomputes the ranking scores of every post, returns the amount of ranked posts
+func (serv postServiceImpl) RefreshScores() (uint, error) {
+	engagements, err := serv.repo.GetEngagement()
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return 0, ErrUnknown
+	}
+
+	now := time.Now()
+	scores := make([]domain.PostScore, len(engagements))
+	for i, engagement := range engagements {
+		scores[i] = scorePost(engagement, now)
+	}
+
+	err = serv.repo.SaveScores(scores, now)
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return 0, ErrUnknown
+	}
+
+	return uint(len(scores)), nil
+}
+
+// Returns the moment a popularity period begins, false if the period isn't valid
+func popularPeriodStart(period domain.PopularPeriod) (time.Time, bool) {
+	switch period {
+	case domain.PopularPeriodToday:
+		return time.Now().AddDate(0, 0, -1), true
+	case domain.PopularPeriodWeek:
+		return time.Now().AddDate(0, 0, -7), true
+	case domain.PopularPeriodMonth:
+		return time.Now().AddDate(0, -1, 0), true
+	case domain.PopularPeriodAllTime:
+		return time.Time{}, true
+	}
+
+	return time.Time{}, false
+}
+
+// Only the owner or a moderator can edit, can return ErrIncorrectParameters, ErrNotExistingEntity, ErrNotAuthorized, ErrArchived, ErrSuspended
+// Tags are normalized
+func (serv postServiceImpl) UpdateTags(userId, id uint, tags []string) error {
+	tags, ok := normalizePostTags(tags)
+	if userId == 0 || id == 0 || !ok {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return ErrIncorrectParameters
+	}
+
+	err := serv.checkEditable(userId, id)
+	if err != nil {
+		return err
+	}
+
+	err = serv.repo.UpdateTags(id, tags)
+	if err == repository.ErrNoRowsAffected {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return ErrNotExistingEntity
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return ErrUnknown
+	}
+
+	return nil
+}
+
+// Returns the distinct attachments in order, each one has to be media uploaded by the owner, can return ErrIncorrectParameters
+func (serv postServiceImpl) checkAttachments(ownerID uint, mediaIDs []uint) ([]uint, error) {
+	if uint(len(mediaIDs)) > config.GetParams().MaxAttachmentsPerPost {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return nil, ErrIncorrectParameters
+	}
+
+	var attachments []uint
+	seen := make(map[uint]bool)
+	for _, mediaID := range mediaIDs {
+		if seen[mediaID] {
+			continue
+		}
+		seen[mediaID] = true
+
+		media, err := serv.mediaRepo.GetByID(mediaID)
+		if err == repository.ErrEmptySelection {
+			logging.LogDomainError(ErrIncorrectParameters)
+			return nil, ErrIncorrectParameters
+		}
+		if err != nil {
+			logging.LogUnexpectedDomainError(err)
+			return nil, ErrUnknown
+		}
+
+		if media.OwnerID != ownerID {
+			logging.LogDomainError(ErrIncorrectParameters)
+			return nil, ErrIncorrectParameters
+		}
+
+		attachments = append(attachments, mediaID)
+	}
+
+	return attachments, nil
+}
+
+// Returns the distinct normalized tags, false if one is unusable or there are too many
+func normalizePostTags(tags []string) ([]string, bool) {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		name := util.NormalizeTag(tag)
+		if name == "" {
+			return nil, false
+		}
+		if !seen[name] {
+			seen[name] = true
+			normalized = append(normalized, name)
+		}
+	}
+
+	if uint(len(normalized)) > config.GetParams().MaxTagsPerPost {
+		return nil, false
+	}
+
+	return normalized, true
+}
+
+// Only the owner or a moderator can edit, can return ErrIncorrectParameters, ErrNotExistingEntity, ErrNotAuthorized, ErrArchived, ErrSuspended
+func (serv postServiceImpl) UpdateTitle(userId, id uint, title string) error {
+	if userId == 0 || id == 0 || title == "" {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return ErrIncorrectParameters
+	}
+
+	err := serv.checkEditable(userId, id)
+	if err != nil {
+		return err
+	}
+
+	err = serv.repo.UpdateTitle(id, userId, title)
+	if err == repository.ErrNoRowsAffected {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return ErrNotExistingEntity
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return ErrUnknown
+	}
+
+	return nil
+}
+
+// Only the owner or a moderator can edit, can return ErrIncorrectParameters, ErrNotExistingEntity, ErrNotAuthorized, ErrArchived, ErrSuspended
+func (serv postServiceImpl) UpdateDescription(userId, id uint, description string) error {
+	if userId == 0 || id == 0 || description == "" {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return ErrIncorrectParameters
+	}
+
+	err := serv.checkEditable(userId, id)
+	if err != nil {
+		return err
+	}
+
+	err = serv.repo.UpdateDescription(id, userId, description)
+	if err == repository.ErrNoRowsAffected {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return ErrNotExistingEntity
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return ErrUnknown
+	}
+
+	return nil
+}
+
+// Only the owner or a moderator can edit, can return ErrIncorrectParameters, ErrNotExistingEntity, ErrNotAuthorized, ErrArchived, ErrSuspended
+// Renders the content again and notifies newly mentioned profiles
+func (serv postServiceImpl) UpdateContent(userId, id uint, content string) error {
+	if userId == 0 || id == 0 || content == "" {
 		logging.LogDomainError(ErrIncorrectParameters)
 		return ErrIncorrectParameters
 	}
 
-	err := serv.repo.UpdateTitle(id, title)
+	err := serv.checkEditable(userId, id)
+	if err != nil {
+		return err
+	}
+
+	err = serv.repo.UpdateContent(id, userId, content)
 	if err == repository.ErrNoRowsAffected {
 		logging.LogDomainError(ErrNotExistingEntity)
 		return ErrNotExistingEntity
 	}
-	if err == repository.ErrRepeatedEntity {
-		logging.LogDomainError(ErrAlreadyExisting)
-		return ErrAlreadyExisting
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return ErrUnknown
+	}
+
+	serv.renderContent(userId, id, content)
+
+	return nil
+}
+
+// Returns nil if the user is the owner or a moderator and the post isn't archived, can return ErrNotExistingEntity, ErrNotAuthorized, ErrArchived, ErrSuspended
+func (serv postServiceImpl) checkEditable(userId, postId uint) error {
+	err := checkNotSuspended(serv.userRepo, userId)
+	if err != nil {
+		return err
+	}
+
+	post, err := serv.repo.GetByID(postId)
+	if err == repository.ErrEmptySelection {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return ErrNotExistingEntity
 	}
 	if err != nil {
 		logging.LogUnexpectedDomainError(err)
 		return ErrUnknown
 	}
 
+	err = checkOwnerOrModerator(serv.userRepo, userId, post.OwnerID)
+	if err != nil {
+		return err
+	}
+
+	if post.Archived {
+		logging.LogDomainError(ErrArchived)
+		return ErrArchived
+	}
+
 	return nil
 }
 
-// Can return ErrIncorrectParameters, ErrNotExistingEntity,
-func (serv postServiceImpl) UpdateDescription(id uint, description string) error {
-	if id == 0 || description == "" {
+// Only the owner or a moderator can restore, can return ErrIncorrectParameters, ErrNotExistingEntity, ErrNotAuthorized
+// Only moderators can restore posts removed by moderation
+func (serv postServiceImpl) Restore(userId uint, postId uint) error {
+	if userId == 0 || postId == 0 {
 		logging.LogDomainError(ErrIncorrectParameters)
 		return ErrIncorrectParameters
 	}
 
-	err := serv.repo.UpdateDescription(id, description)
+	post, err := serv.repo.GetDeletedByID(postId)
+	if err == repository.ErrEmptySelection {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return ErrNotExistingEntity
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return ErrUnknown
+	}
+
+	if post.Moderation != "" {
+		err = checkModerator(serv.userRepo, userId)
+	} else {
+		err = checkOwnerOrModerator(serv.userRepo, userId, post.OwnerID)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = serv.repo.Restore(postId)
 	if err == repository.ErrNoRowsAffected {
 		logging.LogDomainError(ErrNotExistingEntity)
 		return ErrNotExistingEntity
@@ -244,14 +667,97 @@ func (serv postServiceImpl) UpdateDescription(id uint, description string) error
 	return nil
 }
 
-// Can return ErrIncorrectParameters, ErrNotExistingEntity,
-func (serv postServiceImpl) UpdateContent(id uint, content string) error {
-	if id == 0 || content == "" {
+// Returns the amount of purged posts, can return ErrIncorrectParameters
+func (serv postServiceImpl) PurgeDeleted(retention time.Duration) (uint, error) {
+	if retention < 0 {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return 0, ErrIncorrectParameters
+	}
+
+	amount, err := serv.repo.PurgeDeletedBefore(time.Now().Add(-retention))
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return 0, ErrUnknown
+	}
+
+	return amount, nil
+}
+
+// Archives the posts without activity for longer than inactivity, returns the amount of archived posts, can return ErrIncorrectParameters
+func (serv postServiceImpl) ArchiveInactive(inactivity time.Duration) (uint, error) {
+	if inactivity <= 0 {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return 0, ErrIncorrectParameters
+	}
+
+	amount, err := serv.repo.ArchiveInactiveBefore(time.Now().Add(-inactivity))
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return 0, ErrUnknown
+	}
+
+	return amount, nil
+}
+
+// Only the owner or a moderator can lock, can return ErrIncorrectParameters, ErrNotExistingEntity, ErrNotAuthorized, ErrSuspended
+// Locked posts don't accept comments
+func (serv postServiceImpl) Lock(userId, postId uint) error {
+	return serv.updateState(userId, postId, serv.repo.UpdateLocked, true)
+}
+
+// Only the owner or a moderator can unlock, can return ErrIncorrectParameters, ErrNotExistingEntity, ErrNotAuthorized, ErrSuspended
+func (serv postServiceImpl) Unlock(userId, postId uint) error {
+	return serv.updateState(userId, postId, serv.repo.UpdateLocked, false)
+}
+
+// Only the owner or a moderator can pin, can return ErrIncorrectParameters, ErrNotExistingEntity, ErrNotAuthorized, ErrSuspended
+// Pinned posts come first in their category and their owner's posts
+func (serv postServiceImpl) Pin(userId, postId uint) error {
+	return serv.updateState(userId, postId, serv.repo.UpdatePinned, true)
+}
+
+// Only the owner or a moderator can unpin, can return ErrIncorrectParameters, ErrNotExistingEntity, ErrNotAuthorized, ErrSuspended
+func (serv postServiceImpl) Unpin(userId, postId uint) error {
+	return serv.updateState(userId, postId, serv.repo.UpdatePinned, false)
+}
+
+// Only the owner or a moderator can archive, archived posts are read-only, can return ErrIncorrectParameters, ErrNotExistingEntity, ErrNotAuthorized, ErrSuspended
+func (serv postServiceImpl) Archive(userId, postId uint) error {
+	return serv.updateState(userId, postId, serv.repo.UpdateArchived, true)
+}
+
+// Only the owner or a moderator can unarchive, can return ErrIncorrectParameters, ErrNotExistingEntity, ErrNotAuthorized, ErrSuspended
+func (serv postServiceImpl) Unarchive(userId, postId uint) error {
+	return serv.updateState(userId, postId, serv.repo.UpdateArchived, false)
+}
+
+func (serv postServiceImpl) updateState(userId, postId uint, update func(id uint, enabled bool) error, enabled bool) error {
+	if userId == 0 || postId == 0 {
 		logging.LogDomainError(ErrIncorrectParameters)
 		return ErrIncorrectParameters
 	}
 
-	err := serv.repo.UpdateContent(id, content)
+	err := checkNotSuspended(serv.userRepo, userId)
+	if err != nil {
+		return err
+	}
+
+	post, err := serv.repo.GetByID(postId)
+	if err == repository.ErrEmptySelection {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return ErrNotExistingEntity
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return ErrUnknown
+	}
+
+	err = checkOwnerOrModerator(serv.userRepo, userId, post.OwnerID)
+	if err != nil {
+		return err
+	}
+
+	err = update(postId, enabled)
 	if err == repository.ErrNoRowsAffected {
 		logging.LogDomainError(ErrNotExistingEntity)
 		return ErrNotExistingEntity
@@ -264,6 +770,227 @@ func (serv postServiceImpl) UpdateContent(id uint, content string) error {
 	return nil
 }
 
-func NewPostService(repo domain.PostRepository) domain.PostService {
-	return postServiceImpl{repo: repo}
+// Returns a slice of revisions from oldest to newest, can return ErrIncorrectParameters, ErrNotExistingEntity
+func (serv postServiceImpl) GetRevisions(postId uint) ([]domain.PostRevision, error) {
+	if postId == 0 {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return nil, ErrIncorrectParameters
+	}
+
+	revisions, err := serv.repo.GetRevisions(postId)
+	if err == repository.ErrEmptySelection {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return nil, ErrNotExistingEntity
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return nil, ErrUnknown
+	}
+
+	return revisions, nil
+}
+
+// Returns a revision, can return ErrIncorrectParameters, ErrNotExistingEntity
+func (serv postServiceImpl) GetRevision(postId, revision uint) (domain.PostRevision, error) {
+	if postId == 0 || revision == 0 {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return domain.PostRevision{}, ErrIncorrectParameters
+	}
+
+	postRevision, err := serv.repo.GetRevision(postId, revision)
+	if err == repository.ErrEmptySelection {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return domain.PostRevision{}, ErrNotExistingEntity
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return domain.PostRevision{}, ErrUnknown
+	}
+
+	return postRevision, nil
+}
+
+// Returns the differences from one revision to another, can return ErrIncorrectParameters, ErrNotExistingEntity
+func (serv postServiceImpl) DiffRevisions(postId, fromRevision, toRevision uint) (domain.PostRevisionDiff, error) {
+	from, err := serv.GetRevision(postId, fromRevision)
+	if err != nil {
+		return domain.PostRevisionDiff{}, err
+	}
+
+	to, err := serv.GetRevision(postId, toRevision)
+	if err != nil {
+		return domain.PostRevisionDiff{}, err
+	}
+
+	diff := domain.PostRevisionDiff{
+		PostID:       postId,
+		FromRevision: fromRevision,
+		ToRevision:   toRevision,
+		Title:        util.LineDiff(from.Title, to.Title),
+		Description:  util.LineDiff(from.Description, to.Description),
+		Content:      util.LineDiff(from.Content, to.Content),
+	}
+
+	return diff, nil
+}
+
+// Only the owner can roll back, can return ErrIncorrectParameters, ErrNotExistingEntity, ErrNotAuthorized, ErrArchived, ErrSuspended
+// Renders the restored content again
+func (serv postServiceImpl) Rollback(userId, postId, revision uint) error {
+	if userId == 0 || postId == 0 || revision == 0 {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return ErrIncorrectParameters
+	}
+
+	err := checkNotSuspended(serv.userRepo, userId)
+	if err != nil {
+		return err
+	}
+
+	post, err := serv.repo.GetByID(postId)
+	if err == repository.ErrEmptySelection {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return ErrNotExistingEntity
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return ErrUnknown
+	}
+
+	if post.OwnerID != userId {
+		logging.LogDomainError(ErrNotAuthorized)
+		return ErrNotAuthorized
+	}
+
+	if post.Archived {
+		logging.LogDomainError(ErrArchived)
+		return ErrArchived
+	}
+
+	err = serv.repo.Rollback(postId, userId, revision)
+	if err == repository.ErrNoRowsAffected {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return ErrNotExistingEntity
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return ErrUnknown
+	}
+
+	post, err = serv.repo.GetByID(postId)
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return nil
+	}
+	serv.renderContent(userId, postId, post.Content)
+
+	return nil
+}
+
+// Stores the content rendered as HTML and its mentions and hashtags, notifies the mentioned profiles, failing to store them doesn't fail the write
+func (serv postServiceImpl) renderContent(actorID, postID uint, content string) {
+	contentHTML := util.RenderMarkdown(content)
+	entities := parseContentEntities(serv.profileRepo, content)
+	err := serv.repo.SetRendered(postID, contentHTML, entities)
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+	}
+
+	serv.notifier.notifyMentions(actorID, postID, 0, entities)
+}
+
+// Value is 1 or -1 and 0 removes the vote, can return ErrIncorrectParameters, ErrNotExistingEntity, ErrDependencyNotSatisfied, ErrNotAuthorized, ErrArchived, ErrSuspended
+// Upvotes notify the owner, the new counts are published to the post's topic
+func (serv postServiceImpl) Vote(userId uint, postId uint, value int) error {
+	if userId == 0 || postId == 0 || !isValidVote(value) {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return ErrIncorrectParameters
+	}
+
+	err := checkNotSuspended(serv.userRepo, userId)
+	if err != nil {
+		return err
+	}
+
+	post, err := serv.repo.GetByID(postId)
+	if err == repository.ErrEmptySelection {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return ErrNotExistingEntity
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return ErrUnknown
+	}
+
+	if post.Archived {
+		logging.LogDomainError(ErrArchived)
+		return ErrArchived
+	}
+
+	err = checkVisible(serv.profileRepo, userId, post.OwnerID)
+	if err != nil {
+		return err
+	}
+
+	err = serv.repo.Vote(userId, postId, value)
+	if err == repository.ErrNoMatchingDependency {
+		logging.LogDomainError(ErrDependencyNotSatisfied)
+		return ErrDependencyNotSatisfied
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return ErrUnknown
+	}
+
+	if value > 0 {
+		serv.notifier.notify(domain.Notification{UserID: post.OwnerID, ActorID: userId, Type: domain.NotificationLike, PostID: postId})
+	}
+
+	post, err = serv.repo.GetByID(postId)
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+	} else {
+		counts := domain.VoteCounts{PostID: postId, Score: post.Score, Upvotes: post.Upvotes, Downvotes: post.Downvotes}
+		serv.events.Publish(domain.PostTopic(postId), domain.EventPostVoted, counts)
+	}
+
+	return nil
+}
+
+// Private profiles' posts are only visible to their approved followers, viewerId is 0 for anonymous viewers, can return ErrNotAuthorized
+func (serv postServiceImpl) CheckVisible(viewerId, ownerId uint) error {
+	return checkVisible(serv.profileRepo, viewerId, ownerId)
+}
+
+// Fills the viewer fields of the posts for the user, can return ErrIncorrectParameters
+func (serv postServiceImpl) AddViewer(viewerId uint, posts []domain.Post) error {
+	if viewerId == 0 {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return ErrIncorrectParameters
+	}
+	if len(posts) == 0 {
+		return nil
+	}
+
+	postIDs := make([]uint, len(posts))
+	for i, post := range posts {
+		postIDs[i] = post.PostID
+	}
+
+	viewers, err := serv.repo.GetViewerStates(viewerId, postIDs)
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return ErrUnknown
+	}
+
+	for i := range posts {
+		viewer := viewers[posts[i].PostID]
+		posts[i].Viewer = &viewer
+	}
+
+	return nil
+}
+
+func NewPostService(repo domain.PostRepository, userRepo domain.UserRepository, categoryRepo domain.CategoryRepository, profileRepo domain.ProfileRepository, mediaRepo domain.MediaRepository, notificationRepo domain.NotificationRepository, events domain.EventPublisher) domain.PostService {
+	return postServiceImpl{repo: repo, userRepo: userRepo, categoryRepo: categoryRepo, profileRepo: profileRepo, mediaRepo: mediaRepo, notifier: notifier{repo: notificationRepo, profileRepo: profileRepo, events: events}, events: events}
 }
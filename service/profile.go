@@ -1,6 +1,8 @@
 package service
 
 import (
+	"strings"
+
 	"github.com/AlejandroJorge/forum-rest-api/domain"
 	"github.com/AlejandroJorge/forum-rest-api/logging"
 	"github.com/AlejandroJorge/forum-rest-api/repository"
@@ -8,17 +10,77 @@ import (
 )
 
 type profileServiceImpl struct {
-	repo domain.ProfileRepository
+	repo      domain.ProfileRepository
+	mediaRepo domain.MediaRepository
+	notifier  notifier
+	events    domain.EventPublisher
 }
 
-// Can return ErrAlreadyExisting, ErrIncorrectParameters, ErrDependencyNotSatisfied
-func (serv profileServiceImpl) AddFollow(followerId uint, followedId uint) error {
+// Returns true when the follow was only requested, can return ErrAlreadyExisting, ErrIncorrectParameters, ErrDependencyNotSatisfied, ErrNotAuthorized
+// Following a private profile only requests it and profiles that blocked each other can't follow
+// Notifies the followed profile and publishes the follow to its topic
+func (serv profileServiceImpl) AddFollow(followerId uint, followedId uint) (bool, error) {
 	if followedId == 0 || followerId == 0 {
 		logging.LogDomainError(ErrIncorrectParameters)
-		return ErrIncorrectParameters
+		return false, ErrIncorrectParameters
+	}
+
+	blocked, err := isBlockedBetween(serv.repo, followerId, followedId)
+	if err != nil {
+		return false, err
+	}
+	if blocked {
+		logging.LogDomainError(ErrNotAuthorized)
+		return false, ErrNotAuthorized
+	}
+
+	followed, err := serv.repo.GetByUserID(followedId)
+	if err == repository.ErrEmptySelection {
+		logging.LogDomainError(ErrDependencyNotSatisfied)
+		return false, ErrDependencyNotSatisfied
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return false, ErrUnknown
+	}
+
+	if followed.IsPrivate {
+		return true, serv.requestFollow(followerId, followedId)
 	}
 
-	err := serv.repo.AddFollow(followerId, followedId)
+	err = serv.repo.AddFollow(followerId, followedId)
+	if err == repository.ErrRepeatedEntity {
+		logging.LogDomainError(ErrAlreadyExisting)
+		return false, ErrAlreadyExisting
+	}
+	if err == repository.ErrNoMatchingDependency {
+		logging.LogDomainError(ErrDependencyNotSatisfied)
+		return false, ErrDependencyNotSatisfied
+	}
+	if err != nil {
+		logging.LogUnexpectedRepositoryError(err)
+		return false, ErrUnknown
+	}
+
+	serv.notifier.notify(domain.Notification{UserID: followedId, ActorID: followerId, Type: domain.NotificationFollow})
+	serv.events.Publish(domain.ProfileTopic(followedId), domain.EventProfileFollowed, domain.Follow{FollowerID: followerId, FollowedID: followedId})
+
+	return false, nil
+}
+
+// Notifies the private profile of the request, can return ErrAlreadyExisting, ErrDependencyNotSatisfied
+func (serv profileServiceImpl) requestFollow(followerId uint, followedId uint) error {
+	restricted, err := serv.repo.GetRestrictedIDs(followerId, []uint{followedId})
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return ErrUnknown
+	}
+	if len(restricted) == 0 {
+		logging.LogDomainError(ErrAlreadyExisting)
+		return ErrAlreadyExisting
+	}
+
+	err = serv.repo.AddFollowRequest(followerId, followedId)
 	if err == repository.ErrRepeatedEntity {
 		logging.LogDomainError(ErrAlreadyExisting)
 		return ErrAlreadyExisting
@@ -28,10 +90,12 @@ func (serv profileServiceImpl) AddFollow(followerId uint, followedId uint) error
 		return ErrDependencyNotSatisfied
 	}
 	if err != nil {
-		logging.LogUnexpectedRepositoryError(err)
+		logging.LogUnexpectedDomainError(err)
 		return ErrUnknown
 	}
 
+	serv.notifier.notify(domain.Notification{UserID: followedId, ActorID: followerId, Type: domain.NotificationFollowRequest})
+
 	return nil
 }
 
@@ -79,7 +143,7 @@ func (serv profileServiceImpl) Delete(id uint) error {
 	return nil
 }
 
-// Can return ErrIncorrectParameters, ErrNotExistingEntity
+// Cancels the follow request when it's still pending, can return ErrIncorrectParameters, ErrNotExistingEntity
 func (serv profileServiceImpl) DeleteFollow(followerId uint, followedId uint) error {
 	if followedId == 0 || followerId == 0 {
 		logging.LogDomainError(ErrIncorrectParameters)
@@ -87,6 +151,9 @@ func (serv profileServiceImpl) DeleteFollow(followerId uint, followedId uint) er
 	}
 
 	err := serv.repo.DeleteFollow(followerId, followedId)
+	if err == repository.ErrNoRowsAffected {
+		err = serv.repo.DeleteFollowRequest(followerId, followedId)
+	}
 	if err == repository.ErrNoRowsAffected {
 		logging.LogDomainError(ErrNotExistingEntity)
 		return ErrNotExistingEntity
@@ -267,14 +334,19 @@ func (serv profileServiceImpl) UpdateDisplayName(id uint, displayName string) er
 	return nil
 }
 
-// Can return ErrNotExistingEntity
+// The path has to be the URL of media uploaded by the user, can return ErrIncorrectParameters, ErrNotExistingEntity
 func (serv profileServiceImpl) UpdatePicturePath(id uint, picturePath string) error {
 	if id == 0 || picturePath == "" {
 		logging.LogDomainError(ErrIncorrectParameters)
 		return ErrIncorrectParameters
 	}
 
-	err := serv.repo.UpdatePicturePath(id, picturePath)
+	err := serv.checkOwnMedia(id, picturePath)
+	if err != nil {
+		return err
+	}
+
+	err = serv.repo.UpdatePicturePath(id, picturePath)
 	if err == repository.ErrNoRowsAffected {
 		logging.LogDomainError(ErrNotExistingEntity)
 		return ErrNotExistingEntity
@@ -287,14 +359,251 @@ func (serv profileServiceImpl) UpdatePicturePath(id uint, picturePath string) er
 	return nil
 }
 
-// Can return ErrNotExistingEntity
+// The path has to be the URL of media uploaded by the user, can return ErrIncorrectParameters, ErrNotExistingEntity
 func (serv profileServiceImpl) UpdateBackgroundPath(id uint, backgroundPath string) error {
 	if id == 0 || backgroundPath == "" {
 		logging.LogDomainError(ErrIncorrectParameters)
 		return ErrIncorrectParameters
 	}
 
-	err := serv.repo.UpdateBackgroundPath(id, backgroundPath)
+	err := serv.checkOwnMedia(id, backgroundPath)
+	if err != nil {
+		return err
+	}
+
+	err = serv.repo.UpdateBackgroundPath(id, backgroundPath)
+	if err == repository.ErrNoRowsAffected {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return ErrNotExistingEntity
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return ErrUnknown
+	}
+
+	return nil
+}
+
+// Can return ErrIncorrectParameters
+func (serv profileServiceImpl) checkOwnMedia(userID uint, path string) error {
+	key, ok := strings.CutPrefix(path, domain.MediaURLPrefix)
+	if !ok {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return ErrIncorrectParameters
+	}
+
+	_, err := serv.mediaRepo.GetByKey(userID, key)
+	if err == repository.ErrEmptySelection {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return ErrIncorrectParameters
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return ErrUnknown
+	}
+
+	return nil
+}
+
+// Removes the follows between both profiles, can return ErrIncorrectParameters, ErrAlreadyExisting, ErrDependencyNotSatisfied
+func (serv profileServiceImpl) Block(userId uint, blockedId uint) error {
+	if userId == 0 || blockedId == 0 || userId == blockedId {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return ErrIncorrectParameters
+	}
+
+	err := serv.repo.AddBlock(userId, blockedId)
+	if err == repository.ErrRepeatedEntity {
+		logging.LogDomainError(ErrAlreadyExisting)
+		return ErrAlreadyExisting
+	}
+	if err == repository.ErrNoMatchingDependency {
+		logging.LogDomainError(ErrDependencyNotSatisfied)
+		return ErrDependencyNotSatisfied
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return ErrUnknown
+	}
+
+	return nil
+}
+
+// Can return ErrIncorrectParameters, ErrNotExistingEntity
+func (serv profileServiceImpl) Unblock(userId uint, blockedId uint) error {
+	if userId == 0 || blockedId == 0 {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return ErrIncorrectParameters
+	}
+
+	err := serv.repo.DeleteBlock(userId, blockedId)
+	if err == repository.ErrNoRowsAffected {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return ErrNotExistingEntity
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return ErrUnknown
+	}
+
+	return nil
+}
+
+// Returns a slice of valid profiles, can return ErrIncorrectParameters, ErrNotExistingEntity
+func (serv profileServiceImpl) GetBlocked(userId uint) ([]domain.Profile, error) {
+	if userId == 0 {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return nil, ErrIncorrectParameters
+	}
+
+	profiles, err := serv.repo.GetBlocked(userId)
+	if err == repository.ErrEmptySelection {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return nil, ErrNotExistingEntity
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return nil, ErrUnknown
+	}
+
+	return profiles, nil
+}
+
+// Hides the posts and comments of the profile from the user, can return ErrIncorrectParameters, ErrAlreadyExisting, ErrDependencyNotSatisfied
+func (serv profileServiceImpl) Mute(userId uint, mutedId uint) error {
+	if userId == 0 || mutedId == 0 || userId == mutedId {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return ErrIncorrectParameters
+	}
+
+	err := serv.repo.AddMute(userId, mutedId)
+	if err == repository.ErrRepeatedEntity {
+		logging.LogDomainError(ErrAlreadyExisting)
+		return ErrAlreadyExisting
+	}
+	if err == repository.ErrNoMatchingDependency {
+		logging.LogDomainError(ErrDependencyNotSatisfied)
+		return ErrDependencyNotSatisfied
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return ErrUnknown
+	}
+
+	return nil
+}
+
+// Can return ErrIncorrectParameters, ErrNotExistingEntity
+func (serv profileServiceImpl) Unmute(userId uint, mutedId uint) error {
+	if userId == 0 || mutedId == 0 {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return ErrIncorrectParameters
+	}
+
+	err := serv.repo.DeleteMute(userId, mutedId)
+	if err == repository.ErrNoRowsAffected {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return ErrNotExistingEntity
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return ErrUnknown
+	}
+
+	return nil
+}
+
+// Returns a slice of valid profiles, can return ErrIncorrectParameters, ErrNotExistingEntity
+func (serv profileServiceImpl) GetMuted(userId uint) ([]domain.Profile, error) {
+	if userId == 0 {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return nil, ErrIncorrectParameters
+	}
+
+	profiles, err := serv.repo.GetMuted(userId)
+	if err == repository.ErrEmptySelection {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return nil, ErrNotExistingEntity
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return nil, ErrUnknown
+	}
+
+	return profiles, nil
+}
+
+// Making the profile public accepts its pending follow requests, can return ErrIncorrectParameters, ErrNotExistingEntity
+func (serv profileServiceImpl) UpdatePrivacy(id uint, isPrivate bool) error {
+	if id == 0 {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return ErrIncorrectParameters
+	}
+
+	err := serv.repo.UpdatePrivacy(id, isPrivate)
+	if err == repository.ErrNoRowsAffected {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return ErrNotExistingEntity
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return ErrUnknown
+	}
+
+	return nil
+}
+
+// Returns a slice of the profiles waiting for the user to approve them, can return ErrIncorrectParameters, ErrNotExistingEntity
+func (serv profileServiceImpl) GetFollowRequests(userId uint) ([]domain.Profile, error) {
+	if userId == 0 {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return nil, ErrIncorrectParameters
+	}
+
+	profiles, err := serv.repo.GetFollowRequests(userId)
+	if err == repository.ErrEmptySelection {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return nil, ErrNotExistingEntity
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return nil, ErrUnknown
+	}
+
+	return profiles, nil
+}
+
+// Turns the request into a follow, notifies the requester and publishes the follow to the user's topic, can return ErrIncorrectParameters, ErrNotExistingEntity
+func (serv profileServiceImpl) ApproveFollowRequest(userId uint, requesterId uint) error {
+	if userId == 0 || requesterId == 0 {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return ErrIncorrectParameters
+	}
+
+	err := serv.repo.ApproveFollowRequest(requesterId, userId)
+	if err == repository.ErrNoRowsAffected {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return ErrNotExistingEntity
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return ErrUnknown
+	}
+
+	serv.notifier.notify(domain.Notification{UserID: requesterId, ActorID: userId, Type: domain.NotificationFollowAccepted})
+	serv.events.Publish(domain.ProfileTopic(userId), domain.EventProfileFollowed, domain.Follow{FollowerID: requesterId, FollowedID: userId})
+
+	return nil
+}
+
+// Can return ErrIncorrectParameters, ErrNotExistingEntity
+func (serv profileServiceImpl) RejectFollowRequest(userId uint, requesterId uint) error {
+	if userId == 0 || requesterId == 0 {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return ErrIncorrectParameters
+	}
+
+	err := serv.repo.DeleteFollowRequest(requesterId, userId)
 	if err == repository.ErrNoRowsAffected {
 		logging.LogDomainError(ErrNotExistingEntity)
 		return ErrNotExistingEntity
@@ -307,6 +616,6 @@ func (serv profileServiceImpl) UpdateBackgroundPath(id uint, backgroundPath stri
 	return nil
 }
 
-func NewProfileService(repo domain.ProfileRepository) domain.ProfileService {
-	return profileServiceImpl{repo: repo}
+func NewProfileService(repo domain.ProfileRepository, mediaRepo domain.MediaRepository, notificationRepo domain.NotificationRepository, events domain.EventPublisher) domain.ProfileService {
+	return profileServiceImpl{repo: repo, mediaRepo: mediaRepo, notifier: notifier{repo: notificationRepo, profileRepo: repo, events: events}, events: events}
 }
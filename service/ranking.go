@@ -0,0 +1,54 @@
+package service
+
+import (
+	"math"
+	"time"
+
+	"github.com/AlejandroJorge/forum-rest-api/config"
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+)
+
+// z for a 95% confidence in the Wilson score interval
+const wilsonConfidence = 1.96
+
+func isValidPostSort(order domain.PostSort) bool {
+	switch order {
+	case domain.PostSortHot, domain.PostSortTop, domain.PostSortNew, domain.PostSortRising, domain.PostSortActive:
+		return true
+	}
+
+	return false
+}
+
+// New posts don't need a score, they're ordered by creation date
+func scorePost(engagement domain.PostEngagement, now time.Time) domain.PostScore {
+	params := config.GetParams()
+
+	ageHours := math.Max(now.Sub(engagement.CreationDate).Hours(), 0)
+	net := float64(engagement.Upvotes) - float64(engagement.Downvotes)
+	weighted := net + params.RankingCommentWeight*float64(engagement.Comments)
+
+	score := domain.PostScore{
+		PostID: engagement.PostID,
+		Hot:    weighted / math.Pow(ageHours+2, params.RankingGravity),
+		Top:    wilsonLowerBound(engagement.Upvotes, engagement.Upvotes+engagement.Downvotes),
+	}
+	if ageHours < float64(params.RisingWindowHours) {
+		score.Rising = weighted / (ageHours + 1)
+	}
+
+	return score
+}
+
+// Lower bound of the Wilson score interval for the proportion of upvotes, 0 without votes
+func wilsonLowerBound(positive, total uint) float64 {
+	if total == 0 {
+		return 0
+	}
+
+	n := float64(total)
+	p := float64(positive) / n
+	z2 := wilsonConfidence * wilsonConfidence
+
+	return (p + z2/(2*n) - wilsonConfidence*math.Sqrt((p*(1-p)+z2/(4*n))/n)) / (1 + z2/n)
+}
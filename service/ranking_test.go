@@ -0,0 +1,114 @@
+package service
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/AlejandroJorge/forum-rest-api/config"
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+)
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestScorePost(t *testing.T) {
+	params := config.GetParams()
+	now := time.Now()
+	window := time.Duration(params.RisingWindowHours) * time.Hour
+
+	cases := []struct {
+		name       string
+		engagement domain.PostEngagement
+		hot        float64
+		rising     float64
+	}{
+		{
+			name:       "new post",
+			engagement: domain.PostEngagement{Upvotes: 4, CreationDate: now},
+			hot:        4 / math.Pow(2, params.RankingGravity),
+			rising:     4,
+		},
+		{
+			name:       "comments are weighted",
+			engagement: domain.PostEngagement{Upvotes: 1, Comments: 2, CreationDate: now},
+			hot:        (1 + 2*params.RankingCommentWeight) / math.Pow(2, params.RankingGravity),
+			rising:     1 + 2*params.RankingCommentWeight,
+		},
+		{
+			name:       "age decays the score",
+			engagement: domain.PostEngagement{Upvotes: 4, CreationDate: now.Add(-10 * time.Hour)},
+			hot:        4 / math.Pow(12, params.RankingGravity),
+			rising:     4.0 / 11,
+		},
+		{
+			name:       "net negative posts score below zero",
+			engagement: domain.PostEngagement{Upvotes: 1, Downvotes: 3, CreationDate: now},
+			hot:        -2 / math.Pow(2, params.RankingGravity),
+			rising:     -2,
+		},
+		{
+			name:       "posts created in the future count as new",
+			engagement: domain.PostEngagement{Upvotes: 4, CreationDate: now.Add(time.Hour)},
+			hot:        4 / math.Pow(2, params.RankingGravity),
+			rising:     4,
+		},
+		{
+			name:       "just inside the rising window",
+			engagement: domain.PostEngagement{Upvotes: 4, CreationDate: now.Add(-window + time.Minute)},
+			hot:        4 / math.Pow(window.Hours()-1.0/60+2, params.RankingGravity),
+			rising:     4 / (window.Hours() - 1.0/60 + 1),
+		},
+		{
+			name:       "past the rising window",
+			engagement: domain.PostEngagement{Upvotes: 4, CreationDate: now.Add(-window)},
+			hot:        4 / math.Pow(window.Hours()+2, params.RankingGravity),
+			rising:     0,
+		},
+	}
+
+	for _, c := range cases {
+		score := scorePost(c.engagement, now)
+		if !almostEqual(c.hot, score.Hot) {
+			t.Errorf("%s: expected hot '%v', got '%v'", c.name, c.hot, score.Hot)
+		}
+		if !almostEqual(c.rising, score.Rising) {
+			t.Errorf("%s: expected rising '%v', got '%v'", c.name, c.rising, score.Rising)
+		}
+		if !almostEqual(wilsonLowerBound(c.engagement.Upvotes, c.engagement.Upvotes+c.engagement.Downvotes), score.Top) {
+			t.Errorf("%s: expected top to be the Wilson bound, got '%v'", c.name, score.Top)
+		}
+	}
+
+	older := scorePost(domain.PostEngagement{Upvotes: 4, CreationDate: now.Add(-time.Hour)}, now)
+	newer := scorePost(domain.PostEngagement{Upvotes: 4, CreationDate: now}, now)
+	if older.Hot >= newer.Hot {
+		t.Errorf("Expected the older post to rank lower, got '%v' and '%v'", older.Hot, newer.Hot)
+	}
+}
+
+func TestWilsonLowerBound(t *testing.T) {
+	cases := []struct {
+		positive, total uint
+		bound           float64
+	}{
+		{positive: 0, total: 0, bound: 0},
+		{positive: 0, total: 5, bound: 0},
+		{positive: 1, total: 1, bound: 0.20654329147389294},
+		{positive: 10, total: 10, bound: 0.7224598312333834},
+		{positive: 50, total: 100, bound: 0.40382982859014716},
+	}
+
+	for _, c := range cases {
+		bound := wilsonLowerBound(c.positive, c.total)
+		if !almostEqual(c.bound, bound) {
+			t.Errorf("%d of %d: expected '%v', got '%v'", c.positive, c.total, c.bound, bound)
+		}
+	}
+
+	// More votes with the same proportion give a tighter bound
+	if wilsonLowerBound(100, 100) <= wilsonLowerBound(10, 10) {
+		t.Errorf("Expected more votes to raise the bound")
+	}
+}
@@ -0,0 +1,250 @@
+package service
+
+import (
+	"time"
+
+	"github.com/AlejandroJorge/forum-rest-api/config"
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/logging"
+	"github.com/AlejandroJorge/forum-rest-api/repository"
+)
+
+type reportServiceImpl struct {
+	repo        domain.ReportRepository
+	userRepo    domain.UserRepository
+	postRepo    domain.PostRepository
+	commentRepo domain.CommentRepository
+	notifier    notifier
+}
+
+// Returns the id of the report the content is queued in, can return ErrIncorrectParameters, ErrNotExistingEntity, ErrAlreadyExisting, ErrDependencyNotSatisfied, ErrSuspended
+// Authors can't report their own content
+func (serv reportServiceImpl) Report(reporterID uint, targetType domain.ReportTarget, targetID uint, reason domain.ReportReason, details string) (uint, error) {
+	if reporterID == 0 || targetID == 0 || !reason.Valid() {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return 0, ErrIncorrectParameters
+	}
+
+	err := checkNotSuspended(serv.userRepo, reporterID)
+	if err != nil {
+		return 0, err
+	}
+
+	authorID, err := serv.getAuthor(targetType, targetID)
+	if err != nil {
+		return 0, err
+	}
+
+	if authorID == reporterID {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return 0, ErrIncorrectParameters
+	}
+
+	id, err := serv.repo.Create(targetType, targetID, authorID, reporterID, reason, details)
+	if err == repository.ErrRepeatedEntity {
+		logging.LogDomainError(ErrAlreadyExisting)
+		return 0, ErrAlreadyExisting
+	}
+	if err == repository.ErrNoMatchingDependency {
+		logging.LogDomainError(ErrDependencyNotSatisfied)
+		return 0, ErrDependencyNotSatisfied
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return 0, ErrUnknown
+	}
+
+	return id, nil
+}
+
+// Returns the author of a valid post or comment, can return ErrIncorrectParameters, ErrNotExistingEntity
+func (serv reportServiceImpl) getAuthor(targetType domain.ReportTarget, targetID uint) (uint, error) {
+	var authorID uint
+	var err error
+	switch targetType {
+	case domain.ReportTargetPost:
+		var post domain.Post
+		post, err = serv.postRepo.GetByID(targetID)
+		authorID = post.OwnerID
+	case domain.ReportTargetComment:
+		var comment domain.Comment
+		comment, err = serv.commentRepo.GetByID(targetID)
+		authorID = comment.UserID
+	default:
+		logging.LogDomainError(ErrIncorrectParameters)
+		return 0, ErrIncorrectParameters
+	}
+	if err == repository.ErrEmptySelection {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return 0, ErrNotExistingEntity
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return 0, ErrUnknown
+	}
+
+	return authorID, nil
+}
+
+// Returns a page of reports with the status, only moderators can see them, can return ErrIncorrectParameters, ErrNotAuthorized
+func (serv reportServiceImpl) GetQueue(moderatorID uint, status domain.ReportStatus, page uint) ([]domain.Report, error) {
+	if moderatorID == 0 || page == 0 || !status.Valid() {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return nil, ErrIncorrectParameters
+	}
+
+	err := checkModerator(serv.userRepo, moderatorID)
+	if err != nil {
+		return nil, err
+	}
+
+	pageSize := config.GetParams().PageSize
+	reports, err := serv.repo.GetByStatus(status, pageSize, (page-1)*pageSize)
+	if err != nil && err != repository.ErrEmptySelection {
+		logging.LogUnexpectedDomainError(err)
+		return nil, ErrUnknown
+	}
+	if reports == nil {
+		reports = []domain.Report{}
+	}
+
+	return reports, nil
+}
+
+// Only moderators can see reports, can return ErrIncorrectParameters, ErrNotAuthorized, ErrNotExistingEntity
+func (serv reportServiceImpl) GetByID(moderatorID, id uint) (domain.Report, error) {
+	if moderatorID == 0 || id == 0 {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return domain.Report{}, ErrIncorrectParameters
+	}
+
+	err := checkModerator(serv.userRepo, moderatorID)
+	if err != nil {
+		return domain.Report{}, err
+	}
+
+	report, err := serv.repo.GetByID(id)
+	if err == repository.ErrEmptySelection {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return domain.Report{}, ErrNotExistingEntity
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return domain.Report{}, ErrUnknown
+	}
+
+	return report, nil
+}
+
+// Applies the action and closes the report, can return ErrIncorrectParameters, ErrNotAuthorized, ErrNotExistingEntity, ErrArchived
+// Days is only used to suspend the author
+func (serv reportServiceImpl) Act(moderatorID, id uint, action domain.ModerationAction, note string, days uint) error {
+	if moderatorID == 0 || id == 0 || !action.Valid() || (action == domain.ModerationSuspend && days == 0) {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return ErrIncorrectParameters
+	}
+
+	report, err := serv.GetByID(moderatorID, id)
+	if err != nil {
+		return err
+	}
+
+	if report.Status != domain.ReportStatusOpen {
+		logging.LogDomainError(ErrArchived)
+		return ErrArchived
+	}
+
+	notification := domain.Notification{UserID: report.AuthorID, ActorID: moderatorID}
+	if report.TargetType == domain.ReportTargetPost {
+		notification.PostID = report.TargetID
+	} else {
+		notification.CommentID = report.TargetID
+	}
+
+	switch action {
+	case domain.ModerationHide, domain.ModerationDelete:
+		if report.TargetType == domain.ReportTargetPost {
+			err = serv.postRepo.Moderate(report.TargetID, action)
+		} else {
+			err = serv.commentRepo.Moderate(report.TargetID, action)
+		}
+		if err == repository.ErrNoRowsAffected {
+			logging.LogDomainError(ErrNotExistingEntity)
+			return ErrNotExistingEntity
+		}
+		if err != nil {
+			logging.LogUnexpectedDomainError(err)
+			return ErrUnknown
+		}
+	case domain.ModerationWarn:
+		notification.Type = domain.NotificationWarning
+		serv.notifier.notify(notification)
+	case domain.ModerationSuspend:
+		err = serv.userRepo.Suspend(report.AuthorID, time.Now().AddDate(0, 0, int(days)))
+		if err == repository.ErrNoRowsAffected {
+			logging.LogDomainError(ErrNotExistingEntity)
+			return ErrNotExistingEntity
+		}
+		if err != nil {
+			logging.LogUnexpectedDomainError(err)
+			return ErrUnknown
+		}
+
+		notification.Type = domain.NotificationSuspension
+		serv.notifier.notify(notification)
+	}
+
+	status := domain.ReportStatusActioned
+	if action == domain.ModerationDismiss {
+		status = domain.ReportStatusDismissed
+	}
+
+	err = serv.repo.Resolve(status, domain.ModerationLogEntry{
+		ModeratorID: moderatorID,
+		ReportID:    report.ID,
+		Action:      action,
+		TargetType:  report.TargetType,
+		TargetID:    report.TargetID,
+		AuthorID:    report.AuthorID,
+		Note:        note,
+	})
+	if err == repository.ErrNoRowsAffected {
+		logging.LogDomainError(ErrArchived)
+		return ErrArchived
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return ErrUnknown
+	}
+
+	return nil
+}
+
+// Returns a page of decisions from newest to oldest, only moderators can see them, can return ErrIncorrectParameters, ErrNotAuthorized
+func (serv reportServiceImpl) GetLog(moderatorID uint, page uint) ([]domain.ModerationLogEntry, error) {
+	if moderatorID == 0 || page == 0 {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return nil, ErrIncorrectParameters
+	}
+
+	err := checkModerator(serv.userRepo, moderatorID)
+	if err != nil {
+		return nil, err
+	}
+
+	pageSize := config.GetParams().PageSize
+	entries, err := serv.repo.GetLog(pageSize, (page-1)*pageSize)
+	if err != nil && err != repository.ErrEmptySelection {
+		logging.LogUnexpectedDomainError(err)
+		return nil, ErrUnknown
+	}
+	if entries == nil {
+		entries = []domain.ModerationLogEntry{}
+	}
+
+	return entries, nil
+}
+
+func NewReportService(repo domain.ReportRepository, userRepo domain.UserRepository, postRepo domain.PostRepository, commentRepo domain.CommentRepository, profileRepo domain.ProfileRepository, notificationRepo domain.NotificationRepository, events domain.EventPublisher) domain.ReportService {
+	return reportServiceImpl{repo: repo, userRepo: userRepo, postRepo: postRepo, commentRepo: commentRepo, notifier: notifier{repo: notificationRepo, profileRepo: profileRepo, events: events}}
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/AlejandroJorge/forum-rest-api/config"
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/logging"
+	"github.com/AlejandroJorge/forum-rest-api/repository"
+)
+
+const maxSearchTerms = 10
+
+type searchServiceImpl struct {
+	repo domain.SearchRepository
+}
+
+// Matches every word of text, returns a page of results from best to worst match, pages start at 1, can return ErrIncorrectParameters, ErrNotExistingEntity
+func (serv searchServiceImpl) Search(text string, searchType domain.SearchType, filter domain.SearchFilter, page uint) ([]domain.SearchResult, error) {
+	query := buildSearchQuery(text)
+	invalidRange := !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To)
+	if query == "" || page == 0 || invalidRange {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return nil, ErrIncorrectParameters
+	}
+
+	pageSize := config.GetParams().PageSize
+	offset := (page - 1) * pageSize
+
+	var results []domain.SearchResult
+	var err error
+	switch searchType {
+	case domain.SearchTypePosts:
+		results, err = serv.repo.SearchPosts(query, filter, pageSize, offset)
+	case domain.SearchTypeComments:
+		results, err = serv.repo.SearchComments(query, filter, pageSize, offset)
+	case domain.SearchTypeProfiles:
+		results, err = serv.repo.SearchProfiles(query, pageSize, offset)
+	default:
+		logging.LogDomainError(ErrIncorrectParameters)
+		return nil, ErrIncorrectParameters
+	}
+	if err == repository.ErrEmptySelection {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return nil, ErrNotExistingEntity
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return nil, ErrUnknown
+	}
+
+	return results, nil
+}
+
+// Turns free text into an FTS5 query matching every word, the last one as a prefix, empty if there are no words
+func buildSearchQuery(text string) string {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) > maxSearchTerms {
+		words = words[:maxSearchTerms]
+	}
+
+	terms := make([]string, len(words))
+	for i, word := range words {
+		terms[i] = `"` + word + `"`
+	}
+	if len(terms) > 0 {
+		terms[len(terms)-1] += "*"
+	}
+
+	return strings.Join(terms, " ")
+}
+
+func NewSearchService(repo domain.SearchRepository) domain.SearchService {
+	return searchServiceImpl{repo: repo}
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"time"
+
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/logging"
+	"github.com/AlejandroJorge/forum-rest-api/repository"
+	"github.com/AlejandroJorge/forum-rest-api/util"
+)
+
+type tagServiceImpl struct {
+	repo domain.TagRepository
+}
+
+// Returns a slice of tags completing the prefix, can return ErrIncorrectParameters, ErrNotExistingEntity
+func (serv tagServiceImpl) Autocomplete(prefix string) ([]domain.Tag, error) {
+	prefix = util.NormalizeTag(prefix)
+	if prefix == "" {
+		logging.LogDomainError(ErrIncorrectParameters)
+		return nil, ErrIncorrectParameters
+	}
+
+	tags, err := serv.repo.GetByPrefix(prefix, 10)
+	if err == repository.ErrEmptySelection {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return nil, ErrNotExistingEntity
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return nil, ErrUnknown
+	}
+
+	return tags, nil
+}
+
+// Returns a slice of the tags with the most activity during the last week, can return ErrNotExistingEntity
+func (serv tagServiceImpl) GetTrending() ([]domain.Tag, error) {
+	tags, err := serv.repo.GetTrendingAfter(time.Now().AddDate(0, 0, -7), 10)
+	if err == repository.ErrEmptySelection {
+		logging.LogDomainError(ErrNotExistingEntity)
+		return nil, ErrNotExistingEntity
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return nil, ErrUnknown
+	}
+
+	return tags, nil
+}
+
+func NewTagService(repo domain.TagRepository) domain.TagService {
+	return tagServiceImpl{repo: repo}
+}
@@ -26,27 +26,9 @@ func (serv userServiceImpl) Authorize(id uint, jwtTokenString string) error {
 		return ErrUnknown
 	}
 
-	token, err := jwt.Parse(jwtTokenString, func(t *jwt.Token) (interface{}, error) {
-		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, ErrNotValidCredentials
-		}
-		return config.GetParams().AuthSecret, nil
-	})
+	emailFromClaims, err := emailFromToken(jwtTokenString)
 	if err != nil {
-		logging.LogDomainError(ErrNotValidCredentials)
-		return ErrNotValidCredentials
-	}
-
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
-		logging.LogDomainError(ErrNotValidCredentials)
-		return ErrNotValidCredentials
-	}
-
-	emailFromClaims, ok := claims["email"]
-	if !ok {
-		logging.LogDomainError(ErrNotValidCredentials)
-		return ErrNotValidCredentials
+		return err
 	}
 
 	if emailFromClaims != user.Email {
@@ -207,6 +189,54 @@ func (serv userServiceImpl) CheckCredentials(email, password string) error {
 	return nil
 }
 
+// Returns the ID of the user the token was issued to, can return ErrNotValidCredentials
+func (serv userServiceImpl) Identify(jwtTokenString string) (uint, error) {
+	email, err := emailFromToken(jwtTokenString)
+	if err != nil {
+		return 0, err
+	}
+
+	user, err := serv.repo.GetByEmail(email)
+	if err == repository.ErrEmptySelection {
+		logging.LogDomainError(ErrNotValidCredentials)
+		return 0, ErrNotValidCredentials
+	}
+	if err != nil {
+		logging.LogUnexpectedDomainError(err)
+		return 0, ErrUnknown
+	}
+
+	return user.ID, nil
+}
+
+// Returns the email claimed by a token signed with the auth secret, can return ErrNotValidCredentials
+func emailFromToken(jwtTokenString string) (string, error) {
+	token, err := jwt.Parse(jwtTokenString, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, ErrNotValidCredentials
+		}
+		return config.GetParams().AuthSecret, nil
+	})
+	if err != nil {
+		logging.LogDomainError(ErrNotValidCredentials)
+		return "", ErrNotValidCredentials
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		logging.LogDomainError(ErrNotValidCredentials)
+		return "", ErrNotValidCredentials
+	}
+
+	email, ok := claims["email"].(string)
+	if !ok {
+		logging.LogDomainError(ErrNotValidCredentials)
+		return "", ErrNotValidCredentials
+	}
+
+	return email, nil
+}
+
 func NewUserService(repo domain.UserRepository) domain.UserService {
 	return userServiceImpl{repo: repo}
 }
@@ -0,0 +1,6 @@
+package service
+
+// Votes are an upvote, a downvote or 0 to take the vote back
+func isValidVote(value int) bool {
+	return value == 1 || value == -1 || value == 0
+}
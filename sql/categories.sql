@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE Post ADD COLUMN Category_ID INTEGER REFERENCES Category(Category_ID);
+
+UPDATE Post SET
+  Category_ID = (SELECT MIN(Category_ID) FROM Category WHERE Parent_ID IS NULL);
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE Comment ADD COLUMN Creation_Date INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE Comment ADD COLUMN Edit_Date INTEGER;
+
+UPDATE Comment SET
+  Creation_Date = (SELECT CAST(p.Creation_Date AS INTEGER) FROM Post p WHERE p.Post_ID = Comment.Post_ID);
+
+COMMIT;
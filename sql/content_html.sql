@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE Post ADD COLUMN Content_HTML TEXT NOT NULL DEFAULT '';
+ALTER TABLE Comment ADD COLUMN Content_HTML TEXT NOT NULL DEFAULT '';
+
+COMMIT;
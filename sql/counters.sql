@@ -0,0 +1,28 @@
+BEGIN;
+
+ALTER TABLE Profile ADD COLUMN Follower_Count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE Profile ADD COLUMN Follow_Count INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE Post ADD COLUMN Upvotes INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE Post ADD COLUMN Downvotes INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE Post ADD COLUMN Comment_Count INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE Comment ADD COLUMN Upvotes INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE Comment ADD COLUMN Downvotes INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE Comment ADD COLUMN Reply_Count INTEGER NOT NULL DEFAULT 0;
+
+UPDATE Profile SET
+  Follower_Count = (SELECT COUNT(*) FROM Following f WHERE f.Followed_ID = Profile.User_ID),
+  Follow_Count = (SELECT COUNT(*) FROM Following f WHERE f.Follower_ID = Profile.User_ID);
+
+UPDATE Post SET
+  Upvotes = (SELECT COUNT(*) FROM Post_Votes v WHERE v.Post_ID = Post.Post_ID AND v.Value > 0),
+  Downvotes = (SELECT COUNT(*) FROM Post_Votes v WHERE v.Post_ID = Post.Post_ID AND v.Value < 0),
+  Comment_Count = (SELECT COUNT(*) FROM Comment c WHERE c.Post_ID = Post.Post_ID AND c.Deleted_At IS NULL);
+
+UPDATE Comment SET
+  Upvotes = (SELECT COUNT(*) FROM Comment_Votes v WHERE v.Comment_ID = Comment.Comment_ID AND v.Value > 0),
+  Downvotes = (SELECT COUNT(*) FROM Comment_Votes v WHERE v.Comment_ID = Comment.Comment_ID AND v.Value < 0),
+  Reply_Count = (SELECT COUNT(*) FROM Comment r WHERE r.Parent_ID = Comment.Comment_ID AND r.Deleted_At IS NULL);
+
+COMMIT;
@@ -0,0 +1,3 @@
+INSERT INTO Category(Name, Position, Creation_Date)
+SELECT 'General', 1, CAST(strftime('%s', 'now') AS INTEGER)
+WHERE NOT EXISTS (SELECT 1 FROM Category);
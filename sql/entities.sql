@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE Post ADD COLUMN Entities TEXT NOT NULL DEFAULT '[]';
+ALTER TABLE Comment ADD COLUMN Entities TEXT NOT NULL DEFAULT '[]';
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+ALTER TABLE Post ADD COLUMN Last_Activity INTEGER NOT NULL DEFAULT 0;
+
+UPDATE Post SET
+  Last_Activity = COALESCE(
+    (SELECT MAX(c.Creation_Date) FROM Comment c WHERE c.Post_ID = Post.Post_ID AND c.Deleted_At IS NULL), Post.Creation_Date
+  );
+
+COMMIT;
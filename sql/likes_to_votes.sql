@@ -0,0 +1,12 @@
+BEGIN;
+
+INSERT OR IGNORE INTO Post_Votes(Post_ID, Voter_ID, Value)
+SELECT Post_ID, Liker_ID, 1 FROM Post_Likings;
+
+INSERT OR IGNORE INTO Comment_Votes(Comment_ID, Voter_ID, Value)
+SELECT Comment_ID, Liker_ID, 1 FROM Comment_Likings;
+
+DROP TABLE Post_Likings;
+DROP TABLE Comment_Likings;
+
+COMMIT;
@@ -0,0 +1,24 @@
+BEGIN;
+
+INSERT OR IGNORE INTO Profile_Blocks(Blocker_ID, Blocked_ID, Block_Date)
+SELECT Blocker_ID, Blocked_ID, Block_Date FROM Message_Blocks;
+
+DELETE FROM Following WHERE EXISTS (
+  SELECT 1 FROM Message_Blocks b
+  WHERE (b.Blocker_ID = Following.Follower_ID AND b.Blocked_ID = Following.Followed_ID)
+    OR (b.Blocker_ID = Following.Followed_ID AND b.Blocked_ID = Following.Follower_ID)
+);
+
+DELETE FROM Follow_Requests WHERE EXISTS (
+  SELECT 1 FROM Message_Blocks b
+  WHERE (b.Blocker_ID = Follow_Requests.Requester_ID AND b.Blocked_ID = Follow_Requests.Target_ID)
+    OR (b.Blocker_ID = Follow_Requests.Target_ID AND b.Blocked_ID = Follow_Requests.Requester_ID)
+);
+
+UPDATE Profile SET
+  Follower_Count = (SELECT COUNT(*) FROM Following f WHERE f.Followed_ID = Profile.User_ID),
+  Follow_Count = (SELECT COUNT(*) FROM Following f WHERE f.Follower_ID = Profile.User_ID);
+
+DROP TABLE Message_Blocks;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE User ADD COLUMN Suspended_Until INTEGER;
+ALTER TABLE Post ADD COLUMN Moderation_Action TEXT;
+ALTER TABLE Comment ADD COLUMN Moderation_Action TEXT;
+
+COMMIT;
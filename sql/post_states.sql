@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE Post ADD COLUMN Is_Locked INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE Post ADD COLUMN Is_Pinned INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE Post ADD COLUMN Is_Archived INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE Post ADD COLUMN Unarchived_At INTEGER;
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE Profile ADD COLUMN Is_Private INTEGER NOT NULL DEFAULT 0;
+
+COMMIT;
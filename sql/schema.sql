@@ -2,7 +2,9 @@ CREATE TABLE IF NOT EXISTS User (
   User_ID INTEGER PRIMARY KEY AUTOINCREMENT,
   Email TEXT NOT NULL UNIQUE,
   Hashed_Password TEXT NOT NULL,
-  Registration_Date INTEGER NOT NULL
+  Registration_Date INTEGER NOT NULL,
+  Is_Moderator INTEGER NOT NULL DEFAULT 0,
+  Suspended_Until INTEGER
 );
 
 CREATE TABLE IF NOT EXISTS Profile (
@@ -11,6 +13,9 @@ CREATE TABLE IF NOT EXISTS Profile (
   Tag_Name TEXT NOT NULL UNIQUE,
   Picture_Path TEXT,
   Background_Path TEXT,
+  Follower_Count INTEGER NOT NULL DEFAULT 0,
+  Follow_Count INTEGER NOT NULL DEFAULT 0,
+  Is_Private INTEGER NOT NULL DEFAULT 0,
   FOREIGN KEY (User_ID) REFERENCES User(User_ID)
 );
 
@@ -23,37 +28,305 @@ CREATE TABLE IF NOT EXISTS Following (
   PRIMARY KEY (Followed_ID, Follower_ID)
 );
 
+CREATE TABLE IF NOT EXISTS Category (
+  Category_ID INTEGER PRIMARY KEY AUTOINCREMENT,
+  Parent_ID INTEGER,
+  Name TEXT NOT NULL,
+  Position INTEGER NOT NULL,
+  Is_Archived INTEGER NOT NULL DEFAULT 0,
+  Creation_Date INTEGER NOT NULL,
+  FOREIGN KEY (Parent_ID) REFERENCES Category(Category_ID)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS Category_Sibling_Name ON Category(COALESCE(Parent_ID, 0), Name);
+
 CREATE TABLE IF NOT EXISTS Post (
   Post_ID INTEGER PRIMARY KEY AUTOINCREMENT,
-  Title TEXT NOT NULL UNIQUE ,
+  Title TEXT NOT NULL,
+  Slug TEXT NOT NULL UNIQUE,
   Description TEXT NOT NULL,
   Content TEXT NOT NULL,
   Creation_Date TEXT NOT NULL,
   Owner_ID INTEGER NOT NULL,
-  FOREIGN KEY (Owner_ID) REFERENCES Profile(User_ID)
+  Category_ID INTEGER NOT NULL,
+  Deleted_At INTEGER,
+  Upvotes INTEGER NOT NULL DEFAULT 0,
+  Downvotes INTEGER NOT NULL DEFAULT 0,
+  Comment_Count INTEGER NOT NULL DEFAULT 0,
+  Last_Activity INTEGER NOT NULL DEFAULT 0,
+  Content_HTML TEXT NOT NULL DEFAULT '',
+  Entities TEXT NOT NULL DEFAULT '[]',
+  Moderation_Action TEXT,
+  Is_Locked INTEGER NOT NULL DEFAULT 0,
+  Is_Pinned INTEGER NOT NULL DEFAULT 0,
+  Is_Archived INTEGER NOT NULL DEFAULT 0,
+  Unarchived_At INTEGER,
+  FOREIGN KEY (Owner_ID) REFERENCES Profile(User_ID),
+  FOREIGN KEY (Category_ID) REFERENCES Category(Category_ID)
+);
+
+CREATE TABLE IF NOT EXISTS Post_Slug_History (
+  Slug TEXT PRIMARY KEY,
+  Post_ID INTEGER NOT NULL,
+  FOREIGN KEY (Post_ID) REFERENCES Post(Post_ID)
+);
+
+CREATE TABLE IF NOT EXISTS Post_Revision (
+  Post_ID INTEGER NOT NULL,
+  Revision_Number INTEGER NOT NULL,
+  Editor_ID INTEGER NOT NULL,
+  Title TEXT NOT NULL,
+  Description TEXT NOT NULL,
+  Content TEXT NOT NULL,
+  Changed_Fields TEXT NOT NULL,
+  Revision_Date INTEGER NOT NULL,
+  FOREIGN KEY (Post_ID) REFERENCES Post(Post_ID),
+  FOREIGN KEY (Editor_ID) REFERENCES Profile(User_ID),
+  PRIMARY KEY (Post_ID, Revision_Number)
+);
+
+CREATE TABLE IF NOT EXISTS Tag (
+  Tag_ID INTEGER PRIMARY KEY AUTOINCREMENT,
+  Name TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS Post_Tags (
+  Post_ID INTEGER,
+  Tag_ID INTEGER,
+  FOREIGN KEY (Post_ID) REFERENCES Post(Post_ID),
+  FOREIGN KEY (Tag_ID) REFERENCES Tag(Tag_ID),
+  PRIMARY KEY (Post_ID, Tag_ID)
+);
+
+CREATE TABLE IF NOT EXISTS Post_Score (
+  Post_ID INTEGER PRIMARY KEY,
+  Hot_Score REAL NOT NULL,
+  Top_Score REAL NOT NULL,
+  Rising_Score REAL NOT NULL,
+  Refreshed_At INTEGER NOT NULL,
+  FOREIGN KEY (Post_ID) REFERENCES Post(Post_ID)
 );
 
-CREATE TABLE IF NOT EXISTS Post_Likings (
+CREATE TABLE IF NOT EXISTS Post_Votes (
   Post_ID INTEGER,
-  Liker_ID INTEGER,
+  Voter_ID INTEGER,
+  Value INTEGER NOT NULL CHECK (Value IN (-1, 1)),
   FOREIGN KEY (Post_ID) REFERENCES Post(Post_ID),
-  FOREIGN KEY (Liker_ID) REFERENCES Profile(User_ID),
-  PRIMARY KEY (Post_ID, Liker_ID)
+  FOREIGN KEY (Voter_ID) REFERENCES Profile(User_ID),
+  PRIMARY KEY (Post_ID, Voter_ID)
 );
 
 CREATE TABLE IF NOT EXISTS Comment (
   Comment_ID INTEGER PRIMARY KEY AUTOINCREMENT,
   Post_ID INTEGER NOT NULL,
   User_ID INTEGER NOT NULL,
+  Parent_ID INTEGER,
+  Depth INTEGER NOT NULL DEFAULT 0,
   Content TEXT NOT NULL,
+  Creation_Date INTEGER NOT NULL,
+  Edit_Date INTEGER,
+  Deleted_At INTEGER,
+  Upvotes INTEGER NOT NULL DEFAULT 0,
+  Downvotes INTEGER NOT NULL DEFAULT 0,
+  Reply_Count INTEGER NOT NULL DEFAULT 0,
+  Content_HTML TEXT NOT NULL DEFAULT '',
+  Entities TEXT NOT NULL DEFAULT '[]',
+  Moderation_Action TEXT,
   FOREIGN KEY (Post_ID) REFERENCES Post(Post_ID),
-  FOREIGN KEY (User_ID) REFERENCES Profile(User_ID)
+  FOREIGN KEY (User_ID) REFERENCES Profile(User_ID),
+  FOREIGN KEY (Parent_ID) REFERENCES Comment(Comment_ID) ON DELETE SET NULL
 );
 
-CREATE TABLE IF NOT EXISTS Comment_Likings (
+CREATE TABLE IF NOT EXISTS Comment_Revision (
+  Revision_ID INTEGER PRIMARY KEY AUTOINCREMENT,
+  Comment_ID INTEGER NOT NULL,
+  Content TEXT NOT NULL,
+  Revision_Date INTEGER NOT NULL,
+  FOREIGN KEY (Comment_ID) REFERENCES Comment(Comment_ID)
+);
+
+CREATE TABLE IF NOT EXISTS Comment_Votes (
   Comment_ID INTEGER,
-  Liker_ID INTEGER,
+  Voter_ID INTEGER,
+  Value INTEGER NOT NULL CHECK (Value IN (-1, 1)),
   FOREIGN KEY (Comment_ID) REFERENCES Comment(Comment_ID),
-  FOREIGN KEY (Liker_ID) REFERENCES Profile(User_ID),
-  PRIMARY KEY (Comment_ID, Liker_ID)
+  FOREIGN KEY (Voter_ID) REFERENCES Profile(User_ID),
+  PRIMARY KEY (Comment_ID, Voter_ID)
+);
+
+CREATE TABLE IF NOT EXISTS Post_Mentions (
+  Post_ID INTEGER,
+  User_ID INTEGER,
+  FOREIGN KEY (Post_ID) REFERENCES Post(Post_ID),
+  FOREIGN KEY (User_ID) REFERENCES Profile(User_ID),
+  PRIMARY KEY (Post_ID, User_ID)
+);
+
+CREATE TABLE IF NOT EXISTS Post_Hashtags (
+  Post_ID INTEGER,
+  Hashtag TEXT,
+  FOREIGN KEY (Post_ID) REFERENCES Post(Post_ID),
+  PRIMARY KEY (Post_ID, Hashtag)
+);
+
+CREATE TABLE IF NOT EXISTS Comment_Mentions (
+  Comment_ID INTEGER,
+  User_ID INTEGER,
+  FOREIGN KEY (Comment_ID) REFERENCES Comment(Comment_ID),
+  FOREIGN KEY (User_ID) REFERENCES Profile(User_ID),
+  PRIMARY KEY (Comment_ID, User_ID)
+);
+
+CREATE TABLE IF NOT EXISTS Comment_Hashtags (
+  Comment_ID INTEGER,
+  Hashtag TEXT,
+  FOREIGN KEY (Comment_ID) REFERENCES Comment(Comment_ID),
+  PRIMARY KEY (Comment_ID, Hashtag)
+);
+
+CREATE TABLE IF NOT EXISTS Notification (
+  Notification_ID INTEGER PRIMARY KEY AUTOINCREMENT,
+  User_ID INTEGER NOT NULL,
+  Actor_ID INTEGER NOT NULL,
+  Type TEXT NOT NULL,
+  Post_ID INTEGER NOT NULL DEFAULT 0,
+  Comment_ID INTEGER NOT NULL DEFAULT 0,
+  Is_Read INTEGER NOT NULL DEFAULT 0,
+  Creation_Date INTEGER NOT NULL,
+  FOREIGN KEY (User_ID) REFERENCES Profile(User_ID),
+  FOREIGN KEY (Actor_ID) REFERENCES Profile(User_ID),
+  UNIQUE (User_ID, Actor_ID, Type, Post_ID, Comment_ID)
+);
+
+CREATE INDEX IF NOT EXISTS Notification_By_User ON Notification(User_ID, Is_Read);
+
+CREATE TABLE IF NOT EXISTS Notification_Preferences (
+  User_ID INTEGER PRIMARY KEY,
+  Reply_Enabled INTEGER NOT NULL DEFAULT 1,
+  Like_Enabled INTEGER NOT NULL DEFAULT 1,
+  Follow_Enabled INTEGER NOT NULL DEFAULT 1,
+  Mention_Enabled INTEGER NOT NULL DEFAULT 1,
+  FOREIGN KEY (User_ID) REFERENCES Profile(User_ID)
+);
+
+CREATE TABLE IF NOT EXISTS Media (
+  Media_ID INTEGER PRIMARY KEY AUTOINCREMENT,
+  Owner_ID INTEGER NOT NULL,
+  Blob_Key TEXT NOT NULL,
+  Thumbnail_Key TEXT NOT NULL,
+  Content_Type TEXT NOT NULL,
+  Size INTEGER NOT NULL,
+  Width INTEGER NOT NULL,
+  Height INTEGER NOT NULL,
+  Creation_Date INTEGER NOT NULL,
+  FOREIGN KEY (Owner_ID) REFERENCES User(User_ID),
+  UNIQUE (Owner_ID, Blob_Key)
+);
+
+CREATE TABLE IF NOT EXISTS Post_Attachments (
+  Post_ID INTEGER,
+  Media_ID INTEGER,
+  Position INTEGER NOT NULL,
+  FOREIGN KEY (Post_ID) REFERENCES Post(Post_ID),
+  FOREIGN KEY (Media_ID) REFERENCES Media(Media_ID),
+  PRIMARY KEY (Post_ID, Media_ID)
+);
+
+CREATE TABLE IF NOT EXISTS Conversation (
+  Conversation_ID INTEGER PRIMARY KEY AUTOINCREMENT,
+  Creator_ID INTEGER NOT NULL,
+  Is_Group INTEGER NOT NULL DEFAULT 0,
+  Creation_Date INTEGER NOT NULL,
+  Last_Message_At INTEGER NOT NULL,
+  FOREIGN KEY (Creator_ID) REFERENCES Profile(User_ID)
+);
+
+CREATE TABLE IF NOT EXISTS Conversation_Members (
+  Conversation_ID INTEGER,
+  User_ID INTEGER,
+  Last_Read_Message_ID INTEGER NOT NULL DEFAULT 0,
+  Last_Read_At INTEGER NOT NULL DEFAULT 0,
+  FOREIGN KEY (Conversation_ID) REFERENCES Conversation(Conversation_ID),
+  FOREIGN KEY (User_ID) REFERENCES Profile(User_ID),
+  PRIMARY KEY (Conversation_ID, User_ID)
+);
+
+CREATE INDEX IF NOT EXISTS Conversation_Members_By_User ON Conversation_Members(User_ID);
+
+CREATE TABLE IF NOT EXISTS Message (
+  Message_ID INTEGER PRIMARY KEY AUTOINCREMENT,
+  Conversation_ID INTEGER NOT NULL,
+  Sender_ID INTEGER NOT NULL,
+  Content TEXT NOT NULL,
+  Creation_Date INTEGER NOT NULL,
+  FOREIGN KEY (Conversation_ID) REFERENCES Conversation(Conversation_ID),
+  FOREIGN KEY (Sender_ID) REFERENCES Profile(User_ID)
+);
+
+CREATE INDEX IF NOT EXISTS Message_By_Conversation ON Message(Conversation_ID, Message_ID);
+
+CREATE TABLE IF NOT EXISTS Profile_Blocks (
+  Blocker_ID INTEGER,
+  Blocked_ID INTEGER,
+  Block_Date INTEGER NOT NULL,
+  FOREIGN KEY (Blocker_ID) REFERENCES Profile(User_ID),
+  FOREIGN KEY (Blocked_ID) REFERENCES Profile(User_ID),
+  PRIMARY KEY (Blocker_ID, Blocked_ID)
+);
+
+CREATE TABLE IF NOT EXISTS Profile_Mutes (
+  Muter_ID INTEGER,
+  Muted_ID INTEGER,
+  Mute_Date INTEGER NOT NULL,
+  FOREIGN KEY (Muter_ID) REFERENCES Profile(User_ID),
+  FOREIGN KEY (Muted_ID) REFERENCES Profile(User_ID),
+  PRIMARY KEY (Muter_ID, Muted_ID)
+);
+
+CREATE TABLE IF NOT EXISTS Follow_Requests (
+  Requester_ID INTEGER,
+  Target_ID INTEGER,
+  Request_Date INTEGER NOT NULL,
+  FOREIGN KEY (Requester_ID) REFERENCES Profile(User_ID),
+  FOREIGN KEY (Target_ID) REFERENCES Profile(User_ID),
+  PRIMARY KEY (Requester_ID, Target_ID)
+);
+
+CREATE TABLE IF NOT EXISTS Report (
+  Report_ID INTEGER PRIMARY KEY AUTOINCREMENT,
+  Target_Type TEXT NOT NULL,
+  Target_ID INTEGER NOT NULL,
+  Author_ID INTEGER NOT NULL,
+  Status TEXT NOT NULL DEFAULT 'open',
+  Reporter_Count INTEGER NOT NULL DEFAULT 0,
+  Creation_Date INTEGER NOT NULL,
+  Resolution_Date INTEGER,
+  FOREIGN KEY (Author_ID) REFERENCES Profile(User_ID)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS Report_Open_Target ON Report(Target_Type, Target_ID) WHERE Status = 'open';
+
+CREATE TABLE IF NOT EXISTS Report_Reasons (
+  Report_ID INTEGER,
+  Reporter_ID INTEGER,
+  Reason TEXT NOT NULL,
+  Details TEXT NOT NULL DEFAULT '',
+  Report_Date INTEGER NOT NULL,
+  FOREIGN KEY (Report_ID) REFERENCES Report(Report_ID),
+  FOREIGN KEY (Reporter_ID) REFERENCES User(User_ID),
+  PRIMARY KEY (Report_ID, Reporter_ID)
+);
+
+CREATE TABLE IF NOT EXISTS Moderation_Log (
+  Log_ID INTEGER PRIMARY KEY AUTOINCREMENT,
+  Moderator_ID INTEGER NOT NULL,
+  Report_ID INTEGER NOT NULL,
+  Action TEXT NOT NULL,
+  Target_Type TEXT NOT NULL,
+  Target_ID INTEGER NOT NULL,
+  Author_ID INTEGER NOT NULL,
+  Note TEXT NOT NULL DEFAULT '',
+  Action_Date INTEGER NOT NULL,
+  FOREIGN KEY (Moderator_ID) REFERENCES User(User_ID),
+  FOREIGN KEY (Report_ID) REFERENCES Report(Report_ID)
 );
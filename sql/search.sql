@@ -0,0 +1,71 @@
+CREATE VIRTUAL TABLE IF NOT EXISTS Post_Search USING fts5(
+  Title,
+  Description,
+  Content,
+  content='Post',
+  content_rowid='Post_ID'
+);
+
+CREATE TRIGGER IF NOT EXISTS Post_Search_Insert AFTER INSERT ON Post BEGIN
+  INSERT INTO Post_Search(rowid, Title, Description, Content)
+  VALUES (new.Post_ID, new.Title, new.Description, new.Content);
+END;
+
+CREATE TRIGGER IF NOT EXISTS Post_Search_Delete AFTER DELETE ON Post BEGIN
+  INSERT INTO Post_Search(Post_Search, rowid, Title, Description, Content)
+  VALUES ('delete', old.Post_ID, old.Title, old.Description, old.Content);
+END;
+
+CREATE TRIGGER IF NOT EXISTS Post_Search_Update AFTER UPDATE OF Title, Description, Content ON Post BEGIN
+  INSERT INTO Post_Search(Post_Search, rowid, Title, Description, Content)
+  VALUES ('delete', old.Post_ID, old.Title, old.Description, old.Content);
+  INSERT INTO Post_Search(rowid, Title, Description, Content)
+  VALUES (new.Post_ID, new.Title, new.Description, new.Content);
+END;
+
+CREATE VIRTUAL TABLE IF NOT EXISTS Comment_Search USING fts5(
+  Content,
+  content='Comment',
+  content_rowid='Comment_ID'
+);
+
+CREATE TRIGGER IF NOT EXISTS Comment_Search_Insert AFTER INSERT ON Comment BEGIN
+  INSERT INTO Comment_Search(rowid, Content)
+  VALUES (new.Comment_ID, new.Content);
+END;
+
+CREATE TRIGGER IF NOT EXISTS Comment_Search_Delete AFTER DELETE ON Comment BEGIN
+  INSERT INTO Comment_Search(Comment_Search, rowid, Content)
+  VALUES ('delete', old.Comment_ID, old.Content);
+END;
+
+CREATE TRIGGER IF NOT EXISTS Comment_Search_Update AFTER UPDATE OF Content ON Comment BEGIN
+  INSERT INTO Comment_Search(Comment_Search, rowid, Content)
+  VALUES ('delete', old.Comment_ID, old.Content);
+  INSERT INTO Comment_Search(rowid, Content)
+  VALUES (new.Comment_ID, new.Content);
+END;
+
+CREATE VIRTUAL TABLE IF NOT EXISTS Profile_Search USING fts5(
+  Display_Name,
+  Tag_Name,
+  content='Profile',
+  content_rowid='User_ID'
+);
+
+CREATE TRIGGER IF NOT EXISTS Profile_Search_Insert AFTER INSERT ON Profile BEGIN
+  INSERT INTO Profile_Search(rowid, Display_Name, Tag_Name)
+  VALUES (new.User_ID, new.Display_Name, new.Tag_Name);
+END;
+
+CREATE TRIGGER IF NOT EXISTS Profile_Search_Delete AFTER DELETE ON Profile BEGIN
+  INSERT INTO Profile_Search(Profile_Search, rowid, Display_Name, Tag_Name)
+  VALUES ('delete', old.User_ID, old.Display_Name, old.Tag_Name);
+END;
+
+CREATE TRIGGER IF NOT EXISTS Profile_Search_Update AFTER UPDATE OF Display_Name, Tag_Name ON Profile BEGIN
+  INSERT INTO Profile_Search(Profile_Search, rowid, Display_Name, Tag_Name)
+  VALUES ('delete', old.User_ID, old.Display_Name, old.Tag_Name);
+  INSERT INTO Profile_Search(rowid, Display_Name, Tag_Name)
+  VALUES (new.User_ID, new.Display_Name, new.Tag_Name);
+END;
@@ -0,0 +1,5 @@
+INSERT INTO Post_Search(Post_Search) VALUES ('rebuild');
+
+INSERT INTO Comment_Search(Comment_Search) VALUES ('rebuild');
+
+INSERT INTO Profile_Search(Profile_Search) VALUES ('rebuild');
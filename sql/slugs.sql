@@ -0,0 +1,26 @@
+PRAGMA foreign_keys = OFF;
+
+BEGIN;
+
+CREATE TABLE Post_With_Slug (
+  Post_ID INTEGER PRIMARY KEY AUTOINCREMENT,
+  Title TEXT NOT NULL,
+  Slug TEXT NOT NULL UNIQUE,
+  Description TEXT NOT NULL,
+  Content TEXT NOT NULL,
+  Creation_Date TEXT NOT NULL,
+  Owner_ID INTEGER NOT NULL,
+  Deleted_At INTEGER,
+  FOREIGN KEY (Owner_ID) REFERENCES Profile(User_ID)
+);
+
+INSERT INTO Post_With_Slug(Post_ID, Title, Slug, Description, Content, Creation_Date, Owner_ID, Deleted_At)
+SELECT Post_ID, Title, '_' || Post_ID, Description, Content, Creation_Date, Owner_ID, Deleted_At FROM Post;
+
+DROP TABLE Post;
+
+ALTER TABLE Post_With_Slug RENAME TO Post;
+
+COMMIT;
+
+PRAGMA foreign_keys = ON;
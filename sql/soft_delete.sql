@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE User ADD COLUMN Is_Moderator INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE Post ADD COLUMN Deleted_At INTEGER;
+ALTER TABLE Comment ADD COLUMN Deleted_At INTEGER;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE Comment ADD COLUMN Parent_ID INTEGER REFERENCES Comment(Comment_ID) ON DELETE SET NULL;
+ALTER TABLE Comment ADD COLUMN Depth INTEGER NOT NULL DEFAULT 0;
+
+COMMIT;
@@ -0,0 +1,7 @@
+package storage
+
+import "errors"
+
+var ErrBlobNotFound = errors.New("Nothing is stored under the key")
+
+var ErrInvalidKey = errors.New("The key isn't a valid blob key")
@@ -0,0 +1,18 @@
+package storage
+
+// Keys are generated from hashes, anything else could escape the store
+func isValidKey(key string) bool {
+	if len(key) < 3 || key[0] == '.' {
+		return false
+	}
+
+	for _, char := range key {
+		isLower := char >= 'a' && char <= 'z'
+		isDigit := char >= '0' && char <= '9'
+		if !isLower && !isDigit && char != '.' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,76 @@
+package storage
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+)
+
+type localBlobStore struct {
+	folder string
+}
+
+// Writes to a temporary file first so readers never see partial blobs, can return ErrInvalidKey
+func (store localBlobStore) Put(key, contentType string, data []byte) error {
+	path, err := store.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// Can return ErrBlobNotFound, ErrInvalidKey
+func (store localBlobStore) Get(key string) (io.ReadCloser, error) {
+	path, err := store.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, ErrBlobNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// Blobs are spread in folders by the first characters of their key
+func (store localBlobStore) path(key string) (string, error) {
+	if !isValidKey(key) {
+		return "", ErrInvalidKey
+	}
+
+	return filepath.Join(store.folder, key[:2], key), nil
+}
+
+func NewLocalBlobStore(folder string) domain.BlobStore {
+	return localBlobStore{folder: folder}
+}
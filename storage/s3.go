@@ -0,0 +1,143 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+)
+
+// Stores blobs as objects of a bucket on any S3-compatible endpoint, addressed by path and signed with Signature Version 4
+type s3BlobStore struct {
+	endpoint        string
+	bucket          string
+	region          string
+	accessKeyID     string
+	secretAccessKey string
+	client          *http.Client
+}
+
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// Can return ErrInvalidKey
+func (store s3BlobStore) Put(key, contentType string, data []byte) error {
+	if !isValidKey(key) {
+		return ErrInvalidKey
+	}
+
+	req, err := http.NewRequest(http.MethodPut, store.objectURL(key), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	sum := sha256.Sum256(data)
+	store.sign(req, hex.EncodeToString(sum[:]), time.Now())
+
+	res, err := store.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return s3Error(res)
+	}
+
+	return nil
+}
+
+// Can return ErrBlobNotFound, ErrInvalidKey
+func (store s3BlobStore) Get(key string) (io.ReadCloser, error) {
+	if !isValidKey(key) {
+		return nil, ErrInvalidKey
+	}
+
+	req, err := http.NewRequest(http.MethodGet, store.objectURL(key), nil)
+	if err != nil {
+		return nil, err
+	}
+	store.sign(req, emptyPayloadHash, time.Now())
+
+	res, err := store.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode == http.StatusNotFound {
+		res.Body.Close()
+		return nil, ErrBlobNotFound
+	}
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		return nil, s3Error(res)
+	}
+
+	return res.Body, nil
+}
+
+func (store s3BlobStore) objectURL(key string) string {
+	return store.endpoint + "/" + store.bucket + "/" + key
+}
+
+// Adds the Signature Version 4 headers, only host and the x-amz headers are signed
+func (store s3BlobStore) sign(req *http.Request, payloadHash string, moment time.Time) {
+	moment = moment.UTC()
+	amzDate := moment.Format("20060102T150405Z")
+	date := moment.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		"host:" + req.URL.Host + "\n" + "x-amz-content-sha256:" + payloadHash + "\n" + "x-amz-date:" + amzDate + "\n",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + store.region + "/s3/aws4_request"
+	canonicalSum := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(canonicalSum[:])
+
+	signingKey := hmacSHA256([]byte("AWS4"+store.secretAccessKey), date)
+	signingKey = hmacSHA256(signingKey, store.region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		store.accessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func s3Error(res *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+	return fmt.Errorf("S3 responded %s: %s", res.Status, strings.TrimSpace(string(body)))
+}
+
+func NewS3BlobStore(endpoint, bucket, region, accessKeyID, secretAccessKey string) domain.BlobStore {
+	return s3BlobStore{
+		endpoint:        strings.TrimSuffix(endpoint, "/"),
+		bucket:          bucket,
+		region:          region,
+		accessKeyID:     accessKeyID,
+		secretAccessKey: secretAccessKey,
+		client:          &http.Client{Timeout: 30 * time.Second},
+	}
+}
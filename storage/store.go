@@ -0,0 +1,21 @@
+package storage
+
+import (
+	"github.com/AlejandroJorge/forum-rest-api/config"
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+)
+
+var defaultStore domain.BlobStore
+
+// Returns the store chosen by MEDIA_STORE, shared by the whole server
+func DefaultStore() domain.BlobStore {
+	if defaultStore == nil {
+		params := config.GetParams()
+		if params.MediaStore == "s3" {
+			defaultStore = NewS3BlobStore(params.S3Endpoint, params.S3Bucket, params.S3Region, params.S3AccessKeyID, params.S3SecretAccessKey)
+		} else {
+			defaultStore = NewLocalBlobStore(params.MediaFolderName)
+		}
+	}
+	return defaultStore
+}
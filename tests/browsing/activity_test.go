@@ -0,0 +1,59 @@
+package browsing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/tests"
+)
+
+// Moves the creation and last activity of the post back in time
+func backdatePost(postID uint, age time.Duration, t *testing.T) {
+	moment := time.Now().Add(-age).Unix()
+	_, err := tests.MockSQLiteDatabase().Exec(`UPDATE Post SET Creation_Date = ?, Last_Activity = ? WHERE Post_ID = ?`, moment, moment, postID)
+	tests.EndTestIfError(err, t)
+}
+
+// Returns the IDs of the posts in the category sorted by the order
+func sortedCategoryPosts(categoryID uint, order domain.PostSort, t *testing.T) []uint {
+	posts, err := tests.MockPostService().GetPopularInCategory(0, categoryID, domain.PopularPeriodAllTime, order)
+	tests.EndTestIfError(err, t)
+
+	ids := make([]uint, len(posts))
+	for i, post := range posts {
+		ids[i] = post.PostID
+	}
+
+	return ids
+}
+
+func TestActiveSortFollowsTheLatestComment(t *testing.T) {
+	owner := tests.CreateMockProfile(t)
+	categoryID := tests.CreateMockCategory(t)
+	quiet := tests.CreateMockPost(t, owner, categoryID, tests.UniqueName("post"), "content")
+	recent := tests.CreateMockPost(t, owner, categoryID, tests.UniqueName("post"), "content")
+	backdatePost(quiet, 2*time.Hour, t)
+	backdatePost(recent, time.Hour, t)
+
+	ids := sortedCategoryPosts(categoryID, domain.PostSortActive, t)
+	tests.AssertEqu(2, len(ids), t)
+	tests.AssertEqu(recent, ids[0], t)
+
+	_, err := tests.MockCommentService().Create(owner, quiet, 0, "bump")
+	tests.EndTestIfError(err, t)
+
+	post, err := tests.MockPostService().GetByID(quiet)
+	tests.EndTestIfError(err, t)
+	if time.Since(post.LastActivityAt) > time.Minute {
+		t.Errorf("Expected the comment to update the last activity, got '%v'", post.LastActivityAt)
+	}
+
+	ids = sortedCategoryPosts(categoryID, domain.PostSortActive, t)
+	tests.AssertEqu(quiet, ids[0], t)
+	tests.AssertEqu(recent, ids[1], t)
+
+	// Creation order doesn't change
+	ids = sortedCategoryPosts(categoryID, domain.PostSortNew, t)
+	tests.AssertEqu(recent, ids[0], t)
+}
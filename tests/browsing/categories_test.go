@@ -0,0 +1,52 @@
+package browsing
+
+import (
+	"testing"
+
+	"github.com/AlejandroJorge/forum-rest-api/config"
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/repository"
+	"github.com/AlejandroJorge/forum-rest-api/service"
+	"github.com/AlejandroJorge/forum-rest-api/tests"
+)
+
+func TestMain(t *testing.M) {
+	tests.FixWorkingDir()
+	config.InitializeAll()
+	tests.RunMockSQLiteMigration()
+	t.Run()
+}
+
+func postIDs(posts []domain.Post) map[uint]bool {
+	ids := make(map[uint]bool)
+	for _, post := range posts {
+		ids[post.PostID] = true
+	}
+
+	return ids
+}
+
+func TestCategoriesIncludeSubcategories(t *testing.T) {
+	postServ := tests.MockPostService()
+	owner := tests.CreateMockProfile(t)
+	parentID := tests.CreateMockCategory(t)
+	childID, err := repository.NewSQLiteCategoryRepository(tests.MockSQLiteDatabase()).Create(parentID, tests.UniqueName("child"))
+	tests.EndTestIfError(err, t)
+
+	inParent := tests.CreateMockPost(t, owner, parentID, tests.UniqueName("post"), "content")
+	inChild := tests.CreateMockPost(t, owner, childID, tests.UniqueName("post"), "content")
+
+	posts, err := postServ.GetPopularInCategory(0, parentID, domain.PopularPeriodAllTime, domain.PostSortNew)
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(2, len(posts), t)
+	ids := postIDs(posts)
+	tests.AssertEqu(true, ids[inParent] && ids[inChild], t)
+
+	posts, err = postServ.GetPopularInCategory(0, childID, domain.PopularPeriodAllTime, domain.PostSortNew)
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(1, len(posts), t)
+	tests.AssertEqu(inChild, posts[0].PostID, t)
+
+	_, err = postServ.Create(owner, childID+1000000, tests.UniqueName("post"), "description", "content", nil, nil)
+	tests.AssertEqu(service.ErrDependencyNotSatisfied, err, t)
+}
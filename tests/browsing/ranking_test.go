@@ -0,0 +1,36 @@
+package browsing
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/tests"
+)
+
+func TestHotSortFollowsRefreshedScores(t *testing.T) {
+	postServ := tests.MockPostService()
+	owner := tests.CreateMockProfile(t)
+	voter := tests.CreateMockProfile(t)
+	categoryID := tests.CreateMockCategory(t)
+	liked := tests.CreateMockPost(t, owner, categoryID, tests.UniqueName("post"), "content")
+	newest := tests.CreateMockPost(t, owner, categoryID, tests.UniqueName("post"), "content")
+	tests.EndTestIfError(postServ.Vote(voter, liked, 1), t)
+
+	_, err := postServ.RefreshScores()
+	tests.EndTestIfError(err, t)
+
+	recorder := httptest.NewRecorder()
+	path := fmt.Sprintf("/api/v1/categories/%d/posts/alltime?sort=hot", categoryID)
+	tests.MockRouter().ServeHTTP(recorder, httptest.NewRequest("GET", path, nil))
+	tests.AssertEqu(http.StatusOK, recorder.Code, t)
+
+	var posts []domain.Post
+	tests.EndTestIfError(json.NewDecoder(recorder.Body).Decode(&posts), t)
+	tests.AssertEqu(2, len(posts), t)
+	tests.AssertEqu(liked, posts[0].PostID, t)
+	tests.AssertEqu(newest, posts[1].PostID, t)
+}
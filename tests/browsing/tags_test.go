@@ -0,0 +1,32 @@
+package browsing
+
+import (
+	"testing"
+
+	"github.com/AlejandroJorge/forum-rest-api/service"
+	"github.com/AlejandroJorge/forum-rest-api/tests"
+)
+
+func TestTagsAreNormalized(t *testing.T) {
+	postServ := tests.MockPostService()
+	owner := tests.CreateMockProfile(t)
+	tag := tests.UniqueName("go-")
+
+	postID, err := postServ.Create(owner, tests.CreateMockCategory(t), tests.UniqueName("post"), "description", "content", []string{" " + tag + " ", "Web Dev", "web-dev"}, nil)
+	tests.EndTestIfError(err, t)
+
+	post, err := postServ.GetByID(postID)
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(2, len(post.Tags), t)
+	tests.AssertEqu(tag, post.Tags[0], t)
+	tests.AssertEqu("web-dev", post.Tags[1], t)
+
+	posts, err := postServ.GetByTag(0, tag, 1)
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(1, len(posts), t)
+	tests.AssertEqu(postID, posts[0].PostID, t)
+
+	tests.EndTestIfError(postServ.UpdateTags(owner, postID, []string{"other"}), t)
+	_, err = postServ.GetByTag(0, tag, 1)
+	tests.AssertEqu(service.ErrNotExistingEntity, err, t)
+}
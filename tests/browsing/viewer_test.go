@@ -0,0 +1,37 @@
+package browsing
+
+import (
+	"testing"
+
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/tests"
+)
+
+func TestViewerFieldsDependOnTheViewer(t *testing.T) {
+	postServ := tests.MockPostService()
+	owner := tests.CreateMockProfile(t)
+	voter := tests.CreateMockProfile(t)
+	postID, err := postServ.Create(owner, tests.CreateMockCategory(t), tests.UniqueName("post"), "description", "content", nil, nil)
+	tests.EndTestIfError(err, t)
+
+	tests.EndTestIfError(postServ.Vote(voter, postID, 1), t)
+	_, err = tests.MockProfileService().AddFollow(voter, owner)
+	tests.EndTestIfError(err, t)
+
+	post, err := postServ.GetByID(postID)
+	tests.EndTestIfError(err, t)
+
+	posts := []domain.Post{post}
+	tests.EndTestIfError(postServ.AddViewer(voter, posts), t)
+	tests.AssertEqu(1, posts[0].MyVote, t)
+	tests.AssertEqu(true, posts[0].LikedByMe, t)
+	tests.AssertEqu(false, posts[0].IsOwner, t)
+	tests.AssertEqu(true, posts[0].FollowsAuthor, t)
+
+	posts = []domain.Post{post}
+	tests.EndTestIfError(postServ.AddViewer(owner, posts), t)
+	tests.AssertEqu(0, posts[0].MyVote, t)
+	tests.AssertEqu(false, posts[0].LikedByMe, t)
+	tests.AssertEqu(true, posts[0].IsOwner, t)
+	tests.AssertEqu(false, posts[0].FollowsAuthor, t)
+}
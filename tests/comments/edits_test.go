@@ -0,0 +1,54 @@
+package comments
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/AlejandroJorge/forum-rest-api/config"
+	"github.com/AlejandroJorge/forum-rest-api/service"
+	"github.com/AlejandroJorge/forum-rest-api/tests"
+)
+
+func TestMain(t *testing.M) {
+	tests.FixWorkingDir()
+	config.InitializeAll()
+	tests.RunMockSQLiteMigration()
+	t.Run()
+}
+
+func TestEditsKeepRevisions(t *testing.T) {
+	commentServ := tests.MockCommentService()
+	author := tests.CreateMockProfile(t)
+	stranger := tests.CreateMockProfile(t)
+	postID := tests.CreateMockPost(t, author, tests.CreateMockCategory(t), tests.UniqueName("post"), "content")
+	commentID, err := commentServ.Create(author, postID, 0, "first")
+	tests.EndTestIfError(err, t)
+
+	// Comments that were never edited leave EditedAt out
+	comment, err := commentServ.GetByID(commentID)
+	tests.EndTestIfError(err, t)
+	if comment.EditedAt != nil {
+		t.Errorf("Expected no edit date, got %v", comment.EditedAt)
+	}
+	serialized, err := json.Marshal(comment)
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(false, strings.Contains(string(serialized), "EditedAt"), t)
+
+	tests.AssertEqu(service.ErrNotAuthorized, commentServ.Update(stranger, commentID, "hijacked"), t)
+	tests.EndTestIfError(commentServ.Update(author, commentID, "second"), t)
+	tests.EndTestIfError(commentServ.Update(author, commentID, "third"), t)
+
+	comment, err = commentServ.GetByID(commentID)
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu("third", comment.Content, t)
+	if comment.EditedAt == nil || comment.EditedAt.Before(comment.CreatedAt) {
+		t.Errorf("Expected an edit date after the creation, got %v", comment.EditedAt)
+	}
+
+	revisions, err := commentServ.GetRevisions(commentID)
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(2, len(revisions), t)
+	tests.AssertEqu("first", revisions[0].Content, t)
+	tests.AssertEqu("second", revisions[1].Content, t)
+}
@@ -0,0 +1,161 @@
+package counters
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/AlejandroJorge/forum-rest-api/config"
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/repository"
+	"github.com/AlejandroJorge/forum-rest-api/tests"
+)
+
+func TestMain(t *testing.M) {
+	tests.FixWorkingDir()
+	config.InitializeAll()
+	tests.RunMockSQLiteMigration()
+	t.Run()
+}
+
+// Creates amount profiles with the shared fixture, returns their IDs
+func createProfiles(amount int, t *testing.T) []uint {
+	ids := make([]uint, amount)
+	for i := range ids {
+		ids[i] = tests.CreateMockProfile(t)
+	}
+
+	return ids
+}
+
+// Runs action once for every id at the same time and fails on any error
+func concurrently(ids []uint, action func(id uint) error, t *testing.T) {
+	var wg sync.WaitGroup
+	errs := make(chan error, len(ids))
+	for _, id := range ids {
+		wg.Add(1)
+		go func(id uint) {
+			defer wg.Done()
+			errs <- action(id)
+		}(id)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		tests.EndTestIfError(err, t)
+	}
+}
+
+func TestConcurrentPostVotes(t *testing.T) {
+	postRepo := repository.NewSQLitePostRepository(tests.MockSQLiteDatabase())
+	voters := createProfiles(40, t)
+	postID := tests.CreateMockPost(t, voters[0], tests.CreateMockCategory(t), tests.UniqueName("post"), "content")
+
+	concurrently(voters, func(id uint) error {
+		return postRepo.Vote(id, postID, 1)
+	}, t)
+
+	post, err := postRepo.GetByID(postID)
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(uint(40), post.Upvotes, t)
+	tests.AssertEqu(uint(0), post.Downvotes, t)
+	tests.AssertEqu(40, post.Score, t)
+
+	// Half switch to a downvote and the other half take their vote back, twice to check votes are idempotent
+	for i := 0; i < 2; i++ {
+		concurrently(voters, func(id uint) error {
+			if id%2 == 0 {
+				return postRepo.Vote(id, postID, -1)
+			}
+			return postRepo.Vote(id, postID, 0)
+		}, t)
+	}
+
+	post, err = postRepo.GetByID(postID)
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(uint(0), post.Upvotes, t)
+	tests.AssertEqu(uint(20), post.Downvotes, t)
+	tests.AssertEqu(-20, post.Score, t)
+}
+
+func TestConcurrentCommentsAndCommentVotes(t *testing.T) {
+	db := tests.MockSQLiteDatabase()
+	commentRepo := repository.NewSQLiteCommentRepository(db)
+	users := createProfiles(30, t)
+	postID := tests.CreateMockPost(t, users[0], tests.CreateMockCategory(t), tests.UniqueName("post"), "content")
+	parentID, err := commentRepo.Create(postID, users[0], 0, "parent")
+	tests.EndTestIfError(err, t)
+
+	concurrently(users, func(id uint) error {
+		_, err := commentRepo.Create(postID, id, parentID, "reply")
+		if err != nil {
+			return err
+		}
+		return commentRepo.Vote(id, parentID, 1)
+	}, t)
+
+	var commentCount uint
+	err = db.QueryRow(`SELECT Comment_Count FROM Post WHERE Post_ID = ?`, postID).Scan(&commentCount)
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(uint(31), commentCount, t)
+
+	parent, err := commentRepo.GetByID(parentID)
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(uint(30), parent.ReplyCount, t)
+	tests.AssertEqu(uint(30), parent.Upvotes, t)
+}
+
+func TestConcurrentFollows(t *testing.T) {
+	profileRepo := repository.NewSQLiteProfileRepository(tests.MockSQLiteDatabase())
+	followers := createProfiles(30, t)
+	followedID := createProfiles(1, t)[0]
+
+	concurrently(followers, func(id uint) error {
+		return profileRepo.AddFollow(id, followedID)
+	}, t)
+
+	followed, err := profileRepo.GetByUserID(followedID)
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(uint(30), followed.Followers, t)
+
+	concurrently(followers[:10], func(id uint) error {
+		return profileRepo.DeleteFollow(id, followedID)
+	}, t)
+
+	followed, err = profileRepo.GetByUserID(followedID)
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(uint(20), followed.Followers, t)
+
+	follower, err := profileRepo.GetByUserID(followers[len(followers)-1])
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(uint(1), follower.Follows, t)
+}
+
+func TestReconcileRepairsDrift(t *testing.T) {
+	db := tests.MockSQLiteDatabase()
+	postRepo := repository.NewSQLitePostRepository(db)
+	counterRepo := repository.NewSQLiteCounterRepository(db)
+	voters := createProfiles(3, t)
+	postID := tests.CreateMockPost(t, voters[0], tests.CreateMockCategory(t), tests.UniqueName("post"), "content")
+	for _, voterID := range voters {
+		tests.EndTestIfError(postRepo.Vote(voterID, postID, 1), t)
+	}
+
+	_, err := counterRepo.Reconcile()
+	tests.EndTestIfError(err, t)
+
+	_, err = db.Exec(`UPDATE Post SET Upvotes = 99 WHERE Post_ID = ?`, postID)
+	tests.EndTestIfError(err, t)
+
+	drift, err := counterRepo.Reconcile()
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(domain.CounterDrift{Posts: 1}, drift, t)
+
+	post, err := postRepo.GetByID(postID)
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(uint(3), post.Upvotes, t)
+
+	drift, err = counterRepo.Reconcile()
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(domain.CounterDrift{}, drift, t)
+}
@@ -34,7 +34,7 @@ func initializeSQLiteDatabase() {
 
 	dbPath := path.Join(folderPath, fileName)
 
-	connectionStr := "file:" + dbPath + "?_journal=WAL&_foreign_keys=true"
+	connectionStr := "file:" + dbPath + "?_journal=WAL&_foreign_keys=true&_txlock=immediate"
 	newDB, err := sql.Open("sqlite3", connectionStr)
 	util.PanicIfError(err)
 
@@ -44,6 +44,7 @@ func initializeSQLiteDatabase() {
 // Runs the migration script(s), panics if it fails
 func RunMockSQLiteMigration() {
 	mustRunSQLiteScript("schema.sql")
+	runMockSQLiteSearchMigration()
 }
 
 // Runs a SQL script in sql/ folder
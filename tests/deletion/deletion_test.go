@@ -0,0 +1,69 @@
+package deletion
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AlejandroJorge/forum-rest-api/config"
+	"github.com/AlejandroJorge/forum-rest-api/service"
+	"github.com/AlejandroJorge/forum-rest-api/tests"
+)
+
+func TestMain(t *testing.M) {
+	tests.FixWorkingDir()
+	config.InitializeAll()
+	tests.RunMockSQLiteMigration()
+	t.Run()
+}
+
+// Moves the deletion of the post back so purges with a shorter retention remove it
+func backdateDeletion(postID uint, age time.Duration, t *testing.T) {
+	_, err := tests.MockSQLiteDatabase().Exec(`UPDATE Post SET Deleted_At = ? WHERE Post_ID = ?`, time.Now().Add(-age).Unix(), postID)
+	tests.EndTestIfError(err, t)
+}
+
+func TestOwnersAndModeratorsRestore(t *testing.T) {
+	postServ := tests.MockPostService()
+	commentServ := tests.MockCommentService()
+	owner := tests.CreateMockProfile(t)
+	stranger := tests.CreateMockProfile(t)
+	moderator := tests.CreateMockModerator(t)
+	postID := tests.CreateMockPost(t, owner, tests.CreateMockCategory(t), tests.UniqueName("post"), "content")
+	commentID, err := commentServ.Create(owner, postID, 0, "comment")
+	tests.EndTestIfError(err, t)
+
+	tests.EndTestIfError(postServ.Delete(owner, postID), t)
+	_, err = postServ.GetByID(postID)
+	tests.AssertEqu(service.ErrNotExistingEntity, err, t)
+
+	tests.AssertEqu(service.ErrNotAuthorized, postServ.Restore(stranger, postID), t)
+	tests.EndTestIfError(postServ.Restore(owner, postID), t)
+	_, err = postServ.GetByID(postID)
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(service.ErrNotExistingEntity, postServ.Restore(owner, postID), t)
+
+	tests.EndTestIfError(commentServ.Delete(owner, commentID), t)
+	tests.AssertEqu(service.ErrNotAuthorized, commentServ.Restore(stranger, commentID), t)
+	tests.EndTestIfError(commentServ.Restore(moderator, commentID), t)
+	_, err = commentServ.GetByID(commentID)
+	tests.EndTestIfError(err, t)
+}
+
+func TestPurgeKeepsRecentDeletions(t *testing.T) {
+	postServ := tests.MockPostService()
+	owner := tests.CreateMockProfile(t)
+	categoryID := tests.CreateMockCategory(t)
+	old := tests.CreateMockPost(t, owner, categoryID, tests.UniqueName("post"), "content")
+	recent := tests.CreateMockPost(t, owner, categoryID, tests.UniqueName("post"), "content")
+	tests.EndTestIfError(postServ.Delete(owner, old), t)
+	tests.EndTestIfError(postServ.Delete(owner, recent), t)
+	backdateDeletion(old, 48*time.Hour, t)
+
+	_, err := postServ.PurgeDeleted(-time.Hour)
+	tests.AssertEqu(service.ErrIncorrectParameters, err, t)
+	_, err = postServ.PurgeDeleted(24 * time.Hour)
+	tests.EndTestIfError(err, t)
+
+	tests.AssertEqu(service.ErrNotExistingEntity, postServ.Restore(owner, old), t)
+	tests.EndTestIfError(postServ.Restore(owner, recent), t)
+}
@@ -0,0 +1,105 @@
+package feed
+
+import (
+	"testing"
+
+	"github.com/AlejandroJorge/forum-rest-api/config"
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/repository"
+	"github.com/AlejandroJorge/forum-rest-api/service"
+	"github.com/AlejandroJorge/forum-rest-api/tests"
+)
+
+func TestMain(t *testing.M) {
+	tests.FixWorkingDir()
+	config.InitializeAll()
+	tests.RunMockSQLiteMigration()
+	t.Run()
+}
+
+// Creates a follower of an author with more than two pages of posts, returns the follower and the posts from newest to oldest
+func createFollowedPosts(t *testing.T) (uint, []uint) {
+	postServ := tests.MockPostService()
+	follower := tests.CreateMockProfile(t)
+	author := tests.CreateMockProfile(t)
+	err := repository.NewSQLiteProfileRepository(tests.MockSQLiteDatabase()).AddFollow(follower, author)
+	tests.EndTestIfError(err, t)
+
+	categoryID := tests.CreateMockCategory(t)
+	amount := 2*int(config.GetParams().PageSize) + 1
+	posts := make([]uint, amount)
+	for i := range posts {
+		postID, err := postServ.Create(author, categoryID, tests.UniqueName("feed"), "description", "content", nil, nil)
+		tests.EndTestIfError(err, t)
+		posts[amount-1-i] = postID
+	}
+
+	return follower, posts
+}
+
+// Follows the cursors until the last page, returns the IDs of every post in order
+func walkFeed(userID uint, mode domain.FeedMode, t *testing.T) []uint {
+	postServ := tests.MockPostService()
+	var ids []uint
+	cursor := ""
+	for pages := 0; pages < 10; pages++ {
+		page, err := postServ.GetFeed(userID, mode, cursor)
+		tests.EndTestIfError(err, t)
+		if uint(len(page.Posts)) > config.GetParams().PageSize {
+			t.Errorf("Expected at most %d posts in a page, got %d", config.GetParams().PageSize, len(page.Posts))
+		}
+
+		for _, post := range page.Posts {
+			ids = append(ids, post.PostID)
+		}
+
+		if page.NextCursor == "" {
+			return ids
+		}
+		cursor = page.NextCursor
+	}
+
+	t.Fatal("Expected the feed to end")
+	return nil
+}
+
+func TestLatestFeedPages(t *testing.T) {
+	follower, posts := createFollowedPosts(t)
+
+	// Posts created in the same second are ordered by ID, so no post is repeated or skipped between pages
+	ids := walkFeed(follower, domain.FeedModeLatest, t)
+	tests.AssertEqu(len(posts), len(ids), t)
+	for i := range ids {
+		tests.AssertEqu(posts[i], ids[i], t)
+	}
+}
+
+func TestRankedFeedPages(t *testing.T) {
+	follower, posts := createFollowedPosts(t)
+
+	ids := walkFeed(follower, domain.FeedModeRanked, t)
+	tests.AssertEqu(len(posts), len(ids), t)
+	seen := make(map[uint]bool)
+	for _, id := range ids {
+		if seen[id] {
+			t.Errorf("Post %d was returned twice", id)
+		}
+		seen[id] = true
+	}
+	for _, id := range posts {
+		tests.AssertEqu(true, seen[id], t)
+	}
+}
+
+func TestInvalidFeedCursor(t *testing.T) {
+	postServ := tests.MockPostService()
+	follower, _ := createFollowedPosts(t)
+
+	_, err := postServ.GetFeed(follower, domain.FeedModeLatest, "not a cursor")
+	tests.AssertEqu(service.ErrIncorrectParameters, err, t)
+	_, err = postServ.GetFeed(follower, domain.FeedModeRanked, "not a cursor")
+	tests.AssertEqu(service.ErrIncorrectParameters, err, t)
+
+	_, err = postServ.GetFeed(tests.CreateMockProfile(t), domain.FeedModeLatest, "")
+	tests.AssertEqu(service.ErrNotExistingEntity, err, t)
+}
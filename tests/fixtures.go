@@ -0,0 +1,74 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/events"
+	"github.com/AlejandroJorge/forum-rest-api/repository"
+	"github.com/AlejandroJorge/forum-rest-api/service"
+)
+
+// Names are unique across runs because the mock database outlives them
+func UniqueName(prefix string) string {
+	return fmt.Sprintf("%s%d", prefix, time.Now().UnixNano())
+}
+
+// Creates a user with a profile, returns its ID
+func CreateMockProfile(t *testing.T) uint {
+	db := MockSQLiteDatabase()
+	name := UniqueName("user")
+	id, err := repository.NewSQLiteUserRepository(db).Create(name+"@mail.com", "hashed")
+	EndTestIfError(err, t)
+	_, err = repository.NewSQLiteProfileRepository(db).Create(id, name, name)
+	EndTestIfError(err, t)
+
+	return id
+}
+
+// Creates a user with a profile that is a moderator, returns its ID
+func CreateMockModerator(t *testing.T) uint {
+	id := CreateMockProfile(t)
+	_, err := MockSQLiteDatabase().Exec(`UPDATE User SET Is_Moderator = 1 WHERE User_ID = ?`, id)
+	EndTestIfError(err, t)
+
+	return id
+}
+
+// Creates a top level category, returns its ID
+func CreateMockCategory(t *testing.T) uint {
+	id, err := repository.NewSQLiteCategoryRepository(MockSQLiteDatabase()).Create(0, UniqueName("category"))
+	EndTestIfError(err, t)
+
+	return id
+}
+
+// Creates a post through the post service, returns its ID
+func CreateMockPost(t *testing.T, ownerID, categoryID uint, title, content string) uint {
+	id, err := MockPostService().Create(ownerID, categoryID, title, "description", content, nil, nil)
+	EndTestIfError(err, t)
+
+	return id
+}
+
+func MockPostService() domain.PostService {
+	db := MockSQLiteDatabase()
+	return service.NewPostService(repository.NewSQLitePostRepository(db), repository.NewSQLiteUserRepository(db),
+		repository.NewSQLiteCategoryRepository(db), repository.NewSQLiteProfileRepository(db), repository.NewSQLiteMediaRepository(db),
+		repository.NewSQLiteNotificationRepository(db), events.DefaultBus())
+}
+
+func MockCommentService() domain.CommentService {
+	db := MockSQLiteDatabase()
+	return service.NewCommentService(repository.NewSQLiteCommentRepository(db), repository.NewSQLiteUserRepository(db),
+		repository.NewSQLitePostRepository(db), repository.NewSQLiteProfileRepository(db), repository.NewSQLiteNotificationRepository(db),
+		events.DefaultBus())
+}
+
+func MockProfileService() domain.ProfileService {
+	db := MockSQLiteDatabase()
+	return service.NewProfileService(repository.NewSQLiteProfileRepository(db), repository.NewSQLiteMediaRepository(db),
+		repository.NewSQLiteNotificationRepository(db), events.DefaultBus())
+}
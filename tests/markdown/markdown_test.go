@@ -0,0 +1,38 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/AlejandroJorge/forum-rest-api/tests"
+	"github.com/AlejandroJorge/forum-rest-api/util"
+)
+
+func TestFormattingIsRendered(t *testing.T) {
+	tests.AssertEqu("<p><strong>bold</strong> and <em>italic</em> and <del>gone</del></p>\n", util.RenderMarkdown("**bold** and *italic* and ~~gone~~"), t)
+	tests.AssertEqu("<p>first<br>\nsecond</p>\n", util.RenderMarkdown("first\nsecond"), t)
+	tests.AssertEqu("<ul>\n<li>item</li>\n</ul>\n", util.RenderMarkdown("- item"), t)
+}
+
+func TestUnsafeContentIsRemoved(t *testing.T) {
+	unsafe := map[string]string{
+		"<script>alert(1)</script>":            "<script",
+		"<b onclick=\"alert(1)\">raw</b>":      "onclick",
+		"[link](javascript:alert(1))":          "javascript:",
+		"![image](https://example.com/a.png)":  "<img",
+		"<iframe src=\"https://example.com\">": "<iframe",
+	}
+	for content, forbidden := range unsafe {
+		rendered := util.RenderMarkdown(content)
+		if strings.Contains(rendered, forbidden) {
+			t.Errorf("Expected %q to be removed from %q, got %q", forbidden, content, rendered)
+		}
+	}
+}
+
+func TestLinksAreNoFollow(t *testing.T) {
+	rendered := util.RenderMarkdown("[site](https://example.com) and https://example.org")
+	tests.AssertEqu(2, strings.Count(rendered, `rel="nofollow"`), t)
+	tests.AssertEqu(true, strings.Contains(rendered, `href="https://example.com"`), t)
+	tests.AssertEqu(true, strings.Contains(rendered, `href="https://example.org"`), t)
+}
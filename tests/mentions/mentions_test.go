@@ -0,0 +1,62 @@
+package mentions
+
+import (
+	"testing"
+
+	"github.com/AlejandroJorge/forum-rest-api/config"
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/repository"
+	"github.com/AlejandroJorge/forum-rest-api/service"
+	"github.com/AlejandroJorge/forum-rest-api/tests"
+)
+
+func TestMain(t *testing.M) {
+	tests.FixWorkingDir()
+	config.InitializeAll()
+	tests.RunMockSQLiteMigration()
+	t.Run()
+}
+
+func tagNameOf(userID uint, t *testing.T) string {
+	profile, err := repository.NewSQLiteProfileRepository(tests.MockSQLiteDatabase()).GetByUserID(userID)
+	tests.EndTestIfError(err, t)
+
+	return profile.TagName
+}
+
+func TestMentionsAndHashtagsAreParsed(t *testing.T) {
+	db := tests.MockSQLiteDatabase()
+	postServ := tests.MockPostService()
+	author := tests.CreateMockProfile(t)
+	mentioned := tests.CreateMockProfile(t)
+	tagName := tagNameOf(mentioned, t)
+
+	content := "hi @" + tagName + ", @" + tests.UniqueName("missing") + " #golang"
+	postID, err := postServ.Create(author, tests.CreateMockCategory(t), tests.UniqueName("post"), "description", content, nil, nil)
+	tests.EndTestIfError(err, t)
+
+	// Mentions of profiles that don't exist are left out
+	post, err := postServ.GetByID(postID)
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(2, len(post.Entities), t)
+	tests.AssertEqu(domain.ContentEntity{Type: domain.ContentEntityMention, Text: "@" + tagName, Offset: 3, Length: uint(len(tagName) + 1), UserID: mentioned}, post.Entities[0], t)
+	tests.AssertEqu(domain.ContentEntityHashtag, post.Entities[1].Type, t)
+	tests.AssertEqu("#golang", post.Entities[1].Text, t)
+
+	page, err := service.NewNotificationService(repository.NewSQLiteNotificationRepository(db)).GetByUser(mentioned, 1)
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(1, len(page.Notifications), t)
+	tests.AssertEqu(domain.NotificationMention, page.Notifications[0].Type, t)
+	tests.AssertEqu(postID, page.Notifications[0].PostID, t)
+
+	commentID, err := tests.MockCommentService().Create(author, postID, 0, "again @"+tagName)
+	tests.EndTestIfError(err, t)
+
+	mentionServ := service.NewMentionService(repository.NewSQLiteProfileRepository(db), repository.NewSQLitePostRepository(db), repository.NewSQLiteCommentRepository(db))
+	mentions, err := mentionServ.GetByTagName(0, tagName)
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(1, len(mentions.Posts), t)
+	tests.AssertEqu(postID, mentions.Posts[0].PostID, t)
+	tests.AssertEqu(1, len(mentions.Comments), t)
+	tests.AssertEqu(commentID, mentions.Comments[0].ID, t)
+}
@@ -0,0 +1,88 @@
+package messages
+
+import (
+	"testing"
+
+	"github.com/AlejandroJorge/forum-rest-api/config"
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/events"
+	"github.com/AlejandroJorge/forum-rest-api/repository"
+	"github.com/AlejandroJorge/forum-rest-api/service"
+	"github.com/AlejandroJorge/forum-rest-api/tests"
+)
+
+func TestMain(t *testing.M) {
+	tests.FixWorkingDir()
+	config.InitializeAll()
+	tests.RunMockSQLiteMigration()
+	t.Run()
+}
+
+func conversationService() domain.ConversationService {
+	db := tests.MockSQLiteDatabase()
+	return service.NewConversationService(repository.NewSQLiteConversationRepository(db), repository.NewSQLiteUserRepository(db),
+		repository.NewSQLiteProfileRepository(db), events.DefaultBus())
+}
+
+// Returns how many messages of the conversation the user didn't read, as shown in their conversation list
+func unread(userID, conversationID uint, t *testing.T) uint {
+	conversations, err := conversationService().GetByUser(userID, 1)
+	tests.EndTestIfError(err, t)
+	for _, conversation := range conversations {
+		if conversation.ID == conversationID {
+			return conversation.Unread
+		}
+	}
+
+	t.Errorf("Expected conversation %d in the list of user %d", conversationID, userID)
+	return 0
+}
+
+func TestOneToOneConversationsAreReused(t *testing.T) {
+	conversationServ := conversationService()
+	first := tests.CreateMockProfile(t)
+	second := tests.CreateMockProfile(t)
+
+	id, err := conversationServ.Start(first, []uint{second})
+	tests.EndTestIfError(err, t)
+	again, err := conversationServ.Start(second, []uint{first})
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(id, again, t)
+}
+
+func TestOnlyMembersReadAndSend(t *testing.T) {
+	conversationServ := conversationService()
+	sender := tests.CreateMockProfile(t)
+	receiver := tests.CreateMockProfile(t)
+	outsider := tests.CreateMockProfile(t)
+	id, err := conversationServ.Start(sender, []uint{receiver})
+	tests.EndTestIfError(err, t)
+
+	_, err = conversationServ.Send(sender, id, "hello")
+	tests.EndTestIfError(err, t)
+	lastID, err := conversationServ.Send(sender, id, "are you there?")
+	tests.EndTestIfError(err, t)
+
+	_, err = conversationServ.Send(outsider, id, "hi")
+	tests.AssertEqu(service.ErrNotExistingEntity, err, t)
+	_, err = conversationServ.GetMessages(outsider, id, 1)
+	tests.AssertEqu(service.ErrNotExistingEntity, err, t)
+
+	messages, err := conversationServ.GetMessages(receiver, id, 1)
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(2, len(messages), t)
+	tests.AssertEqu("are you there?", messages[0].Content, t)
+
+	tests.AssertEqu(uint(2), unread(receiver, id, t), t)
+	tests.AssertEqu(uint(0), unread(sender, id, t), t)
+
+	tests.EndTestIfError(conversationServ.MarkRead(receiver, id, 0), t)
+	tests.AssertEqu(uint(0), unread(receiver, id, t), t)
+	conversation, err := conversationServ.GetByID(receiver, id)
+	tests.EndTestIfError(err, t)
+	for _, member := range conversation.Members {
+		if member.UserID == receiver {
+			tests.AssertEqu(lastID, member.LastReadMessageID, t)
+		}
+	}
+}
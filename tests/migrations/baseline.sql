@@ -0,0 +1,69 @@
+-- Schema and rows of a database created before soft deletion, threads, slugs and categories existed
+
+CREATE TABLE IF NOT EXISTS User (
+  User_ID INTEGER PRIMARY KEY AUTOINCREMENT,
+  Email TEXT NOT NULL UNIQUE,
+  Hashed_Password TEXT NOT NULL,
+  Registration_Date INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS Profile (
+  User_ID INTEGER PRIMARY KEY,
+  Display_Name TEXT NOT NULL,
+  Tag_Name TEXT NOT NULL UNIQUE,
+  Picture_Path TEXT,
+  Background_Path TEXT,
+  FOREIGN KEY (User_ID) REFERENCES User(User_ID)
+);
+
+CREATE TABLE IF NOT EXISTS Following (
+  Followed_ID INTEGER,
+  Follower_ID INTEGER,
+  Following_Date INTEGER NOT NULL,
+  FOREIGN KEY (Followed_ID) REFERENCES Profile(User_ID),
+  FOREIGN KEY (Follower_ID) REFERENCES Profile(User_ID),
+  PRIMARY KEY (Followed_ID, Follower_ID)
+);
+
+CREATE TABLE IF NOT EXISTS Post (
+  Post_ID INTEGER PRIMARY KEY AUTOINCREMENT,
+  Title TEXT NOT NULL UNIQUE ,
+  Description TEXT NOT NULL,
+  Content TEXT NOT NULL,
+  Creation_Date TEXT NOT NULL,
+  Owner_ID INTEGER NOT NULL,
+  FOREIGN KEY (Owner_ID) REFERENCES Profile(User_ID)
+);
+
+CREATE TABLE IF NOT EXISTS Post_Likings (
+  Post_ID INTEGER,
+  Liker_ID INTEGER,
+  FOREIGN KEY (Post_ID) REFERENCES Post(Post_ID),
+  FOREIGN KEY (Liker_ID) REFERENCES Profile(User_ID),
+  PRIMARY KEY (Post_ID, Liker_ID)
+);
+
+CREATE TABLE IF NOT EXISTS Comment (
+  Comment_ID INTEGER PRIMARY KEY AUTOINCREMENT,
+  Post_ID INTEGER NOT NULL,
+  User_ID INTEGER NOT NULL,
+  Content TEXT NOT NULL,
+  FOREIGN KEY (Post_ID) REFERENCES Post(Post_ID),
+  FOREIGN KEY (User_ID) REFERENCES Profile(User_ID)
+);
+
+CREATE TABLE IF NOT EXISTS Comment_Likings (
+  Comment_ID INTEGER,
+  Liker_ID INTEGER,
+  FOREIGN KEY (Comment_ID) REFERENCES Comment(Comment_ID),
+  FOREIGN KEY (Liker_ID) REFERENCES Profile(User_ID),
+  PRIMARY KEY (Comment_ID, Liker_ID)
+);
+
+INSERT INTO User(User_ID, Email, Hashed_Password, Registration_Date) VALUES (1, 'ana@mail.com', 'hashed', 1700000000), (2, 'ben@mail.com', 'hashed', 1700000000);
+INSERT INTO Profile(User_ID, Display_Name, Tag_Name) VALUES (1, 'Ana', 'ana'), (2, 'Ben', 'ben');
+INSERT INTO Following(Followed_ID, Follower_ID, Following_Date) VALUES (1, 2, 1700000100);
+INSERT INTO Post(Post_ID, Title, Description, Content, Creation_Date, Owner_ID) VALUES (1, 'Hello World', 'first', 'hello', 1700000200, 1), (2, 'Hello, world!', 'second', 'hi', 1700000300, 2);
+INSERT INTO Post_Likings(Post_ID, Liker_ID) VALUES (1, 2);
+INSERT INTO Comment(Comment_ID, Post_ID, User_ID, Content) VALUES (1, 1, 2, 'nice'), (2, 1, 1, 'thanks');
+INSERT INTO Comment_Likings(Comment_ID, Liker_ID) VALUES (1, 1);
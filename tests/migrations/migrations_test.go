@@ -0,0 +1,133 @@
+package migrations
+
+import (
+	"database/sql"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/AlejandroJorge/forum-rest-api/config"
+	"github.com/AlejandroJorge/forum-rest-api/tests"
+	"github.com/AlejandroJorge/forum-rest-api/util"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const upgradedFolder = "mock"
+
+const upgradedFile = "upgraded.sqlite"
+
+// Creates a database as the baseline schema left it and lets the app migrate it on startup
+func TestMain(t *testing.M) {
+	tests.FixWorkingDir()
+	createBaselineDatabase()
+	os.Setenv("DB_FOLDER_NAME", upgradedFolder)
+	os.Setenv("DB_FILE_NAME", upgradedFile)
+	config.InitializeAll()
+	t.Run()
+}
+
+func createBaselineDatabase() {
+	folderPath := path.Join(util.GetWorkingDir(), upgradedFolder)
+	err := os.MkdirAll(folderPath, 0755)
+	util.PanicIfError(err)
+
+	dbPath := path.Join(folderPath, upgradedFile)
+	for _, suffix := range []string{"", "-wal", "-shm"} {
+		os.Remove(dbPath + suffix)
+	}
+
+	script, err := os.ReadFile(path.Join(util.GetWorkingDir(), "tests", "migrations", "baseline.sql"))
+	util.PanicIfError(err)
+
+	db, err := sql.Open("sqlite3", "file:"+dbPath)
+	util.PanicIfError(err)
+	defer db.Close()
+
+	_, err = db.Exec(string(script))
+	util.PanicIfError(err)
+}
+
+func TestUpgradedColumns(t *testing.T) {
+	db := config.SQLiteDatabase()
+	columns := map[string][]string{
+		"User":    {"Is_Moderator", "Suspended_Until"},
+		"Post":    {"Deleted_At", "Slug", "Category_ID", "Upvotes", "Last_Activity", "Is_Locked"},
+		"Comment": {"Deleted_At", "Parent_ID", "Depth", "Creation_Date", "Edit_Date", "Reply_Count"},
+	}
+	for table, names := range columns {
+		for _, name := range names {
+			var existing int
+			err := db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`, table, name).Scan(&existing)
+			tests.EndTestIfError(err, t)
+			if existing == 0 {
+				t.Errorf("%s.%s wasn't added", table, name)
+			}
+		}
+	}
+}
+
+func TestUpgradedPosts(t *testing.T) {
+	db := config.SQLiteDatabase()
+	expectedSlugs := map[uint]string{1: "hello-world", 2: "hello-world-2"}
+	for id, expectedSlug := range expectedSlugs {
+		var slug string
+		var categoryID uint
+		err := db.QueryRow(`SELECT Slug, Category_ID FROM Post WHERE Post_ID = ?`, id).Scan(&slug, &categoryID)
+		tests.EndTestIfError(err, t)
+		tests.AssertEqu(expectedSlug, slug, t)
+
+		var categoryName string
+		err = db.QueryRow(`SELECT Name FROM Category WHERE Category_ID = ?`, categoryID).Scan(&categoryName)
+		tests.EndTestIfError(err, t)
+		tests.AssertEqu("General", categoryName, t)
+	}
+
+	_, err := db.Exec(`
+	INSERT INTO Post(Title, Slug, Description, Content, Creation_Date, Owner_ID, Category_ID)
+	VALUES ('Hello World', 'hello-world-3', 'third', 'hey', 1700000400, 1, 1)
+	`)
+	if err != nil {
+		t.Errorf("Titles are still unique after the upgrade: %v", err)
+	}
+}
+
+func TestUpgradedComments(t *testing.T) {
+	db := config.SQLiteDatabase()
+
+	var creationDate int64
+	var depth uint
+	var parentID sql.NullInt64
+	err := db.QueryRow(`SELECT Creation_Date, Depth, Parent_ID FROM Comment WHERE Comment_ID = 1`).Scan(&creationDate, &depth, &parentID)
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(int64(1700000200), creationDate, t)
+	tests.AssertEqu(uint(0), depth, t)
+	tests.AssertEqu(false, parentID.Valid, t)
+
+	var violations int
+	err = db.QueryRow(`SELECT COUNT(*) FROM pragma_foreign_key_check`).Scan(&violations)
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(0, violations, t)
+}
+
+func TestUpgradedCounters(t *testing.T) {
+	db := config.SQLiteDatabase()
+
+	var upvotes, commentCount uint
+	var lastActivity int64
+	err := db.QueryRow(`SELECT Upvotes, Comment_Count, Last_Activity FROM Post WHERE Post_ID = 1`).Scan(&upvotes, &commentCount, &lastActivity)
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(uint(1), upvotes, t)
+	tests.AssertEqu(uint(2), commentCount, t)
+	tests.AssertEqu(int64(1700000200), lastActivity, t)
+
+	var commentUpvotes uint
+	err = db.QueryRow(`SELECT Upvotes FROM Comment WHERE Comment_ID = 1`).Scan(&commentUpvotes)
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(uint(1), commentUpvotes, t)
+
+	var followerCount, followCount uint
+	err = db.QueryRow(`SELECT Follower_Count, Follow_Count FROM Profile WHERE User_ID = 1`).Scan(&followerCount, &followCount)
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(uint(1), followerCount, t)
+	tests.AssertEqu(uint(0), followCount, t)
+}
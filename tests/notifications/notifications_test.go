@@ -0,0 +1,85 @@
+package notifications
+
+import (
+	"testing"
+
+	"github.com/AlejandroJorge/forum-rest-api/config"
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/repository"
+	"github.com/AlejandroJorge/forum-rest-api/service"
+	"github.com/AlejandroJorge/forum-rest-api/tests"
+)
+
+func TestMain(t *testing.M) {
+	tests.FixWorkingDir()
+	config.InitializeAll()
+	tests.RunMockSQLiteMigration()
+	t.Run()
+}
+
+func notificationService() domain.NotificationService {
+	return service.NewNotificationService(repository.NewSQLiteNotificationRepository(tests.MockSQLiteDatabase()))
+}
+
+// Counts the notifications of the type the user received
+func countNotifications(userID uint, notificationType domain.NotificationType, t *testing.T) int {
+	page, err := notificationService().GetByUser(userID, 1)
+	tests.EndTestIfError(err, t)
+
+	count := 0
+	for _, notification := range page.Notifications {
+		if notification.Type == notificationType {
+			count++
+		}
+	}
+
+	return count
+}
+
+func TestPreferencesDefaultToEverything(t *testing.T) {
+	preferences, err := notificationService().GetPreferences(tests.CreateMockProfile(t))
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(domain.NotificationPreferences{Reply: true, Like: true, Follow: true, Mention: true}, preferences, t)
+}
+
+func TestTurnedOffRepliesAreSkipped(t *testing.T) {
+	commentServ := tests.MockCommentService()
+	owner := tests.CreateMockProfile(t)
+	commenter := tests.CreateMockProfile(t)
+	postID := tests.CreateMockPost(t, owner, tests.CreateMockCategory(t), tests.UniqueName("post"), "content")
+
+	err := notificationService().UpdatePreferences(owner, domain.NotificationPreferences{Like: true, Follow: true, Mention: true})
+	tests.EndTestIfError(err, t)
+	_, err = commentServ.Create(commenter, postID, 0, "muted reply")
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(0, countNotifications(owner, domain.NotificationReply, t), t)
+
+	err = notificationService().UpdatePreferences(owner, domain.NotificationPreferences{Reply: true})
+	tests.EndTestIfError(err, t)
+	_, err = commentServ.Create(commenter, postID, 0, "reply")
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(1, countNotifications(owner, domain.NotificationReply, t), t)
+
+	// Nobody is notified about their own comments
+	_, err = commentServ.Create(owner, postID, 0, "own reply")
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(1, countNotifications(owner, domain.NotificationReply, t), t)
+}
+
+func TestTurnedOffLikesAreSkipped(t *testing.T) {
+	postServ := tests.MockPostService()
+	owner := tests.CreateMockProfile(t)
+	voter := tests.CreateMockProfile(t)
+	postID := tests.CreateMockPost(t, owner, tests.CreateMockCategory(t), tests.UniqueName("post"), "content")
+	otherPostID := tests.CreateMockPost(t, owner, tests.CreateMockCategory(t), tests.UniqueName("post"), "content")
+
+	err := notificationService().UpdatePreferences(owner, domain.NotificationPreferences{Reply: true})
+	tests.EndTestIfError(err, t)
+	tests.EndTestIfError(postServ.Vote(voter, postID, 1), t)
+	tests.AssertEqu(0, countNotifications(owner, domain.NotificationLike, t), t)
+
+	err = notificationService().UpdatePreferences(owner, domain.NotificationPreferences{Like: true})
+	tests.EndTestIfError(err, t)
+	tests.EndTestIfError(postServ.Vote(voter, otherPostID, 1), t)
+	tests.AssertEqu(1, countNotifications(owner, domain.NotificationLike, t), t)
+}
@@ -0,0 +1,106 @@
+package poststates
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AlejandroJorge/forum-rest-api/config"
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/repository"
+	"github.com/AlejandroJorge/forum-rest-api/service"
+	"github.com/AlejandroJorge/forum-rest-api/tests"
+)
+
+func TestMain(t *testing.M) {
+	tests.FixWorkingDir()
+	config.InitializeAll()
+	tests.RunMockSQLiteMigration()
+	t.Run()
+}
+
+func TestLockedPostsRejectComments(t *testing.T) {
+	postServ := tests.MockPostService()
+	commentServ := tests.MockCommentService()
+	owner := tests.CreateMockProfile(t)
+	commenter := tests.CreateMockProfile(t)
+	postID := tests.CreateMockPost(t, owner, tests.CreateMockCategory(t), tests.UniqueName("post"), "content")
+	commentID, err := commentServ.Create(commenter, postID, 0, "before the lock")
+	tests.EndTestIfError(err, t)
+
+	tests.AssertEqu(service.ErrNotAuthorized, postServ.Lock(commenter, postID), t)
+	tests.EndTestIfError(postServ.Lock(owner, postID), t)
+
+	_, err = commentServ.Create(commenter, postID, 0, "comment")
+	tests.AssertEqu(service.ErrLocked, err, t)
+	_, err = commentServ.Create(commenter, postID, commentID, "reply")
+	tests.AssertEqu(service.ErrLocked, err, t)
+
+	// Locking only stops new comments
+	tests.EndTestIfError(postServ.Vote(commenter, postID, 1), t)
+
+	tests.EndTestIfError(postServ.Unlock(owner, postID), t)
+	_, err = commentServ.Create(commenter, postID, 0, "after the lock")
+	tests.EndTestIfError(err, t)
+}
+
+func TestArchivedPostsAreReadOnly(t *testing.T) {
+	postServ := tests.MockPostService()
+	commentServ := tests.MockCommentService()
+	owner := tests.CreateMockProfile(t)
+	commenter := tests.CreateMockProfile(t)
+	moderator := tests.CreateMockModerator(t)
+	postID := tests.CreateMockPost(t, owner, tests.CreateMockCategory(t), tests.UniqueName("post"), "content")
+	commentID, err := commentServ.Create(commenter, postID, 0, "before archiving")
+	tests.EndTestIfError(err, t)
+
+	tests.AssertEqu(service.ErrNotAuthorized, postServ.Archive(commenter, postID), t)
+	tests.EndTestIfError(postServ.Archive(moderator, postID), t)
+
+	_, err = commentServ.Create(commenter, postID, 0, "comment")
+	tests.AssertEqu(service.ErrArchived, err, t)
+	tests.AssertEqu(service.ErrArchived, commentServ.Update(commenter, commentID, "edited"), t)
+	tests.AssertEqu(service.ErrArchived, commentServ.Vote(owner, commentID, 1), t)
+	tests.AssertEqu(service.ErrArchived, postServ.Vote(commenter, postID, 1), t)
+	tests.AssertEqu(service.ErrArchived, postServ.UpdateContent(owner, postID, "edited"), t)
+
+	tests.EndTestIfError(postServ.Unarchive(owner, postID), t)
+	_, err = commentServ.Create(commenter, postID, 0, "after archiving")
+	tests.EndTestIfError(err, t)
+}
+
+func TestPinnedPostsComeFirst(t *testing.T) {
+	postServ := tests.MockPostService()
+	owner := tests.CreateMockProfile(t)
+	categoryID := tests.CreateMockCategory(t)
+	pinned := tests.CreateMockPost(t, owner, categoryID, tests.UniqueName("post"), "content")
+	tests.CreateMockPost(t, owner, categoryID, tests.UniqueName("post"), "content")
+	tests.CreateMockPost(t, owner, categoryID, tests.UniqueName("post"), "content")
+
+	tests.EndTestIfError(postServ.Pin(owner, pinned), t)
+
+	posts, err := postServ.GetPopularInCategory(0, categoryID, domain.PopularPeriodAllTime, domain.PostSortNew)
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(pinned, posts[0].PostID, t)
+	tests.AssertEqu(true, posts[0].Pinned, t)
+
+	posts, err = postServ.GetByUser(0, owner)
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(pinned, posts[0].PostID, t)
+}
+
+func TestSuspendedOwnersCantChangeStates(t *testing.T) {
+	postServ := tests.MockPostService()
+	owner := tests.CreateMockProfile(t)
+	postID := tests.CreateMockPost(t, owner, tests.CreateMockCategory(t), tests.UniqueName("post"), "content")
+	userRepo := repository.NewSQLiteUserRepository(tests.MockSQLiteDatabase())
+	tests.EndTestIfError(userRepo.Suspend(owner, time.Now().Add(time.Hour)), t)
+
+	for _, update := range []func(userId, postId uint) error{
+		postServ.Lock, postServ.Unlock, postServ.Pin, postServ.Unpin, postServ.Archive, postServ.Unarchive,
+	} {
+		tests.AssertEqu(service.ErrSuspended, update(owner, postID), t)
+	}
+
+	tests.EndTestIfError(userRepo.Suspend(owner, time.Time{}), t)
+	tests.EndTestIfError(postServ.Lock(owner, postID), t)
+}
@@ -0,0 +1,113 @@
+package reports
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AlejandroJorge/forum-rest-api/config"
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/events"
+	"github.com/AlejandroJorge/forum-rest-api/repository"
+	"github.com/AlejandroJorge/forum-rest-api/service"
+	"github.com/AlejandroJorge/forum-rest-api/tests"
+)
+
+func TestMain(t *testing.M) {
+	tests.FixWorkingDir()
+	config.InitializeAll()
+	tests.RunMockSQLiteMigration()
+	t.Run()
+}
+
+func reportService() domain.ReportService {
+	db := tests.MockSQLiteDatabase()
+	return service.NewReportService(repository.NewSQLiteReportRepository(db), repository.NewSQLiteUserRepository(db),
+		repository.NewSQLitePostRepository(db), repository.NewSQLiteCommentRepository(db), repository.NewSQLiteProfileRepository(db),
+		repository.NewSQLiteNotificationRepository(db), events.DefaultBus())
+}
+
+func TestReportsOfTheSameContentAreGrouped(t *testing.T) {
+	reportServ := reportService()
+	author := tests.CreateMockProfile(t)
+	first := tests.CreateMockProfile(t)
+	second := tests.CreateMockProfile(t)
+	moderator := tests.CreateMockModerator(t)
+	postID := tests.CreateMockPost(t, author, tests.CreateMockCategory(t), tests.UniqueName("post"), "content")
+
+	reportID, err := reportServ.Report(first, domain.ReportTargetPost, postID, domain.ReportReasonSpam, "")
+	tests.EndTestIfError(err, t)
+	_, err = reportServ.Report(first, domain.ReportTargetPost, postID, domain.ReportReasonHate, "again")
+	tests.AssertEqu(service.ErrAlreadyExisting, err, t)
+
+	secondID, err := reportServ.Report(second, domain.ReportTargetPost, postID, domain.ReportReasonSpam, "")
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(reportID, secondID, t)
+
+	report, err := reportServ.GetByID(moderator, reportID)
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(uint(2), report.ReporterCount, t)
+	tests.AssertEqu(uint(2), report.Reasons[domain.ReportReasonSpam], t)
+	tests.AssertEqu(uint(0), report.Reasons[domain.ReportReasonHate], t)
+	tests.AssertEqu(author, report.AuthorID, t)
+
+	// Once the report is closed the content can be reported again in a new one
+	tests.EndTestIfError(reportServ.Act(moderator, reportID, domain.ModerationDismiss, "fine", 0), t)
+	newID, err := reportServ.Report(first, domain.ReportTargetPost, postID, domain.ReportReasonSpam, "")
+	tests.EndTestIfError(err, t)
+	if newID == reportID {
+		t.Errorf("Expected a new report after the previous one was dismissed")
+	}
+}
+
+func TestInvalidReports(t *testing.T) {
+	reportServ := reportService()
+	author := tests.CreateMockProfile(t)
+	reporter := tests.CreateMockProfile(t)
+	postID := tests.CreateMockPost(t, author, tests.CreateMockCategory(t), tests.UniqueName("post"), "content")
+
+	_, err := reportServ.Report(author, domain.ReportTargetPost, postID, domain.ReportReasonSpam, "")
+	tests.AssertEqu(service.ErrIncorrectParameters, err, t)
+	_, err = reportServ.Report(reporter, domain.ReportTargetPost, postID, "boring", "")
+	tests.AssertEqu(service.ErrIncorrectParameters, err, t)
+	_, err = reportServ.Report(reporter, domain.ReportTargetComment, postID+1000000, domain.ReportReasonSpam, "")
+	tests.AssertEqu(service.ErrNotExistingEntity, err, t)
+
+	reportID, err := reportServ.Report(reporter, domain.ReportTargetPost, postID, domain.ReportReasonOther, "")
+	tests.EndTestIfError(err, t)
+	_, err = reportServ.GetByID(reporter, reportID)
+	tests.AssertEqu(service.ErrNotAuthorized, err, t)
+}
+
+// Reports the post and applies the moderation action to the report
+func moderatePost(moderator, postID uint, action domain.ModerationAction, t *testing.T) {
+	reportID, err := reportService().Report(tests.CreateMockProfile(t), domain.ReportTargetPost, postID, domain.ReportReasonSpam, "")
+	tests.EndTestIfError(err, t)
+	tests.EndTestIfError(reportService().Act(moderator, reportID, action, "", 0), t)
+}
+
+func TestModeratedPostsOnlyComeBackThroughModerators(t *testing.T) {
+	postServ := tests.MockPostService()
+	author := tests.CreateMockProfile(t)
+	moderator := tests.CreateMockModerator(t)
+	categoryID := tests.CreateMockCategory(t)
+	hidden := tests.CreateMockPost(t, author, categoryID, tests.UniqueName("post"), "content")
+	deleted := tests.CreateMockPost(t, author, categoryID, tests.UniqueName("post"), "content")
+	moderatePost(moderator, hidden, domain.ModerationHide, t)
+	moderatePost(moderator, deleted, domain.ModerationDelete, t)
+
+	_, err := postServ.GetByID(hidden)
+	tests.AssertEqu(service.ErrNotExistingEntity, err, t)
+	tests.AssertEqu(service.ErrNotAuthorized, postServ.Restore(author, hidden), t)
+	tests.AssertEqu(service.ErrNotAuthorized, postServ.Restore(author, deleted), t)
+
+	// Hidden posts are kept for moderators however old they are
+	_, err = tests.MockSQLiteDatabase().Exec(`UPDATE Post SET Deleted_At = ? WHERE Post_ID IN (?, ?)`, time.Now().Add(-48*time.Hour).Unix(), hidden, deleted)
+	tests.EndTestIfError(err, t)
+	_, err = postServ.PurgeDeleted(24 * time.Hour)
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(service.ErrNotExistingEntity, postServ.Restore(moderator, deleted), t)
+
+	tests.EndTestIfError(postServ.Restore(moderator, hidden), t)
+	_, err = postServ.GetByID(hidden)
+	tests.EndTestIfError(err, t)
+}
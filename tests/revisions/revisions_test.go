@@ -0,0 +1,81 @@
+package revisions
+
+import (
+	"testing"
+
+	"github.com/AlejandroJorge/forum-rest-api/config"
+	"github.com/AlejandroJorge/forum-rest-api/service"
+	"github.com/AlejandroJorge/forum-rest-api/tests"
+)
+
+func TestMain(t *testing.M) {
+	tests.FixWorkingDir()
+	config.InitializeAll()
+	tests.RunMockSQLiteMigration()
+	t.Run()
+}
+
+func TestOnlyOwnerOrModeratorEdits(t *testing.T) {
+	postServ := tests.MockPostService()
+	owner := tests.CreateMockProfile(t)
+	stranger := tests.CreateMockProfile(t)
+	moderator := tests.CreateMockModerator(t)
+	postID := tests.CreateMockPost(t, owner, tests.CreateMockCategory(t), tests.UniqueName("post"), "content")
+
+	tests.AssertEqu(service.ErrNotAuthorized, postServ.UpdateTitle(stranger, postID, tests.UniqueName("title")), t)
+	tests.AssertEqu(service.ErrNotAuthorized, postServ.UpdateDescription(stranger, postID, "changed"), t)
+	tests.AssertEqu(service.ErrNotAuthorized, postServ.UpdateContent(stranger, postID, "changed"), t)
+	tests.AssertEqu(service.ErrNotAuthorized, postServ.UpdateTags(stranger, postID, []string{"changed"}), t)
+
+	post, err := postServ.GetByID(postID)
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu("content", post.Content, t)
+
+	tests.EndTestIfError(postServ.UpdateContent(owner, postID, "by owner"), t)
+	tests.EndTestIfError(postServ.UpdateContent(moderator, postID, "by moderator"), t)
+
+	post, err = postServ.GetByID(postID)
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu("by moderator", post.Content, t)
+}
+
+func TestOnlyOwnerRollsBack(t *testing.T) {
+	postServ := tests.MockPostService()
+	owner := tests.CreateMockProfile(t)
+	moderator := tests.CreateMockModerator(t)
+	postID := tests.CreateMockPost(t, owner, tests.CreateMockCategory(t), tests.UniqueName("post"), "first")
+	tests.EndTestIfError(postServ.UpdateContent(owner, postID, "second"), t)
+
+	tests.AssertEqu(service.ErrNotAuthorized, postServ.Rollback(moderator, postID, 1), t)
+	tests.EndTestIfError(postServ.Rollback(owner, postID, 1), t)
+
+	post, err := postServ.GetByID(postID)
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu("first", post.Content, t)
+}
+
+func TestRevisionHistoryAndDiff(t *testing.T) {
+	postServ := tests.MockPostService()
+	owner := tests.CreateMockProfile(t)
+	postID := tests.CreateMockPost(t, owner, tests.CreateMockCategory(t), tests.UniqueName("post"), "one\ntwo\nthree")
+	tests.EndTestIfError(postServ.UpdateContent(owner, postID, "one\n2\nthree"), t)
+
+	revisions, err := postServ.GetRevisions(postID)
+	tests.EndTestIfError(err, t)
+	if len(revisions) < 2 {
+		t.Fatalf("Expected at least 2 revisions, got %d", len(revisions))
+	}
+
+	first := revisions[0]
+	last := revisions[len(revisions)-1]
+	tests.AssertEqu("one\ntwo\nthree", first.Content, t)
+	tests.AssertEqu("one\n2\nthree", last.Content, t)
+	tests.AssertEqu(owner, last.EditorID, t)
+
+	diff, err := postServ.DiffRevisions(postID, first.Number, last.Number)
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu("  one\n- two\n+ 2\n  three", diff.Content, t)
+
+	_, err = postServ.DiffRevisions(postID, first.Number, last.Number+1)
+	tests.AssertEqu(service.ErrNotExistingEntity, err, t)
+}
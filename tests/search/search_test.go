@@ -0,0 +1,154 @@
+//go:build sqlite_fts5
+
+package search
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AlejandroJorge/forum-rest-api/config"
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/repository"
+	"github.com/AlejandroJorge/forum-rest-api/service"
+	"github.com/AlejandroJorge/forum-rest-api/tests"
+)
+
+func TestMain(t *testing.M) {
+	tests.FixWorkingDir()
+	config.InitializeAll()
+	tests.RunMockSQLiteMigration()
+	t.Run()
+}
+
+func searchService() domain.SearchService {
+	return service.NewSearchService(repository.NewSQLiteSearchRepository(tests.MockSQLiteDatabase()))
+}
+
+// Returns the IDs of the posts matching the text, in rank order
+func searchPosts(text string, filter domain.SearchFilter, page uint, t *testing.T) []uint {
+	results, err := searchService().Search(text, domain.SearchTypePosts, filter, page)
+	if err == service.ErrNotExistingEntity {
+		return nil
+	}
+	tests.EndTestIfError(err, t)
+
+	ids := make([]uint, len(results))
+	for i, result := range results {
+		ids[i] = result.ID
+	}
+
+	return ids
+}
+
+func TestIndexFollowsInsertsUpdatesAndDeletes(t *testing.T) {
+	postServ := tests.MockPostService()
+	owner := tests.CreateMockProfile(t)
+	word := tests.UniqueName("inserted")
+	postID := tests.CreateMockPost(t, owner, tests.CreateMockCategory(t), tests.UniqueName("post"), "about "+word)
+
+	results, err := searchService().Search(word, domain.SearchTypePosts, domain.SearchFilter{}, 1)
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(1, len(results), t)
+	tests.AssertEqu(postID, results[0].ID, t)
+	tests.AssertEqu("about <mark>"+word+"</mark>", results[0].Snippet, t)
+
+	updated := tests.UniqueName("updated")
+	tests.EndTestIfError(postServ.UpdateContent(owner, postID, "about "+updated), t)
+	tests.AssertEqu(0, len(searchPosts(word, domain.SearchFilter{}, 1, t)), t)
+	tests.AssertEqu(1, len(searchPosts(updated, domain.SearchFilter{}, 1, t)), t)
+
+	commentID, err := tests.MockCommentService().Create(owner, postID, 0, "a comment on "+updated)
+	tests.EndTestIfError(err, t)
+	comments, err := searchService().Search(updated, domain.SearchTypeComments, domain.SearchFilter{}, 1)
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(1, len(comments), t)
+	tests.AssertEqu(commentID, comments[0].ID, t)
+
+	// Deleted posts stay indexed until they are purged
+	tests.EndTestIfError(postServ.Delete(owner, postID), t)
+	tests.AssertEqu(0, len(searchPosts(updated, domain.SearchFilter{}, 1, t)), t)
+	_, err = tests.MockSQLiteDatabase().Exec(`UPDATE Post SET Deleted_At = ? WHERE Post_ID = ?`, time.Now().Add(-time.Hour).Unix(), postID)
+	tests.EndTestIfError(err, t)
+	_, err = postServ.PurgeDeleted(time.Minute)
+	tests.EndTestIfError(err, t)
+
+	var indexed int
+	err = tests.MockSQLiteDatabase().QueryRow(`SELECT COUNT(*) FROM Post_Search WHERE Post_Search MATCH ?`, `"`+updated+`"`).Scan(&indexed)
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(0, indexed, t)
+}
+
+func TestFiltersNarrowTheResults(t *testing.T) {
+	author := tests.CreateMockProfile(t)
+	other := tests.CreateMockProfile(t)
+	categoryID := tests.CreateMockCategory(t)
+	word := tests.UniqueName("filtered")
+	first := tests.CreateMockPost(t, author, categoryID, tests.UniqueName("post"), word)
+	second := tests.CreateMockPost(t, other, categoryID, tests.UniqueName("post"), word)
+	third := tests.CreateMockPost(t, author, tests.CreateMockCategory(t), tests.UniqueName("post"), word)
+
+	old := time.Now().Add(-48 * time.Hour)
+	_, err := tests.MockSQLiteDatabase().Exec(`UPDATE Post SET Creation_Date = ? WHERE Post_ID = ?`, old.Unix(), third)
+	tests.EndTestIfError(err, t)
+
+	tests.AssertEqu(3, len(searchPosts(word, domain.SearchFilter{}, 1, t)), t)
+
+	byAuthor := searchPosts(word, domain.SearchFilter{AuthorID: other}, 1, t)
+	tests.AssertEqu(1, len(byAuthor), t)
+	tests.AssertEqu(second, byAuthor[0], t)
+
+	byCategory := searchPosts(word, domain.SearchFilter{AuthorID: author, CategoryID: categoryID}, 1, t)
+	tests.AssertEqu(1, len(byCategory), t)
+	tests.AssertEqu(first, byCategory[0], t)
+
+	byDate := searchPosts(word, domain.SearchFilter{To: time.Now().Add(-24 * time.Hour)}, 1, t)
+	tests.AssertEqu(1, len(byDate), t)
+	tests.AssertEqu(third, byDate[0], t)
+	tests.AssertEqu(2, len(searchPosts(word, domain.SearchFilter{From: time.Now().Add(-24 * time.Hour)}, 1, t)), t)
+
+	_, err = searchService().Search(word, domain.SearchTypePosts, domain.SearchFilter{From: time.Now(), To: old}, 1)
+	tests.AssertEqu(service.ErrIncorrectParameters, err, t)
+}
+
+func TestResultsArePaged(t *testing.T) {
+	owner := tests.CreateMockProfile(t)
+	categoryID := tests.CreateMockCategory(t)
+	word := tests.UniqueName("paged")
+	pageSize := int(config.GetParams().PageSize)
+	for i := 0; i <= pageSize; i++ {
+		tests.CreateMockPost(t, owner, categoryID, tests.UniqueName("post"), word)
+	}
+
+	tests.AssertEqu(pageSize, len(searchPosts(word, domain.SearchFilter{}, 1, t)), t)
+	tests.AssertEqu(1, len(searchPosts(word, domain.SearchFilter{}, 2, t)), t)
+	tests.AssertEqu(0, len(searchPosts(word, domain.SearchFilter{}, 3, t)), t)
+
+	_, err := searchService().Search(word, domain.SearchTypePosts, domain.SearchFilter{}, 0)
+	tests.AssertEqu(service.ErrIncorrectParameters, err, t)
+}
+
+func TestPrivatePostsOnlyMatchForApprovedFollowers(t *testing.T) {
+	owner := tests.CreateMockProfile(t)
+	follower := tests.CreateMockProfile(t)
+	stranger := tests.CreateMockProfile(t)
+	_, err := tests.MockProfileService().AddFollow(follower, owner)
+	tests.EndTestIfError(err, t)
+	tests.EndTestIfError(tests.MockProfileService().UpdatePrivacy(owner, true), t)
+	word := tests.UniqueName("private")
+	postID := tests.CreateMockPost(t, owner, tests.CreateMockCategory(t), tests.UniqueName("post"), word)
+	_, err = tests.MockCommentService().Create(owner, postID, 0, word)
+	tests.EndTestIfError(err, t)
+
+	for _, viewer := range []uint{0, stranger} {
+		tests.AssertEqu(0, len(searchPosts(word, domain.SearchFilter{ViewerID: viewer}, 1, t)), t)
+		_, err = searchService().Search(word, domain.SearchTypeComments, domain.SearchFilter{ViewerID: viewer}, 1)
+		tests.AssertEqu(service.ErrNotExistingEntity, err, t)
+	}
+
+	for _, viewer := range []uint{owner, follower} {
+		tests.AssertEqu(1, len(searchPosts(word, domain.SearchFilter{ViewerID: viewer}, 1, t)), t)
+		comments, err := searchService().Search(word, domain.SearchTypeComments, domain.SearchFilter{ViewerID: viewer}, 1)
+		tests.EndTestIfError(err, t)
+		tests.AssertEqu(1, len(comments), t)
+	}
+}
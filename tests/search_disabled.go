@@ -0,0 +1,6 @@
+//go:build !sqlite_fts5
+
+package tests
+
+// SQLite is built without FTS5 unless the sqlite_fts5 tag is set, search stays untested
+func runMockSQLiteSearchMigration() {}
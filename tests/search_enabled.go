@@ -0,0 +1,9 @@
+//go:build sqlite_fts5
+
+package tests
+
+// Creates the full-text search tables and indexes the rows left by previous runs
+func runMockSQLiteSearchMigration() {
+	mustRunSQLiteScript("search.sql")
+	mustRunSQLiteScript("search_rebuild.sql")
+}
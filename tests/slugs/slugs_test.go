@@ -0,0 +1,60 @@
+package slugs
+
+import (
+	"testing"
+
+	"github.com/AlejandroJorge/forum-rest-api/config"
+	"github.com/AlejandroJorge/forum-rest-api/service"
+	"github.com/AlejandroJorge/forum-rest-api/tests"
+)
+
+func TestMain(t *testing.M) {
+	tests.FixWorkingDir()
+	config.InitializeAll()
+	tests.RunMockSQLiteMigration()
+	t.Run()
+}
+
+func slugOf(postID uint, t *testing.T) string {
+	post, err := tests.MockPostService().GetByID(postID)
+	tests.EndTestIfError(err, t)
+
+	return post.Slug
+}
+
+func TestRepeatedTitlesGetSuffixedSlugs(t *testing.T) {
+	owner := tests.CreateMockProfile(t)
+	title := tests.UniqueName("Hello, World ")
+	base := slugOf(tests.CreateMockPost(t, owner, tests.CreateMockCategory(t), title, "content"), t)
+
+	tests.AssertEqu(base+"-2", slugOf(tests.CreateMockPost(t, owner, tests.CreateMockCategory(t), title, "content"), t), t)
+	tests.AssertEqu(base+"-3", slugOf(tests.CreateMockPost(t, owner, tests.CreateMockCategory(t), title, "content"), t), t)
+
+	// A title that already looks suffixed starts its own sequence
+	tests.AssertEqu(base+"-2-2", slugOf(tests.CreateMockPost(t, owner, tests.CreateMockCategory(t), title+" 2", "content"), t), t)
+}
+
+func TestRenamedPostsKeepTheirOldSlug(t *testing.T) {
+	postServ := tests.MockPostService()
+	owner := tests.CreateMockProfile(t)
+	title := tests.UniqueName("Renamed ")
+	first := tests.CreateMockPost(t, owner, tests.CreateMockCategory(t), title, "content")
+	second := tests.CreateMockPost(t, owner, tests.CreateMockCategory(t), title, "content")
+	base := slugOf(first, t)
+	tests.AssertEqu(base+"-2", slugOf(second, t), t)
+
+	tests.EndTestIfError(postServ.UpdateTitle(owner, second, tests.UniqueName("Other ")), t)
+
+	// The old slug still resolves and isn't handed out again
+	post, err := postServ.GetBySlug(base + "-2")
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(second, post.PostID, t)
+	tests.AssertEqu(base+"-3", slugOf(tests.CreateMockPost(t, owner, tests.CreateMockCategory(t), title, "content"), t), t)
+
+	// Saving the same title again keeps the post's own slug
+	tests.EndTestIfError(postServ.UpdateTitle(owner, first, title), t)
+	tests.AssertEqu(base, slugOf(first, t), t)
+
+	_, err = postServ.GetBySlug(tests.UniqueName("missing-"))
+	tests.AssertEqu(service.ErrNotExistingEntity, err, t)
+}
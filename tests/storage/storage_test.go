@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/storage"
+)
+
+const blobKey = "0123456789abcdef.png"
+
+// A stand-in for an S3-compatible endpoint, keeps objects of one bucket in memory and rejects unsigned requests
+func newFakeS3(t *testing.T, bucket string) *httptest.Server {
+	var mu sync.Mutex
+	objects := make(map[string][]byte)
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization := r.Header.Get("Authorization")
+		if !strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 Credential=access/") ||
+			!strings.Contains(authorization, "/us-east-1/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature=") {
+			t.Errorf("unexpected authorization %q", authorization)
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		key, ok := strings.CutPrefix(r.URL.Path, "/"+bucket+"/")
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, "<Error><Code>NoSuchBucket</Code></Error>")
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch r.Method {
+		case http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			sum := sha256.Sum256(body)
+			if r.Header.Get("X-Amz-Content-Sha256") != hex.EncodeToString(sum[:]) {
+				t.Errorf("payload hash doesn't match the body")
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			objects[key] = body
+		case http.MethodGet:
+			body, ok := objects[key]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write(body)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+}
+
+func testBlobStore(store domain.BlobStore, t *testing.T) {
+	_, err := store.Get(blobKey)
+	if err != storage.ErrBlobNotFound {
+		t.Fatalf("expected ErrBlobNotFound before storing, got %v", err)
+	}
+
+	data := []byte("not really a png")
+	err = store.Put(blobKey, "image/png", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = store.Put(blobKey, "image/png", data)
+	if err != nil {
+		t.Fatalf("storing the same key again failed: %v", err)
+	}
+
+	blob, err := store.Get(blobKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer blob.Close()
+
+	stored, err := io.ReadAll(blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(stored, data) {
+		t.Errorf("expected %q, got %q", data, stored)
+	}
+
+	for _, key := range []string{"../escape.png", ".hidden", "UPPER.png", "a/b.png"} {
+		err = store.Put(key, "image/png", data)
+		if err != storage.ErrInvalidKey {
+			t.Errorf("expected ErrInvalidKey storing %q, got %v", key, err)
+		}
+	}
+}
+
+func TestLocalBlobStore(t *testing.T) {
+	testBlobStore(storage.NewLocalBlobStore(t.TempDir()), t)
+}
+
+func TestS3BlobStore(t *testing.T) {
+	server := newFakeS3(t, "media")
+	defer server.Close()
+
+	testBlobStore(storage.NewS3BlobStore(server.URL, "media", "us-east-1", "access", "secret"), t)
+}
+
+func TestS3BlobStoreReportsFailures(t *testing.T) {
+	server := newFakeS3(t, "media")
+	defer server.Close()
+
+	store := storage.NewS3BlobStore(server.URL, "missing", "us-east-1", "access", "secret")
+	err := store.Put(blobKey, "image/png", []byte("data"))
+	if err == nil || !strings.Contains(err.Error(), "NoSuchBucket") {
+		t.Errorf("expected the S3 error to be reported, got %v", err)
+	}
+}
@@ -0,0 +1,164 @@
+package stream
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AlejandroJorge/forum-rest-api/config"
+	"github.com/AlejandroJorge/forum-rest-api/delivery"
+	"github.com/AlejandroJorge/forum-rest-api/delivery/controller"
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/events"
+	"github.com/AlejandroJorge/forum-rest-api/tests"
+	"github.com/gorilla/websocket"
+)
+
+type gatewayMessage struct {
+	Type    string        `json:"Type"`
+	PostID  uint          `json:"PostID"`
+	Event   *domain.Event `json:"Event"`
+	Message string        `json:"Message"`
+}
+
+// Serves the gateway on its own bus, the user is taken from the user query parameter instead of the auth cookie
+func startGateway(bus domain.EventBus) *httptest.Server {
+	gateway := controller.NewGatewayController(tests.MockPostService(), bus)
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var userID uint
+		fmt.Sscan(r.URL.Query().Get("user"), &userID)
+		gateway.Connect(w, delivery.WithAuthenticatedUser(r, userID))
+	}))
+}
+
+func dialGateway(server *httptest.Server, userID uint, t *testing.T) *websocket.Conn {
+	url := fmt.Sprintf("ws%s?user=%d", strings.TrimPrefix(server.URL, "http"), userID)
+	ws, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("Couldn't connect to the gateway: %v", err)
+	}
+
+	return ws
+}
+
+func sendToGateway(ws *websocket.Conn, msgType string, postID uint, t *testing.T) {
+	err := ws.WriteJSON(map[string]interface{}{"Type": msgType, "PostID": postID})
+	tests.EndTestIfError(err, t)
+}
+
+func readFromGateway(ws *websocket.Conn, t *testing.T) gatewayMessage {
+	ws.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var msg gatewayMessage
+	tests.EndTestIfError(ws.ReadJSON(&msg), t)
+
+	return msg
+}
+
+// Fails if anything arrives within wait, the connection can't be read again afterwards
+func expectNothingFromGateway(ws *websocket.Conn, wait time.Duration, t *testing.T) {
+	ws.SetReadDeadline(time.Now().Add(wait))
+	var msg gatewayMessage
+	err := ws.ReadJSON(&msg)
+	if err == nil {
+		t.Errorf("Expected no message, got '%+v'", msg)
+	}
+}
+
+func TestGatewaySubscriptionsFollowPostVisibility(t *testing.T) {
+	server := startGateway(events.NewMemoryBus(0))
+	defer server.Close()
+	owner := tests.CreateMockProfile(t)
+	follower := tests.CreateMockProfile(t)
+	stranger := tests.CreateMockProfile(t)
+	_, err := tests.MockProfileService().AddFollow(follower, owner)
+	tests.EndTestIfError(err, t)
+	tests.EndTestIfError(tests.MockProfileService().UpdatePrivacy(owner, true), t)
+	postID := tests.CreateMockPost(t, owner, tests.CreateMockCategory(t), tests.UniqueName("post"), "content")
+
+	strangerWS := dialGateway(server, stranger, t)
+	defer strangerWS.Close()
+	sendToGateway(strangerWS, "subscribe", postID, t)
+	msg := readFromGateway(strangerWS, t)
+	tests.AssertEqu("error", msg.Type, t)
+	tests.AssertEqu(postID, msg.PostID, t)
+	sendToGateway(strangerWS, "subscribe", postID+1000000, t)
+	tests.AssertEqu("Post doesn't exist", readFromGateway(strangerWS, t).Message, t)
+
+	followerWS := dialGateway(server, follower, t)
+	defer followerWS.Close()
+	sendToGateway(followerWS, "subscribe", postID, t)
+	msg = readFromGateway(followerWS, t)
+	tests.AssertEqu("subscribed", msg.Type, t)
+	tests.AssertEqu(postID, msg.PostID, t)
+}
+
+func TestGatewayLimitsSubscriptions(t *testing.T) {
+	server := startGateway(events.NewMemoryBus(0))
+	defer server.Close()
+	owner := tests.CreateMockProfile(t)
+	categoryID := tests.CreateMockCategory(t)
+	limit := int(config.GetParams().WSMaxSubscriptions)
+	postIDs := make([]uint, limit+1)
+	for i := range postIDs {
+		postIDs[i] = tests.CreateMockPost(t, owner, categoryID, tests.UniqueName("post"), "content")
+	}
+
+	ws := dialGateway(server, owner, t)
+	defer ws.Close()
+	for i, postID := range postIDs {
+		// Stays under the rate limit of the gateway
+		if i > 0 && i%9 == 0 {
+			time.Sleep(time.Second)
+		}
+		sendToGateway(ws, "subscribe", postID, t)
+		msg := readFromGateway(ws, t)
+		if i < limit {
+			tests.AssertEqu("subscribed", msg.Type, t)
+		} else {
+			tests.AssertEqu("Too many subscriptions", msg.Message, t)
+		}
+	}
+
+	sendToGateway(ws, "unsubscribe", postIDs[0], t)
+	tests.AssertEqu("unsubscribed", readFromGateway(ws, t).Type, t)
+	sendToGateway(ws, "subscribe", postIDs[limit], t)
+	tests.AssertEqu("subscribed", readFromGateway(ws, t).Type, t)
+}
+
+func TestGatewayTypingIsThrottledAndNotEchoed(t *testing.T) {
+	server := startGateway(events.NewMemoryBus(0))
+	defer server.Close()
+	typist := tests.CreateMockProfile(t)
+	reader := tests.CreateMockProfile(t)
+	postID := tests.CreateMockPost(t, typist, tests.CreateMockCategory(t), tests.UniqueName("post"), "content")
+
+	typistWS := dialGateway(server, typist, t)
+	defer typistWS.Close()
+	readerWS := dialGateway(server, reader, t)
+	defer readerWS.Close()
+
+	sendToGateway(typistWS, "typing", postID, t)
+	tests.AssertEqu("Not subscribed to the post", readFromGateway(typistWS, t).Message, t)
+
+	for _, ws := range []*websocket.Conn{typistWS, readerWS} {
+		sendToGateway(ws, "subscribe", postID, t)
+		tests.AssertEqu("subscribed", readFromGateway(ws, t).Type, t)
+	}
+
+	sendToGateway(typistWS, "typing", postID, t)
+	sendToGateway(typistWS, "typing", postID, t)
+
+	msg := readFromGateway(readerWS, t)
+	if msg.Event == nil {
+		t.Fatalf("Expected a typing event, got '%+v'", msg)
+	}
+	tests.AssertEqu(domain.EventPostTyping, msg.Event.Type, t)
+	tests.AssertEqu(float64(typist), msg.Event.Data.(map[string]interface{})["UserID"], t)
+
+	// The second notice came too soon and the typist never hears their own
+	expectNothingFromGateway(readerWS, 500*time.Millisecond, t)
+	expectNothingFromGateway(typistWS, 100*time.Millisecond, t)
+}
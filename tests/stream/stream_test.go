@@ -0,0 +1,99 @@
+package stream
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AlejandroJorge/forum-rest-api/config"
+	"github.com/AlejandroJorge/forum-rest-api/delivery/controller"
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/events"
+	"github.com/AlejandroJorge/forum-rest-api/tests"
+)
+
+func TestMain(t *testing.M) {
+	tests.FixWorkingDir()
+	config.InitializeAll()
+	tests.RunMockSQLiteMigration()
+	t.Run()
+}
+
+func TestReplayAfterLastEventID(t *testing.T) {
+	bus := events.NewMemoryBus(3)
+	for i := 1; i <= 4; i++ {
+		bus.Publish("followed", domain.EventPostCreated, i)
+		bus.Publish("other", domain.EventPostCreated, i)
+	}
+	bus.PublishEphemeral("followed", domain.EventPostTyping, 0)
+
+	// Only the last 3 events are kept, from those only the followed topic is replayed
+	missed, subscription := bus.Subscribe([]string{"followed"}, 1)
+	defer subscription.Unsubscribe()
+	tests.AssertEqu(1, len(missed), t)
+	tests.AssertEqu(uint64(7), missed[0].ID, t)
+	tests.AssertEqu(4, missed[0].Data, t)
+
+	missed, subscription = bus.Subscribe([]string{"followed"}, 0)
+	defer subscription.Unsubscribe()
+	tests.AssertEqu(0, len(missed), t)
+
+	missed, subscription = bus.Subscribe([]string{"followed"}, 7)
+	defer subscription.Unsubscribe()
+	tests.AssertEqu(0, len(missed), t)
+}
+
+func TestSubscribersGetNewEvents(t *testing.T) {
+	bus := events.NewMemoryBus(0)
+	_, subscription := bus.Subscribe([]string{"followed"}, 0)
+	defer subscription.Unsubscribe()
+
+	bus.Publish("other", domain.EventPostCreated, 1)
+	bus.Publish("followed", domain.EventPostCreated, 2)
+
+	event := <-subscription.Events()
+	tests.AssertEqu(2, event.Data, t)
+	tests.AssertEqu("followed", event.Topic, t)
+}
+
+// Opens a stream of the profile that the client leaves right after the missed events are written
+func openStream(bus domain.EventBus, profileID uint, lastEventID string) *httptest.ResponseRecorder {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	request := httptest.NewRequest("GET", fmt.Sprintf("/stream?profile=%d", profileID), nil).WithContext(ctx)
+	if lastEventID != "" {
+		request.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	recorder := httptest.NewRecorder()
+	controller.NewStreamController(tests.MockPostService(), bus).Subscribe(recorder, request)
+	return recorder
+}
+
+func TestStreamResumesFromLastEventIDHeader(t *testing.T) {
+	profileID := tests.CreateMockProfile(t)
+	topic := domain.ProfileTopic(profileID)
+	bus := events.NewMemoryBus(10)
+	bus.Publish(topic, domain.EventProfileFollowed, "first")
+	bus.Publish(topic, domain.EventProfileFollowed, "second")
+	bus.Publish(topic, domain.EventProfileFollowed, "third")
+
+	recorder := openStream(bus, profileID, "1")
+	tests.AssertEqu(http.StatusOK, recorder.Code, t)
+	tests.AssertEqu("text/event-stream", recorder.Header().Get("Content-Type"), t)
+	expected := "id: 2\nevent: profile.followed\ndata: \"second\"\n\n" +
+		"id: 3\nevent: profile.followed\ndata: \"third\"\n\n"
+	tests.AssertEqu(expected, recorder.Body.String(), t)
+
+	recorder = openStream(bus, profileID, "")
+	tests.AssertEqu(http.StatusOK, recorder.Code, t)
+	tests.AssertEqu("", recorder.Body.String(), t)
+
+	recorder = openStream(bus, profileID, "latest")
+	tests.AssertEqu(http.StatusBadRequest, recorder.Code, t)
+	tests.AssertEqu(false, strings.Contains(recorder.Body.String(), "id:"), t)
+}
@@ -0,0 +1,76 @@
+package threads
+
+import (
+	"testing"
+
+	"github.com/AlejandroJorge/forum-rest-api/config"
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/service"
+	"github.com/AlejandroJorge/forum-rest-api/tests"
+)
+
+func TestMain(t *testing.M) {
+	tests.FixWorkingDir()
+	config.InitializeAll()
+	tests.RunMockSQLiteMigration()
+	t.Run()
+}
+
+func TestRepliesNestUpToMaxDepth(t *testing.T) {
+	commentServ := tests.MockCommentService()
+	user := tests.CreateMockProfile(t)
+	postID := tests.CreateMockPost(t, user, tests.CreateMockCategory(t), tests.UniqueName("post"), "content")
+
+	parentID := uint(0)
+	for depth := uint(0); depth <= config.GetParams().MaxCommentDepth; depth++ {
+		id, err := commentServ.Create(user, postID, parentID, "reply")
+		tests.EndTestIfError(err, t)
+
+		comment, err := commentServ.GetByID(id)
+		tests.EndTestIfError(err, t)
+		tests.AssertEqu(depth, comment.Depth, t)
+		tests.AssertEqu(parentID, comment.ParentID, t)
+		parentID = id
+	}
+
+	_, err := commentServ.Create(user, postID, parentID, "too deep")
+	tests.AssertEqu(service.ErrMaxDepthExceeded, err, t)
+
+	// Replies must belong to the post of their parent
+	_, err = commentServ.Create(user, tests.CreateMockPost(t, user, tests.CreateMockCategory(t), tests.UniqueName("post"), "content"), parentID, "elsewhere")
+	tests.AssertEqu(service.ErrIncorrectParameters, err, t)
+}
+
+func TestThreadsNestReplies(t *testing.T) {
+	commentServ := tests.MockCommentService()
+	user := tests.CreateMockProfile(t)
+	postID := tests.CreateMockPost(t, user, tests.CreateMockCategory(t), tests.UniqueName("post"), "content")
+
+	first, err := commentServ.Create(user, postID, 0, "first")
+	tests.EndTestIfError(err, t)
+	second, err := commentServ.Create(user, postID, 0, "second")
+	tests.EndTestIfError(err, t)
+	reply, err := commentServ.Create(user, postID, first, "reply")
+	tests.EndTestIfError(err, t)
+	_, err = commentServ.Create(user, postID, reply, "nested")
+	tests.EndTestIfError(err, t)
+
+	threads, err := commentServ.GetThreadsByPost(postID, domain.CommentOrderOldest)
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(2, len(threads), t)
+	tests.AssertEqu(first, threads[0].ID, t)
+	tests.AssertEqu(second, threads[1].ID, t)
+	tests.AssertEqu(1, len(threads[0].Replies), t)
+	tests.AssertEqu(reply, threads[0].Replies[0].ID, t)
+	tests.AssertEqu(1, len(threads[0].Replies[0].Replies), t)
+	tests.AssertEqu(uint(1), threads[0].ReplyCount, t)
+
+	threads, err = commentServ.GetThreadsByPost(postID, domain.CommentOrderNewest)
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(second, threads[0].ID, t)
+
+	replies, err := commentServ.GetReplies(first)
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(1, len(replies), t)
+	tests.AssertEqu(reply, replies[0].ID, t)
+}
@@ -0,0 +1,127 @@
+package visibility
+
+import (
+	"testing"
+
+	"github.com/AlejandroJorge/forum-rest-api/config"
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/events"
+	"github.com/AlejandroJorge/forum-rest-api/repository"
+	"github.com/AlejandroJorge/forum-rest-api/service"
+	"github.com/AlejandroJorge/forum-rest-api/tests"
+)
+
+func TestMain(t *testing.M) {
+	tests.FixWorkingDir()
+	config.InitializeAll()
+	tests.RunMockSQLiteMigration()
+	t.Run()
+}
+
+// Returns the IDs of the posts in the category the viewer can see
+func categoryPosts(viewerID, categoryID uint, t *testing.T) map[uint]bool {
+	posts, err := tests.MockPostService().GetPopularInCategory(viewerID, categoryID, domain.PopularPeriodAllTime, domain.PostSortNew)
+	if err == service.ErrNotExistingEntity {
+		return map[uint]bool{}
+	}
+	tests.EndTestIfError(err, t)
+
+	ids := make(map[uint]bool)
+	for _, post := range posts {
+		ids[post.PostID] = true
+	}
+
+	return ids
+}
+
+func follows(followerID, followedID uint, t *testing.T) bool {
+	profiles, err := tests.MockProfileService().GetFollowsByID(followerID)
+	if err == service.ErrNotExistingEntity {
+		return false
+	}
+	tests.EndTestIfError(err, t)
+
+	for _, profile := range profiles {
+		if profile.UserID == followedID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestBlockStopsInteractions(t *testing.T) {
+	db := tests.MockSQLiteDatabase()
+	profileServ := tests.MockProfileService()
+	conversationServ := service.NewConversationService(repository.NewSQLiteConversationRepository(db), repository.NewSQLiteUserRepository(db),
+		repository.NewSQLiteProfileRepository(db), events.DefaultBus())
+	blocker := tests.CreateMockProfile(t)
+	blocked := tests.CreateMockProfile(t)
+	postID := tests.CreateMockPost(t, blocker, tests.CreateMockCategory(t), tests.UniqueName("post"), "content")
+
+	_, err := profileServ.AddFollow(blocker, blocked)
+	tests.EndTestIfError(err, t)
+	_, err = profileServ.AddFollow(blocked, blocker)
+	tests.EndTestIfError(err, t)
+
+	tests.EndTestIfError(profileServ.Block(blocker, blocked), t)
+	tests.AssertEqu(service.ErrAlreadyExisting, profileServ.Block(blocker, blocked), t)
+
+	// Blocking removes the follows both ways and they can't come back
+	tests.AssertEqu(false, follows(blocker, blocked, t), t)
+	tests.AssertEqu(false, follows(blocked, blocker, t), t)
+	_, err = profileServ.AddFollow(blocked, blocker)
+	tests.AssertEqu(service.ErrNotAuthorized, err, t)
+	_, err = profileServ.AddFollow(blocker, blocked)
+	tests.AssertEqu(service.ErrNotAuthorized, err, t)
+
+	_, err = tests.MockCommentService().Create(blocked, postID, 0, "comment")
+	tests.AssertEqu(service.ErrNotAuthorized, err, t)
+	_, err = conversationServ.Start(blocked, []uint{blocker})
+	tests.AssertEqu(service.ErrNotAuthorized, err, t)
+	_, err = conversationServ.Start(blocker, []uint{blocked})
+	tests.AssertEqu(service.ErrNotAuthorized, err, t)
+
+	tests.EndTestIfError(profileServ.Unblock(blocker, blocked), t)
+	_, err = profileServ.AddFollow(blocked, blocker)
+	tests.EndTestIfError(err, t)
+}
+
+func TestMutedProfilesAreHidden(t *testing.T) {
+	profileServ := tests.MockProfileService()
+	commentServ := tests.MockCommentService()
+	viewer := tests.CreateMockProfile(t)
+	muted := tests.CreateMockProfile(t)
+	other := tests.CreateMockProfile(t)
+	categoryID := tests.CreateMockCategory(t)
+	mutedPost := tests.CreateMockPost(t, muted, categoryID, tests.UniqueName("post"), "content")
+	otherPost := tests.CreateMockPost(t, other, categoryID, tests.UniqueName("post"), "content")
+
+	tests.EndTestIfError(profileServ.Mute(viewer, muted), t)
+	tests.AssertEqu(service.ErrAlreadyExisting, profileServ.Mute(viewer, muted), t)
+
+	visible := categoryPosts(viewer, categoryID, t)
+	tests.AssertEqu(false, visible[mutedPost], t)
+	tests.AssertEqu(true, visible[otherPost], t)
+
+	// Only the muter stops seeing them
+	visible = categoryPosts(0, categoryID, t)
+	tests.AssertEqu(true, visible[mutedPost], t)
+	tests.AssertEqu(true, visible[otherPost], t)
+
+	_, err := profileServ.AddFollow(viewer, muted)
+	tests.EndTestIfError(err, t)
+	_, err = tests.MockPostService().GetFeed(viewer, domain.FeedModeLatest, "")
+	tests.AssertEqu(service.ErrNotExistingEntity, err, t)
+
+	_, err = commentServ.Create(muted, otherPost, 0, "muted comment")
+	tests.EndTestIfError(err, t)
+	_, err = commentServ.Create(other, otherPost, 0, "comment")
+	tests.EndTestIfError(err, t)
+	comments, err := commentServ.GetByPost(otherPost, domain.CommentOrderOldest)
+	tests.EndTestIfError(err, t)
+	comments, err = commentServ.HideMuted(viewer, comments)
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(1, len(comments), t)
+	tests.AssertEqu(other, comments[0].UserID, t)
+}
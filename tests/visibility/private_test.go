@@ -0,0 +1,145 @@
+package visibility
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AlejandroJorge/forum-rest-api/repository"
+	"github.com/AlejandroJorge/forum-rest-api/service"
+	"github.com/AlejandroJorge/forum-rest-api/tests"
+)
+
+func TestPrivatePostsOnlyReachApprovedFollowers(t *testing.T) {
+	postServ := tests.MockPostService()
+	profileServ := tests.MockProfileService()
+	owner := tests.CreateMockProfile(t)
+	follower := tests.CreateMockProfile(t)
+	stranger := tests.CreateMockProfile(t)
+	categoryID := tests.CreateMockCategory(t)
+	tests.EndTestIfError(profileServ.UpdatePrivacy(owner, true), t)
+	postID := tests.CreateMockPost(t, owner, categoryID, tests.UniqueName("post"), "content")
+
+	requested, err := profileServ.AddFollow(follower, owner)
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(true, requested, t)
+
+	// A pending request doesn't give access yet
+	for _, viewer := range []uint{0, stranger, follower} {
+		tests.AssertEqu(service.ErrNotAuthorized, postServ.CheckVisible(viewer, owner), t)
+		tests.AssertEqu(false, categoryPosts(viewer, categoryID, t)[postID], t)
+		_, err = postServ.GetByUser(viewer, owner)
+		tests.AssertEqu(service.ErrNotExistingEntity, err, t)
+	}
+
+	tests.EndTestIfError(profileServ.ApproveFollowRequest(owner, follower), t)
+	tests.EndTestIfError(postServ.CheckVisible(follower, owner), t)
+	tests.EndTestIfError(postServ.CheckVisible(owner, owner), t)
+	tests.AssertEqu(true, categoryPosts(follower, categoryID, t)[postID], t)
+	tests.AssertEqu(true, categoryPosts(owner, categoryID, t)[postID], t)
+	posts, err := postServ.GetByUser(follower, owner)
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(1, len(posts), t)
+
+	tests.AssertEqu(service.ErrNotAuthorized, postServ.CheckVisible(stranger, owner), t)
+	tests.AssertEqu(false, categoryPosts(stranger, categoryID, t)[postID], t)
+}
+
+func TestMakingProfilePublicAcceptsRequests(t *testing.T) {
+	profileServ := tests.MockProfileService()
+	owner := tests.CreateMockProfile(t)
+	requester := tests.CreateMockProfile(t)
+	tests.EndTestIfError(profileServ.UpdatePrivacy(owner, true), t)
+
+	requested, err := profileServ.AddFollow(requester, owner)
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(true, requested, t)
+	tests.AssertEqu(false, follows(requester, owner, t), t)
+
+	tests.EndTestIfError(profileServ.UpdatePrivacy(owner, false), t)
+	tests.AssertEqu(true, follows(requester, owner, t), t)
+	tests.EndTestIfError(tests.MockPostService().CheckVisible(0, owner), t)
+}
+
+func TestPrivateLikesAndMentionsOnlyReachApprovedFollowers(t *testing.T) {
+	db := tests.MockSQLiteDatabase()
+	likeServ := service.NewLikeService(repository.NewSQLitePostRepository(db), repository.NewSQLiteCommentRepository(db))
+	mentionServ := service.NewMentionService(repository.NewSQLiteProfileRepository(db), repository.NewSQLitePostRepository(db), repository.NewSQLiteCommentRepository(db))
+	owner := tests.CreateMockProfile(t)
+	follower := tests.CreateMockProfile(t)
+	stranger := tests.CreateMockProfile(t)
+	_, err := tests.MockProfileService().AddFollow(follower, owner)
+	tests.EndTestIfError(err, t)
+	tests.EndTestIfError(tests.MockProfileService().UpdatePrivacy(owner, true), t)
+
+	followerProfile, err := repository.NewSQLiteProfileRepository(db).GetByUserID(follower)
+	tests.EndTestIfError(err, t)
+	postID, err := tests.MockPostService().Create(owner, tests.CreateMockCategory(t), tests.UniqueName("post"), "description", "hi @"+followerProfile.TagName, nil, nil)
+	tests.EndTestIfError(err, t)
+	commentID, err := tests.MockCommentService().Create(owner, postID, 0, "again @"+followerProfile.TagName)
+	tests.EndTestIfError(err, t)
+	tests.EndTestIfError(tests.MockPostService().Vote(follower, postID, 1), t)
+	tests.EndTestIfError(tests.MockCommentService().Vote(follower, commentID, 1), t)
+
+	for _, viewer := range []uint{0, stranger} {
+		_, err = likeServ.GetByUser(viewer, follower)
+		tests.AssertEqu(service.ErrNotExistingEntity, err, t)
+
+		mentions, err := mentionServ.GetByTagName(viewer, followerProfile.TagName)
+		tests.EndTestIfError(err, t)
+		tests.AssertEqu(0, len(mentions.Posts), t)
+		tests.AssertEqu(0, len(mentions.Comments), t)
+	}
+
+	likes, err := likeServ.GetByUser(follower, follower)
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(1, len(likes.Posts), t)
+	tests.AssertEqu(1, len(likes.Comments), t)
+
+	mentions, err := mentionServ.GetByTagName(follower, followerProfile.TagName)
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(1, len(mentions.Posts), t)
+	tests.AssertEqu(1, len(mentions.Comments), t)
+}
+
+func TestCommentsOnPrivatePostsOnlyReachApprovedFollowers(t *testing.T) {
+	postServ := tests.MockPostService()
+	commentServ := tests.MockCommentService()
+	owner := tests.CreateMockProfile(t)
+	follower := tests.CreateMockProfile(t)
+	stranger := tests.CreateMockProfile(t)
+	_, err := tests.MockProfileService().AddFollow(follower, owner)
+	tests.EndTestIfError(err, t)
+	tests.EndTestIfError(tests.MockProfileService().UpdatePrivacy(owner, true), t)
+	postID := tests.CreateMockPost(t, owner, tests.CreateMockCategory(t), tests.UniqueName("post"), "content")
+	commentID, err := commentServ.Create(owner, postID, 0, "first")
+	tests.EndTestIfError(err, t)
+
+	_, err = commentServ.Create(stranger, postID, 0, "hello")
+	tests.AssertEqu(service.ErrNotAuthorized, err, t)
+	tests.AssertEqu(service.ErrNotAuthorized, postServ.Vote(stranger, postID, 1), t)
+	tests.AssertEqu(service.ErrNotAuthorized, commentServ.Vote(stranger, commentID, 1), t)
+	_, err = commentServ.GetByUser(stranger, owner)
+	tests.AssertEqu(service.ErrNotExistingEntity, err, t)
+
+	_, err = commentServ.Create(follower, postID, commentID, "hello")
+	tests.EndTestIfError(err, t)
+	tests.EndTestIfError(postServ.Vote(follower, postID, 1), t)
+	tests.EndTestIfError(commentServ.Vote(follower, commentID, 1), t)
+	comments, err := commentServ.GetByUser(follower, owner)
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(1, len(comments), t)
+
+	// Anonymous requests to the comment routes are refused
+	for _, path := range []string{
+		fmt.Sprintf("/api/v1/posts/%d/comments", postID),
+		fmt.Sprintf("/api/v1/comments/%d", commentID),
+		fmt.Sprintf("/api/v1/comments/%d/replies", commentID),
+		fmt.Sprintf("/api/v1/comments/%d/revisions", commentID),
+	} {
+		recorder := httptest.NewRecorder()
+		tests.MockRouter().ServeHTTP(recorder, httptest.NewRequest("GET", path, nil))
+		tests.AssertEqu(http.StatusForbidden, recorder.Code, t)
+	}
+}
@@ -0,0 +1,87 @@
+package votes
+
+import (
+	"testing"
+
+	"github.com/AlejandroJorge/forum-rest-api/config"
+	"github.com/AlejandroJorge/forum-rest-api/domain"
+	"github.com/AlejandroJorge/forum-rest-api/service"
+	"github.com/AlejandroJorge/forum-rest-api/tests"
+)
+
+func TestMain(t *testing.M) {
+	tests.FixWorkingDir()
+	config.InitializeAll()
+	tests.RunMockSQLiteMigration()
+	t.Run()
+}
+
+// Checks the counters of the post and the vote the voter sees on it
+func assertPostVotes(postID, voterID uint, upvotes, downvotes uint, myVote int, t *testing.T) {
+	post, err := tests.MockPostService().GetByID(postID)
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(upvotes, post.Upvotes, t)
+	tests.AssertEqu(downvotes, post.Downvotes, t)
+	tests.AssertEqu(int(upvotes)-int(downvotes), post.Score, t)
+
+	posts := []domain.Post{post}
+	tests.EndTestIfError(tests.MockPostService().AddViewer(voterID, posts), t)
+	tests.AssertEqu(myVote, posts[0].MyVote, t)
+}
+
+// Checks the counters of the comment and the vote the voter sees on it
+func assertCommentVotes(commentID, voterID uint, upvotes, downvotes uint, myVote int, t *testing.T) {
+	comment, err := tests.MockCommentService().GetByID(commentID)
+	tests.EndTestIfError(err, t)
+	tests.AssertEqu(upvotes, comment.Upvotes, t)
+	tests.AssertEqu(downvotes, comment.Downvotes, t)
+	tests.AssertEqu(int(upvotes)-int(downvotes), comment.Score, t)
+
+	comments := []domain.Comment{comment}
+	tests.EndTestIfError(tests.MockCommentService().AddViewer(voterID, comments), t)
+	tests.AssertEqu(myVote, comments[0].MyVote, t)
+}
+
+func TestPostVotes(t *testing.T) {
+	postServ := tests.MockPostService()
+	voter := tests.CreateMockProfile(t)
+	postID := tests.CreateMockPost(t, tests.CreateMockProfile(t), tests.CreateMockCategory(t), tests.UniqueName("post"), "content")
+
+	// Voting the same way again changes nothing
+	tests.EndTestIfError(postServ.Vote(voter, postID, 1), t)
+	tests.EndTestIfError(postServ.Vote(voter, postID, 1), t)
+	assertPostVotes(postID, voter, 1, 0, 1, t)
+
+	tests.EndTestIfError(postServ.Vote(voter, postID, -1), t)
+	assertPostVotes(postID, voter, 0, 1, -1, t)
+
+	tests.EndTestIfError(postServ.Vote(voter, postID, 0), t)
+	assertPostVotes(postID, voter, 0, 0, 0, t)
+
+	for _, value := range []int{2, -2} {
+		tests.AssertEqu(service.ErrIncorrectParameters, postServ.Vote(voter, postID, value), t)
+	}
+	assertPostVotes(postID, voter, 0, 0, 0, t)
+}
+
+func TestCommentVotes(t *testing.T) {
+	commentServ := tests.MockCommentService()
+	author := tests.CreateMockProfile(t)
+	voter := tests.CreateMockProfile(t)
+	postID := tests.CreateMockPost(t, author, tests.CreateMockCategory(t), tests.UniqueName("post"), "content")
+	commentID, err := commentServ.Create(author, postID, 0, "comment")
+	tests.EndTestIfError(err, t)
+
+	tests.EndTestIfError(commentServ.Vote(voter, commentID, 1), t)
+	tests.EndTestIfError(commentServ.Vote(voter, commentID, 1), t)
+	assertCommentVotes(commentID, voter, 1, 0, 1, t)
+
+	tests.EndTestIfError(commentServ.Vote(voter, commentID, -1), t)
+	assertCommentVotes(commentID, voter, 0, 1, -1, t)
+
+	tests.EndTestIfError(commentServ.Vote(voter, commentID, 0), t)
+	assertCommentVotes(commentID, voter, 0, 0, 0, t)
+
+	tests.AssertEqu(service.ErrIncorrectParameters, commentServ.Vote(voter, commentID, 5), t)
+	assertCommentVotes(commentID, voter, 0, 0, 0, t)
+}
@@ -0,0 +1,120 @@
+package util
+
+import "strings"
+
+// Changed regions whose lines would need more comparisons than this are shown as replaced whole
+const maxDiffComparisons = 1 << 22
+
+// Returns a line based diff, unchanged lines start with "  ", removed ones with "- " and added ones with "+ "
+// Uses Hirschberg's algorithm, so memory grows with the amount of lines instead of their product
+func LineDiff(before, after string) string {
+	a := strings.Split(before, "\n")
+	b := strings.Split(after, "\n")
+
+	return strings.Join(diffLines(nil, a, b), "\n")
+}
+
+// Appends the diff of a and b to lines
+func diffLines(lines, a, b []string) []string {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		lines = append(lines, "  "+a[prefix])
+		prefix++
+	}
+	a, b = a[prefix:], b[prefix:]
+
+	suffix := 0
+	for suffix < len(a) && suffix < len(b) && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	common := a[len(a)-suffix:]
+	a, b = a[:len(a)-suffix], b[:len(b)-suffix]
+
+	switch {
+	case len(a) == 0:
+		lines = appendPrefixed(lines, "+ ", b)
+	case len(b) == 0:
+		lines = appendPrefixed(lines, "- ", a)
+	case len(a)*len(b) > maxDiffComparisons:
+		lines = appendPrefixed(lines, "- ", a)
+		lines = appendPrefixed(lines, "+ ", b)
+	case len(a) == 1:
+		kept := -1
+		for j, line := range b {
+			if line == a[0] {
+				kept = j
+				break
+			}
+		}
+		if kept < 0 {
+			lines = append(lines, "- "+a[0])
+			lines = appendPrefixed(lines, "+ ", b)
+		} else {
+			lines = appendPrefixed(lines, "+ ", b[:kept])
+			lines = append(lines, "  "+a[0])
+			lines = appendPrefixed(lines, "+ ", b[kept+1:])
+		}
+	default:
+		middle := len(a) / 2
+		forward := lcsLengths(a[:middle], b, false)
+		backward := lcsLengths(a[middle:], b, true)
+
+		// Splits b where the common subsequences of both halves of a add up to the longest
+		split := 0
+		for j := range forward {
+			if forward[j]+backward[j] > forward[split]+backward[split] {
+				split = j
+			}
+		}
+
+		lines = diffLines(lines, a[:middle], b[:split])
+		lines = diffLines(lines, a[middle:], b[split:])
+	}
+
+	return appendPrefixed(lines, "  ", common)
+}
+
+// Returns the lengths of the longest common subsequences of a and every prefix of b, or every suffix of b when reversed
+// The j-th length pairs with b[:j] forwards and with b[j:] backwards
+func lcsLengths(a, b []string, reversed bool) []int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for i := range a {
+		line := a[i]
+		if reversed {
+			line = a[len(a)-1-i]
+		}
+
+		for j := 1; j <= len(b); j++ {
+			other := b[j-1]
+			if reversed {
+				other = b[len(b)-j]
+			}
+
+			if line == other {
+				current[j] = previous[j-1] + 1
+			} else if previous[j] >= current[j-1] {
+				current[j] = previous[j]
+			} else {
+				current[j] = current[j-1]
+			}
+		}
+		previous, current = current, previous
+	}
+
+	if reversed {
+		for i, j := 0, len(previous)-1; i < j; i, j = i+1, j-1 {
+			previous[i], previous[j] = previous[j], previous[i]
+		}
+	}
+
+	return previous
+}
+
+func appendPrefixed(lines []string, prefix string, added []string) []string {
+	for _, line := range added {
+		lines = append(lines, prefix+line)
+	}
+
+	return lines
+}
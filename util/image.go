@@ -0,0 +1,75 @@
+package util
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+
+	"golang.org/x/image/draw"
+)
+
+var ErrImageTooLarge = errors.New("The image has more pixels than allowed")
+
+// Returns the image and its format, the dimensions are checked before decoding so huge images aren't allocated
+func DecodeImage(data []byte, maxPixels uint) (image.Image, string, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", err
+	}
+	if uint(config.Width)*uint(config.Height) > maxPixels {
+		return nil, "", ErrImageTooLarge
+	}
+
+	var img image.Image
+	switch format {
+	case "jpeg":
+		img, err = jpeg.Decode(bytes.NewReader(data))
+	case "png":
+		img, err = png.Decode(bytes.NewReader(data))
+	case "gif":
+		img, err = gif.Decode(bytes.NewReader(data))
+	default:
+		return nil, "", image.ErrFormat
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	return img, format, nil
+}
+
+// Encodes JPEG images as JPEG and everything else as PNG, metadata like EXIF isn't carried over
+func EncodeImage(img image.Image, format string) ([]byte, string, error) {
+	var buf bytes.Buffer
+	if format == "jpeg" {
+		err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 90})
+		return buf.Bytes(), "image/jpeg", err
+	}
+
+	err := png.Encode(&buf, img)
+	return buf.Bytes(), "image/png", err
+}
+
+// Scales the image down to fit in a square of side maxSide, smaller images are returned as they are
+func ResizeImage(img image.Image, maxSide uint) image.Image {
+	bounds := img.Bounds()
+	width, height := uint(bounds.Dx()), uint(bounds.Dy())
+	if width <= maxSide && height <= maxSide {
+		return img
+	}
+
+	if width >= height {
+		height = max(1, height*maxSide/width)
+		width = maxSide
+	} else {
+		width = max(1, width*maxSide/height)
+		height = maxSide
+	}
+
+	resized := image.NewRGBA(image.Rect(0, 0, int(width), int(height)))
+	draw.CatmullRom.Scale(resized, resized.Bounds(), img, bounds, draw.Src, nil)
+	return resized
+}
@@ -0,0 +1,44 @@
+package util
+
+import (
+	"bytes"
+	"html"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	goldmarkhtml "github.com/yuin/goldmark/renderer/html"
+)
+
+// Raw HTML in the content is never rendered
+var markdown = goldmark.New(
+	goldmark.WithExtensions(extension.Strikethrough, extension.Linkify),
+	goldmark.WithRendererOptions(goldmarkhtml.WithHardWraps()),
+)
+
+// Links keep only safe schemes and get rel="nofollow"
+var markdownPolicy = newMarkdownPolicy()
+
+func newMarkdownPolicy() *bluemonday.Policy {
+	policy := bluemonday.NewPolicy()
+	policy.AllowElements("p", "br", "hr", "strong", "em", "del", "code", "pre", "blockquote", "ul", "ol", "li",
+		"h1", "h2", "h3", "h4", "h5", "h6")
+	policy.AllowAttrs("start").Matching(bluemonday.Integer).OnElements("ol")
+	policy.AllowAttrs("href").OnElements("a")
+	policy.AllowURLSchemes("http", "https", "mailto")
+	policy.RequireParseableURLs(true)
+	policy.RequireNoFollowOnLinks(true)
+
+	return policy
+}
+
+// Returns the content rendered as HTML with only allowlisted tags, images and raw HTML are left out
+func RenderMarkdown(content string) string {
+	var buf bytes.Buffer
+	err := markdown.Convert([]byte(content), &buf)
+	if err != nil {
+		return "<p>" + html.EscapeString(content) + "</p>"
+	}
+
+	return markdownPolicy.Sanitize(buf.String())
+}
@@ -1,6 +1,9 @@
 package util
 
-import "regexp"
+import (
+	"regexp"
+	"unicode/utf8"
+)
 
 func IsEmailFormat(email string) bool {
 	emailRegex := `^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`
@@ -17,3 +20,36 @@ func IsAlphanumeric(input string) bool {
 
 	return regex.MatchString(alphanumericRegex)
 }
+
+// A match found in a text, Offset and Length count characters instead of bytes
+type TextMatch struct {
+	Text   string
+	Offset uint
+	Length uint
+}
+
+// Returns every @tagname in order, addresses like user@mail.com aren't mentions
+func FindMentions(content string) []TextMatch {
+	return findPrefixed(content, `(?:^|[^a-zA-Z0-9_.@])(@[a-zA-Z][a-zA-Z0-9]*)`)
+}
+
+// Returns every #hashtag in order, HTML entities like &#39; aren't hashtags
+func FindHashtags(content string) []TextMatch {
+	return findPrefixed(content, `(?:^|[^a-zA-Z0-9_&#])(#[a-zA-Z][a-zA-Z0-9_]*)`)
+}
+
+func findPrefixed(content string, pattern string) []TextMatch {
+	regex := regexp.MustCompile(pattern)
+
+	var matches []TextMatch
+	for _, indexes := range regex.FindAllStringSubmatchIndex(content, -1) {
+		start, end := indexes[2], indexes[3]
+		matches = append(matches, TextMatch{
+			Text:   content[start:end],
+			Offset: uint(utf8.RuneCountInString(content[:start])),
+			Length: uint(utf8.RuneCountInString(content[start:end])),
+		})
+	}
+
+	return matches
+}
@@ -0,0 +1,41 @@
+package util
+
+import "strings"
+
+const maxSlugLength = 80
+
+const maxTagLength = 30
+
+// Returns a lowercase URL friendly version of text made of ASCII letters, digits and dashes
+func Slugify(text string) string {
+	slug := slugify(text, maxSlugLength)
+	if slug == "" {
+		return "post"
+	}
+
+	return slug
+}
+
+// Returns the canonical form of a tag name, empty if nothing usable remains
+func NormalizeTag(name string) string {
+	return slugify(name, maxTagLength)
+}
+
+func slugify(text string, maxLength int) string {
+	var builder strings.Builder
+	lastWasDash := true
+	for _, r := range strings.ToLower(text) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			builder.WriteRune(r)
+			lastWasDash = false
+		} else if !lastWasDash {
+			builder.WriteRune('-')
+			lastWasDash = true
+		}
+		if builder.Len() >= maxLength {
+			break
+		}
+	}
+
+	return strings.Trim(builder.String(), "-")
+}